@@ -2,9 +2,11 @@
 //
 // 实际能力分布在各子包中：
 //   - 对称加密：aes（GCM/CBC/CFB）、chacha（XChaCha20-Poly1305）、stream（流式 AEAD）
-//   - 非对称：rsa（OAEP/PSS）、ed（Ed25519）、ecdsa、ecdh、hpke、mlkem（后量子）
+//   - 非对称：rsa（OAEP/PSS）、rsa/blind（RFC 9474 盲签名）、ed（Ed25519）、ecdsa、ecdh、hpke、ecies（Apple/Tink 互通）、mlkem（后量子）、mldsa（后量子签名）、xwing（X25519+ML-KEM-768 混合 KEM）
+//   - 安全通道：noise（Noise 协议 XX/IK/NK 握手，包装 net.Conn）、e2e（X3DH + Double Ratchet 端到端加密会话）、spake2（RFC 9382 口令认证密钥交换）
+//   - 证书/SSH：x509ca（CSR 生成与进程内 CA）、sshsig（ssh-keygen -Y 兼容签名）
 //   - 摘要/认证：sha256、hmac、md5、sha1
-//   - 口令/派生：hash（argon2id、bcrypt）、hkdf
+//   - 口令/派生：hash（argon2id、bcrypt）、hkdf、kdf（NIST SP 800-108/56C）、seed（种子分层派生与助记词）
 //   - 编码/工具：base64、sqids、sign
 //
 // 新系统优先选用现代默认能力（AES-GCM/ChaCha20-Poly1305、RSA-OAEP/PSS、Ed25519、SHA-256+）。
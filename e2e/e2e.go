@@ -0,0 +1,96 @@
+// Package e2e 实现端到端加密会话：X3DH 初始密钥协商 + Double Ratchet（双棘轮），
+// 协议结构遵循 Signal 公开规范，提供前向安全与入侵后恢复（post-compromise security）。
+//
+// 角色与流程：
+//
+//  1. 接收方 Bob 用 Ed25519 身份密钥签署一把 X25519 签名预密钥（GenerateSignedPreKey），
+//     另可生成一批一次性预密钥（GenerateOneTimePreKeys），把公开部分组成 PreKeyBundle 上传服务器。
+//  2. 发起方 Alice 取得 Bundle，InitiateSession 校验签名并完成 X3DH，立即可以 Encrypt；
+//     在收到 Bob 的第一条回复之前，每条消息都携带 X3DH 头部（PreKey 消息）。
+//  3. Bob 收到第一条消息后用 PeekPreKeyMessage 查出所用预密钥 ID，AcceptSession 建立会话并
+//     得到第一条明文；之后双方都只用 Session.Encrypt/Decrypt。一次性预密钥用后应立即删除。
+//
+// 身份密钥为 Ed25519 密钥，参与 X3DH 时经 ed.ToX25519PrivateKey/ToX25519PublicKey 转换为 X25519；
+// 身份公钥的真实性（安全码比对等）由应用负责。密钥派生使用 hkdf（HKDF-SHA256），链密钥用
+// HMAC-SHA256，消息加密为 ChaCha20-Poly1305（密钥与 nonce 由消息密钥派生，每把消息密钥只用一次）。
+//
+// Session 可用 MarshalBinary 序列化、UnmarshalSession 还原；序列化结果包含全部会话密钥，
+// 须加密存储。Session 不是并发安全的，同一会话的调用须由调用方串行化。
+package e2e
+
+import (
+	"crypto/ecdh"
+	"errors"
+	"io"
+
+	encryecdh "github.com/gtkit/encry/ecdh"
+)
+
+const (
+	// DefaultMaxSkip 为默认的单链最大跳过消息数，也是保存的跳过消息密钥上限。
+	DefaultMaxSkip = 1000
+
+	keySize = 32
+)
+
+var (
+	// ErrInvalidIdentityKey 表示身份私钥或公钥非法。
+	ErrInvalidIdentityKey = errors.New("e2e: invalid identity key")
+	// ErrInvalidPreKey 表示预密钥为 nil、公钥非法或与消息中的 ID 不一致。
+	ErrInvalidPreKey = errors.New("e2e: invalid prekey")
+	// ErrInvalidSignature 表示 Bundle 中签名预密钥的签名无效。
+	ErrInvalidSignature = errors.New("e2e: invalid signed prekey signature")
+	// ErrInvalidMessage 表示消息格式非法、认证失败或重放（对应消息密钥已用过）。
+	ErrInvalidMessage = errors.New("e2e: invalid message")
+	// ErrNotPreKeyMessage 表示传给 AcceptSession/PeekPreKeyMessage 的不是携带 X3DH 头部的首条消息。
+	ErrNotPreKeyMessage = errors.New("e2e: not a prekey message")
+	// ErrTooManySkipped 表示消息序号跳过的数量超过 WithMaxSkip 上限。
+	ErrTooManySkipped = errors.New("e2e: too many skipped messages")
+	// ErrInvalidState 表示序列化的会话状态非法或版本不受支持。
+	ErrInvalidState = errors.New("e2e: invalid session state")
+)
+
+// Option 用于定制会话参数（Functional Options）。
+type Option func(*options)
+
+type options struct {
+	maxSkip int
+	rand    io.Reader
+}
+
+// WithMaxSkip 指定单条接收链最多跳过的消息数（乱序、丢包），同时限制保存的跳过消息密钥数量，
+// 超出时丢弃最早的；默认 DefaultMaxSkip。n <= 0 时使用默认值。
+func WithMaxSkip(n int) Option {
+	return func(o *options) { o.maxSkip = n }
+}
+
+// WithRand 指定生成临时密钥与棘轮密钥的随机源（每个私钥读取 32 字节），默认 crypto/rand。
+// 仅用于测试等需要可复现会话的场景。
+func WithRand(r io.Reader) Option {
+	return func(o *options) { o.rand = r }
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	if o.maxSkip <= 0 {
+		o.maxSkip = DefaultMaxSkip
+	}
+	return o
+}
+
+func (o *options) generateKey() (*ecdh.PrivateKey, error) {
+	if o.rand == nil {
+		return encryecdh.GenerateX25519()
+	}
+	seed := make([]byte, encryecdh.X25519SeedSize)
+	defer clear(seed)
+	if _, err := io.ReadFull(o.rand, seed); err != nil {
+		return nil, err
+	}
+	return encryecdh.X25519FromSeed(seed)
+}
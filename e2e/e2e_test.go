@@ -0,0 +1,544 @@
+package e2e_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"fmt"
+	"testing"
+
+	"github.com/gtkit/encry/e2e"
+	"github.com/gtkit/encry/ed"
+	"github.com/stretchr/testify/require"
+)
+
+type party struct {
+	identity ed25519.PrivateKey
+	spk      *e2e.SignedPreKey
+	opks     map[uint32]*e2e.OneTimePreKey
+}
+
+func newParty(t *testing.T, oneTimePreKeys int) *party {
+	t.Helper()
+	_, identity, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+	spk, err := e2e.GenerateSignedPreKey(identity, 7)
+	require.NoError(t, err)
+	keys, err := e2e.GenerateOneTimePreKeys(100, oneTimePreKeys)
+	require.NoError(t, err)
+	p := &party{identity: identity, spk: spk, opks: map[uint32]*e2e.OneTimePreKey{}}
+	for _, k := range keys {
+		p.opks[k.ID] = k
+	}
+	return p
+}
+
+// bundle 模拟服务器分发：有一次性预密钥时附带一把。
+func (p *party) bundle() *e2e.PreKeyBundle {
+	var opk *e2e.OneTimePreKey
+	for _, k := range p.opks {
+		opk = k
+		break
+	}
+	return e2e.NewPreKeyBundle(p.identity.Public().(ed25519.PublicKey), p.spk, opk)
+}
+
+// accept 模拟接收方处理首条消息：按头部查找预密钥，用后删除一次性预密钥。
+func (p *party) accept(t *testing.T, msg []byte, opts ...e2e.Option) (*e2e.Session, []byte) {
+	t.Helper()
+	info, err := e2e.PeekPreKeyMessage(msg)
+	require.NoError(t, err)
+	require.Equal(t, uint32(7), info.SignedPreKeyID)
+	var opk *e2e.OneTimePreKey
+	if info.HasOneTimePreKey {
+		opk = p.opks[info.OneTimePreKeyID]
+		require.NotNil(t, opk)
+	}
+	session, plainText, err := e2e.AcceptSession(p.identity, p.spk, opk, msg, opts...)
+	require.NoError(t, err)
+	delete(p.opks, info.OneTimePreKeyID)
+	return session, plainText
+}
+
+// step 是脚本中的一步：send 为 "A"/"B" 时由该方发送编号为 id 的消息，否则投递编号 id 的消息。
+type step struct {
+	send string
+	id   int
+}
+
+func send(from string, ids ...int) []step {
+	steps := make([]step, 0, len(ids))
+	for _, id := range ids {
+		steps = append(steps, step{send: from, id: id})
+	}
+	return steps
+}
+
+func deliver(ids ...int) []step {
+	steps := make([]step, 0, len(ids))
+	for _, id := range ids {
+		steps = append(steps, step{id: id})
+	}
+	return steps
+}
+
+func script(parts ...[]step) []step {
+	var steps []step
+	for _, p := range parts {
+		steps = append(steps, p...)
+	}
+	return steps
+}
+
+func TestConversationScripts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		opks    int
+		persist bool
+		steps   []step
+	}{
+		{
+			name:  "按序一问一答",
+			opks:  1,
+			steps: script(send("A", 1), deliver(1), send("B", 2), deliver(2), send("A", 3), deliver(3), send("B", 4), deliver(4)),
+		},
+		{
+			name:  "无一次性预密钥",
+			steps: script(send("A", 1, 2), deliver(1, 2), send("B", 3), deliver(3)),
+		},
+		{
+			name:  "首条消息之前的 PreKey 消息先到",
+			opks:  1,
+			steps: script(send("A", 1, 2, 3), deliver(3, 1), send("B", 4), deliver(4, 2), send("A", 5), deliver(5)),
+		},
+		{
+			name:  "同一链内乱序",
+			opks:  2,
+			steps: script(send("A", 1), deliver(1), send("B", 2, 3, 4, 5), deliver(5, 3, 2, 4)),
+		},
+		{
+			name: "跨棘轮步骤乱序（依赖 PN）",
+			opks: 1,
+			steps: script(
+				send("A", 1), deliver(1),
+				send("B", 2, 3, 4), deliver(2),
+				send("A", 5), deliver(5),
+				send("B", 6, 7), deliver(7, 3, 6, 4),
+				send("A", 8), deliver(8),
+			),
+		},
+		{
+			name: "双方同时发送",
+			opks: 1,
+			steps: script(
+				send("A", 1), deliver(1),
+				send("A", 2), send("B", 3), send("A", 4), send("B", 5),
+				deliver(3, 2, 5, 4),
+				send("B", 6), send("A", 7), deliver(7, 6),
+			),
+		},
+		{
+			name:    "每步序列化并还原会话",
+			opks:    1,
+			persist: true,
+			steps: script(
+				send("A", 1, 2), deliver(2, 1),
+				send("B", 3, 4, 5), deliver(4),
+				send("A", 6), deliver(6, 5, 3),
+				send("B", 7), deliver(7),
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			runScript(t, tt.opks, tt.persist, tt.steps)
+		})
+	}
+}
+
+func runScript(t *testing.T, opks int, persist bool, steps []step) {
+	t.Helper()
+	alice := newParty(t, 0)
+	bob := newParty(t, opks)
+
+	sessions := map[string]*e2e.Session{}
+	var err error
+	sessions["A"], err = e2e.InitiateSession(alice.identity, bob.bundle())
+	require.NoError(t, err)
+
+	type inFlight struct {
+		to  string
+		msg []byte
+	}
+	messages := map[int]inFlight{}
+	for i, st := range steps {
+		if st.send != "" {
+			s := sessions[st.send]
+			require.NotNil(t, s, "step %d: %s has no session yet", i, st.send)
+			msg, err := s.Encrypt(fmt.Appendf(nil, "message %d", st.id))
+			require.NoError(t, err)
+			to := "B"
+			if st.send == "B" {
+				to = "A"
+			}
+			messages[st.id] = inFlight{to: to, msg: msg}
+		} else {
+			m, ok := messages[st.id]
+			require.True(t, ok, "step %d: message %d not sent", i, st.id)
+			var plainText []byte
+			if sessions[m.to] == nil {
+				sessions[m.to], plainText = bob.accept(t, m.msg)
+				require.Equal(t, alice.identity.Public(), sessions[m.to].PeerIdentity())
+			} else {
+				plainText, err = sessions[m.to].Decrypt(m.msg)
+				require.NoError(t, err, "step %d: message %d", i, st.id)
+			}
+			require.Equal(t, fmt.Sprintf("message %d", st.id), string(plainText))
+
+			// 重放同一条消息必须失败。
+			_, err = sessions[m.to].Decrypt(m.msg)
+			require.ErrorIs(t, err, e2e.ErrInvalidMessage)
+			delete(messages, st.id)
+		}
+
+		if persist {
+			for name, s := range sessions {
+				if s == nil {
+					continue
+				}
+				state, err := s.MarshalBinary()
+				require.NoError(t, err)
+				sessions[name], err = e2e.UnmarshalSession(state)
+				require.NoError(t, err)
+			}
+		}
+	}
+	require.Empty(t, messages, "script left undelivered messages")
+}
+
+func TestPreKeyMessagesUntilReply(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newParty(t, 0), newParty(t, 1)
+	a, err := e2e.InitiateSession(alice.identity, bob.bundle())
+	require.NoError(t, err)
+
+	m1, err := a.Encrypt([]byte("one"))
+	require.NoError(t, err)
+	m2, err := a.Encrypt([]byte("two"))
+	require.NoError(t, err)
+	_, err = e2e.PeekPreKeyMessage(m2)
+	require.NoError(t, err) // 未收到回复前仍携带 X3DH 头部
+
+	b, _ := bob.accept(t, m1)
+	_, err = b.Decrypt(m2)
+	require.NoError(t, err)
+	reply, err := b.Encrypt([]byte("reply"))
+	require.NoError(t, err)
+	_, err = e2e.PeekPreKeyMessage(reply)
+	require.ErrorIs(t, err, e2e.ErrNotPreKeyMessage)
+
+	_, err = a.Decrypt(reply)
+	require.NoError(t, err)
+	m3, err := a.Encrypt([]byte("three"))
+	require.NoError(t, err)
+	_, err = e2e.PeekPreKeyMessage(m3)
+	require.ErrorIs(t, err, e2e.ErrNotPreKeyMessage)
+	require.Less(t, len(m3), len(m2))
+
+	// 发起方不接受 PreKey 消息。
+	_, err = a.Decrypt(m1)
+	require.ErrorIs(t, err, e2e.ErrInvalidMessage)
+}
+
+func TestWithRandReproducible(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newParty(t, 0), newParty(t, 0)
+	bundle := bob.bundle()
+	encrypt := func() []byte {
+		s, err := e2e.InitiateSession(alice.identity, bundle, e2e.WithRand(bytes.NewReader(bytes.Repeat([]byte{0x42}, 64))))
+		require.NoError(t, err)
+		msg, err := s.Encrypt([]byte("same"))
+		require.NoError(t, err)
+		return msg
+	}
+	require.Equal(t, encrypt(), encrypt())
+
+	_, err := e2e.InitiateSession(alice.identity, bundle, e2e.WithRand(bytes.NewReader(nil)))
+	require.Error(t, err)
+}
+
+func TestTamperedMessagesLeaveStateIntact(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newParty(t, 0), newParty(t, 1)
+	a, err := e2e.InitiateSession(alice.identity, bob.bundle())
+	require.NoError(t, err)
+	first, err := a.Encrypt([]byte("hi"))
+	require.NoError(t, err)
+	b, _ := bob.accept(t, first)
+
+	msg, err := b.Encrypt([]byte("payload"))
+	require.NoError(t, err)
+	before, err := a.MarshalBinary()
+	require.NoError(t, err)
+
+	for i := range msg {
+		tampered := append([]byte(nil), msg...)
+		tampered[i] ^= 0x80
+		_, err := a.Decrypt(tampered)
+		require.Error(t, err, "byte %d", i)
+	}
+	_, err = a.Decrypt(msg[:len(msg)-1])
+	require.Error(t, err)
+	_, err = a.Decrypt(nil)
+	require.ErrorIs(t, err, e2e.ErrInvalidMessage)
+
+	after, err := a.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, before, after)
+	plainText, err := a.Decrypt(msg)
+	require.NoError(t, err)
+	require.Equal(t, "payload", string(plainText))
+}
+
+func TestMaxSkip(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newParty(t, 0), newParty(t, 0)
+	a, err := e2e.InitiateSession(alice.identity, bob.bundle(), e2e.WithMaxSkip(3))
+	require.NoError(t, err)
+	first, err := a.Encrypt([]byte("hi"))
+	require.NoError(t, err)
+	b, _ := bob.accept(t, first, e2e.WithMaxSkip(3))
+
+	var msgs [][]byte
+	for i := range 6 {
+		m, err := a.Encrypt(fmt.Appendf(nil, "%d", i))
+		require.NoError(t, err)
+		msgs = append(msgs, m)
+	}
+
+	// 跳过 4 条超过上限，状态不变。
+	_, err = b.Decrypt(msgs[4])
+	require.ErrorIs(t, err, e2e.ErrTooManySkipped)
+
+	// 跳过 3 条在上限内，被跳过的消息随后仍可解密。
+	plainText, err := b.Decrypt(msgs[3])
+	require.NoError(t, err)
+	require.Equal(t, "3", string(plainText))
+	for _, i := range []int{1, 0, 2, 5, 4} {
+		plainText, err := b.Decrypt(msgs[i])
+		require.NoError(t, err, i)
+		require.Equal(t, fmt.Sprint(i), string(plainText))
+	}
+}
+
+func TestSkippedKeysEvictOldest(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newParty(t, 0), newParty(t, 0)
+	a, err := e2e.InitiateSession(alice.identity, bob.bundle())
+	require.NoError(t, err)
+	first, err := a.Encrypt([]byte("hi"))
+	require.NoError(t, err)
+	b, _ := bob.accept(t, first, e2e.WithMaxSkip(2))
+
+	var msgs [][]byte
+	for i := range 5 {
+		m, err := a.Encrypt(fmt.Appendf(nil, "%d", i))
+		require.NoError(t, err)
+		msgs = append(msgs, m)
+	}
+	// 依次收到 #2、#4：共跳过 #0、#1、#3，只保留最近的 2 把（#1、#3）。
+	for _, i := range []int{2, 4, 1, 3} {
+		_, err := b.Decrypt(msgs[i])
+		require.NoError(t, err, i)
+	}
+	_, err = b.Decrypt(msgs[0])
+	require.ErrorIs(t, err, e2e.ErrInvalidMessage)
+}
+
+// 认证失败的消息在会话副本上淘汰并清零了旧的跳过密钥，原会话中的这些密钥不受影响。
+func TestFailedDecryptKeepsSkippedKeys(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newParty(t, 0), newParty(t, 0)
+	a, err := e2e.InitiateSession(alice.identity, bob.bundle())
+	require.NoError(t, err)
+	first, err := a.Encrypt([]byte("hi"))
+	require.NoError(t, err)
+	b, _ := bob.accept(t, first, e2e.WithMaxSkip(2))
+
+	var msgs [][]byte
+	for i := range 5 {
+		m, err := a.Encrypt(fmt.Appendf(nil, "%d", i))
+		require.NoError(t, err)
+		msgs = append(msgs, m)
+	}
+	_, err = b.Decrypt(msgs[2])
+	require.NoError(t, err)
+
+	tampered := append([]byte(nil), msgs[4]...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = b.Decrypt(tampered)
+	require.Error(t, err)
+
+	for _, i := range []int{0, 1, 4, 3} {
+		plainText, err := b.Decrypt(msgs[i])
+		require.NoError(t, err, i)
+		require.Equal(t, fmt.Sprint(i), string(plainText))
+	}
+}
+
+// 泄露某一时刻的会话状态后，攻击者只能解密到下一轮 DH 棘轮为止：
+// 对方用新棘轮密钥回复后，新消息无法再用泄露的状态解密。
+func TestPostCompromiseSecurity(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newParty(t, 0), newParty(t, 1)
+	a, err := e2e.InitiateSession(alice.identity, bob.bundle())
+	require.NoError(t, err)
+	first, err := a.Encrypt([]byte("hi"))
+	require.NoError(t, err)
+	b, _ := bob.accept(t, first)
+
+	leaked, err := b.MarshalBinary()
+	require.NoError(t, err)
+	attacker, err := e2e.UnmarshalSession(leaked)
+	require.NoError(t, err)
+
+	exchange := func(from, to *e2e.Session, text string) []byte {
+		msg, err := from.Encrypt([]byte(text))
+		require.NoError(t, err)
+		plainText, err := to.Decrypt(msg)
+		require.NoError(t, err)
+		require.Equal(t, text, string(plainText))
+		return msg
+	}
+
+	exchange(b, a, "reply")
+	// 泄露状态中含 Bob 当前的棘轮私钥，Alice 的下一条消息仍可被解密。
+	exposed := exchange(a, b, "still exposed")
+	plainText, err := attacker.Decrypt(exposed)
+	require.NoError(t, err)
+	require.Equal(t, "still exposed", string(plainText))
+
+	// Bob 用新生成的棘轮密钥回复后，会话自愈。
+	exchange(b, a, "new ratchet key")
+	healed := exchange(a, b, "after healing")
+	_, err = attacker.Decrypt(healed)
+	require.ErrorIs(t, err, e2e.ErrInvalidMessage)
+}
+
+func TestBundleVerify(t *testing.T) {
+	t.Parallel()
+
+	alice, bob, mallory := newParty(t, 0), newParty(t, 1), newParty(t, 0)
+	require.NoError(t, bob.bundle().Verify())
+
+	tests := []struct {
+		name   string
+		mutate func(*e2e.PreKeyBundle)
+		want   error
+	}{
+		{"签名被篡改", func(b *e2e.PreKeyBundle) { b.SignedPreKeySignature[0] ^= 1 }, e2e.ErrInvalidSignature},
+		{"签名预密钥被替换", func(b *e2e.PreKeyBundle) { b.SignedPreKey = mallory.spk.PrivateKey.PublicKey().Bytes() }, e2e.ErrInvalidSignature},
+		{"身份公钥被替换", func(b *e2e.PreKeyBundle) { b.IdentityKey = mallory.identity.Public().(ed25519.PublicKey) }, e2e.ErrInvalidSignature},
+		{"身份公钥非法", func(b *e2e.PreKeyBundle) { b.IdentityKey = make([]byte, 31) }, e2e.ErrInvalidIdentityKey},
+		{"一次性预密钥非法", func(b *e2e.PreKeyBundle) { b.OneTimePreKey = []byte{1, 2, 3} }, e2e.ErrInvalidPreKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b := bob.bundle()
+			tt.mutate(b)
+			require.ErrorIs(t, b.Verify(), tt.want)
+			_, err := e2e.InitiateSession(alice.identity, b)
+			require.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	_, err := e2e.InitiateSession(alice.identity, nil)
+	require.ErrorIs(t, err, e2e.ErrInvalidPreKey)
+	_, err = e2e.InitiateSession(alice.identity[:10], bob.bundle())
+	require.ErrorIs(t, err, e2e.ErrInvalidIdentityKey)
+	_, err = e2e.GenerateSignedPreKey(nil, 1)
+	require.ErrorIs(t, err, e2e.ErrInvalidIdentityKey)
+	_, err = e2e.GenerateOneTimePreKeys(1, -1)
+	require.ErrorIs(t, err, e2e.ErrInvalidPreKey)
+}
+
+func TestAcceptSessionErrors(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newParty(t, 0), newParty(t, 1)
+	bundle := bob.bundle()
+	a, err := e2e.InitiateSession(alice.identity, bundle)
+	require.NoError(t, err)
+	msg, err := a.Encrypt([]byte("hi"))
+	require.NoError(t, err)
+	opk := bob.opks[bundle.OneTimePreKeyID]
+	otherSPK, err := e2e.GenerateSignedPreKey(bob.identity, 8)
+	require.NoError(t, err)
+	otherOPKs, err := e2e.GenerateOneTimePreKeys(bundle.OneTimePreKeyID, 1)
+	require.NoError(t, err)
+
+	_, _, err = e2e.AcceptSession(bob.identity, otherSPK, opk, msg)
+	require.ErrorIs(t, err, e2e.ErrInvalidPreKey)
+	_, _, err = e2e.AcceptSession(bob.identity, nil, opk, msg)
+	require.ErrorIs(t, err, e2e.ErrInvalidPreKey)
+	_, _, err = e2e.AcceptSession(bob.identity, bob.spk, nil, msg)
+	require.ErrorIs(t, err, e2e.ErrInvalidPreKey)
+	_, _, err = e2e.AcceptSession(bob.identity[:5], bob.spk, opk, msg)
+	require.ErrorIs(t, err, e2e.ErrInvalidIdentityKey)
+
+	// ID 相同但私钥不对（或身份密钥不对）时 X3DH 结果不同，首条消息认证失败。
+	_, _, err = e2e.AcceptSession(bob.identity, bob.spk, otherOPKs[0], msg)
+	require.ErrorIs(t, err, e2e.ErrInvalidMessage)
+	_, _, err = e2e.AcceptSession(alice.identity, bob.spk, opk, msg)
+	require.ErrorIs(t, err, e2e.ErrInvalidMessage)
+
+	b, _, err := e2e.AcceptSession(bob.identity, bob.spk, opk, msg)
+	require.NoError(t, err)
+	reply, err := b.Encrypt([]byte("reply"))
+	require.NoError(t, err)
+	_, _, err = e2e.AcceptSession(bob.identity, bob.spk, opk, reply)
+	require.ErrorIs(t, err, e2e.ErrNotPreKeyMessage)
+	_, err = e2e.PeekPreKeyMessage([]byte{9, 9})
+	require.ErrorIs(t, err, e2e.ErrInvalidMessage)
+}
+
+func TestUnmarshalSessionErrors(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := newParty(t, 0), newParty(t, 0)
+	a, err := e2e.InitiateSession(alice.identity, bob.bundle())
+	require.NoError(t, err)
+	state, err := a.MarshalBinary()
+	require.NoError(t, err)
+
+	_, err = e2e.UnmarshalSession(nil)
+	require.ErrorIs(t, err, e2e.ErrInvalidState)
+	_, err = e2e.UnmarshalSession(state[:len(state)-1])
+	require.ErrorIs(t, err, e2e.ErrInvalidState)
+	_, err = e2e.UnmarshalSession(append(state, 0))
+	require.ErrorIs(t, err, e2e.ErrInvalidState)
+	bad := append([]byte(nil), state...)
+	bad[0] = 2
+	_, err = e2e.UnmarshalSession(bad)
+	require.ErrorIs(t, err, e2e.ErrInvalidState)
+	bad = append([]byte(nil), state...)
+	bad[len(bad)-1] = 0xff // 跳过密钥数
+	_, err = e2e.UnmarshalSession(bad)
+	require.ErrorIs(t, err, e2e.ErrInvalidState)
+
+	restored, err := e2e.UnmarshalSession(state)
+	require.NoError(t, err)
+	again, err := restored.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, state, again)
+}
@@ -0,0 +1,34 @@
+package e2e_test
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/gtkit/encry/e2e"
+	"github.com/gtkit/encry/ed"
+)
+
+func Example() {
+	// Bob：生成身份密钥与预密钥，把 Bundle 发布到服务器。
+	bobPub, bobIdentity, _ := ed.GenerateKeyPair()
+	signedPreKey, _ := e2e.GenerateSignedPreKey(bobIdentity, 1)
+	oneTimePreKeys, _ := e2e.GenerateOneTimePreKeys(1, 10)
+	bundle := e2e.NewPreKeyBundle(bobPub, signedPreKey, oneTimePreKeys[0])
+
+	// Alice：取得 Bundle，建立会话并发送首条消息。
+	_, aliceIdentity, _ := ed.GenerateKeyPair()
+	alice, _ := e2e.InitiateSession(aliceIdentity, bundle)
+	first, _ := alice.Encrypt([]byte("hi bob"))
+
+	// Bob：按首条消息头部找到预密钥，建立会话。
+	info, _ := e2e.PeekPreKeyMessage(first)
+	bob, plainText, _ := e2e.AcceptSession(bobIdentity, signedPreKey, oneTimePreKeys[info.OneTimePreKeyID-1], first)
+	fmt.Println(string(plainText), info.IdentityKey.Equal(aliceIdentity.Public().(ed25519.PublicKey)))
+
+	reply, _ := bob.Encrypt([]byte("hi alice"))
+	plainText, _ = alice.Decrypt(reply)
+	fmt.Println(string(plainText))
+	// Output:
+	// hi bob true
+	// hi alice
+}
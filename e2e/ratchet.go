@@ -0,0 +1,311 @@
+package e2e
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/gtkit/encry/hkdf"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// 消息格式：版本(1) || 类型(1) || [X3DH 头部] || 棘轮头部 || AEAD 密文；
+// 棘轮头部 = 发送方棘轮公钥(32) || PN(4) || N(4)，AEAD 的关联数据为 AD || 消息中密文之前的全部字节。
+const (
+	messageVersion byte = 1
+
+	messageTypePreKey byte = 1
+	messageTypeNormal byte = 2
+
+	ratchetHeaderSize = keySize + 4 + 4
+
+	rootInfo    = "encry e2e ratchet v1"
+	messageInfo = "encry e2e message v1"
+)
+
+// Session 是一个 Double Ratchet 会话，由 InitiateSession/AcceptSession 创建或 UnmarshalSession 还原。
+type Session struct {
+	opts      *options
+	initiator bool
+	// peer 为对端 Ed25519 身份公钥；ad 为 X3DH 关联数据 IK_A || IK_B。
+	peer []byte
+	ad   []byte
+	// preKeyHeader：发起方在收到首条回复前随每条消息发送；接收方用于识别重复的首消息头部。
+	preKeyHeader []byte
+
+	rootKey    []byte
+	dhs        *ecdh.PrivateKey
+	dhr        *ecdh.PublicKey
+	cks, ckr   []byte
+	ns, nr, pn uint32
+	skipped    []skippedKey
+}
+
+// skippedKey 是因乱序或丢包而提前派生、尚未使用的消息密钥。
+type skippedKey struct {
+	dh  [keySize]byte
+	n   uint32
+	key []byte
+}
+
+type message struct {
+	preKeyHeader []byte
+	dh           []byte
+	pn, n        uint32
+	// header 为密文之前的全部字节，参与 AEAD 认证。
+	header     []byte
+	cipherText []byte
+}
+
+func parseMessage(msg []byte) (*message, error) {
+	if len(msg) < 2 || msg[0] != messageVersion {
+		return nil, ErrInvalidMessage
+	}
+	m := &message{}
+	rest := msg[2:]
+	switch msg[1] {
+	case messageTypePreKey:
+		if len(rest) < preKeyHeaderSize {
+			return nil, ErrInvalidMessage
+		}
+		m.preKeyHeader, rest = rest[:preKeyHeaderSize], rest[preKeyHeaderSize:]
+	case messageTypeNormal:
+	default:
+		return nil, ErrInvalidMessage
+	}
+	if len(rest) < ratchetHeaderSize+chacha20poly1305.Overhead {
+		return nil, ErrInvalidMessage
+	}
+	m.dh = rest[:keySize]
+	m.pn = binary.BigEndian.Uint32(rest[keySize:])
+	m.n = binary.BigEndian.Uint32(rest[keySize+4:])
+	headerLen := len(msg) - len(rest) + ratchetHeaderSize
+	m.header, m.cipherText = msg[:headerLen], msg[headerLen:]
+	return m, nil
+}
+
+// PeerIdentity 返回对端的 Ed25519 身份公钥。
+func (s *Session) PeerIdentity() ed25519.PublicKey {
+	return bytes.Clone(s.peer)
+}
+
+// Encrypt 加密一条消息并推进发送链；每条消息使用独立的消息密钥。
+func (s *Session) Encrypt(plainText []byte) ([]byte, error) {
+	chainKey, messageKey := kdfChain(s.cks)
+	defer clear(messageKey)
+
+	size := 2 + ratchetHeaderSize + len(plainText) + chacha20poly1305.Overhead
+	if s.initiator && s.preKeyHeader != nil {
+		size += preKeyHeaderSize
+	}
+	out := make([]byte, 0, size)
+	if s.initiator && s.preKeyHeader != nil {
+		out = append(out, messageVersion, messageTypePreKey)
+		out = append(out, s.preKeyHeader...)
+	} else {
+		out = append(out, messageVersion, messageTypeNormal)
+	}
+	out = append(out, s.dhs.PublicKey().Bytes()...)
+	out = binary.BigEndian.AppendUint32(out, s.pn)
+	out = binary.BigEndian.AppendUint32(out, s.ns)
+
+	sealed, err := seal(messageKey, s.ad, out, plainText)
+	if err != nil {
+		return nil, err
+	}
+	s.cks = chainKey
+	s.ns++
+	return sealed, nil
+}
+
+// Decrypt 解密一条消息。乱序到达的消息按序号派生并保存被跳过的消息密钥，之后仍可解密；
+// 任何错误（篡改、重放、跳过过多）都不会改变会话状态。
+func (s *Session) Decrypt(msg []byte) ([]byte, error) {
+	m, err := parseMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	if m.preKeyHeader != nil && (s.initiator || !bytes.Equal(m.preKeyHeader, s.preKeyHeader)) {
+		return nil, ErrInvalidMessage
+	}
+
+	if i := s.findSkipped(m.dh, m.n); i >= 0 {
+		plainText, err := open(s.skipped[i].key, s.ad, m.header, m.cipherText)
+		if err != nil {
+			return nil, err
+		}
+		clear(s.skipped[i].key)
+		s.skipped = append(s.skipped[:i:i], s.skipped[i+1:]...)
+		s.received()
+		return plainText, nil
+	}
+
+	// 在副本上推进棘轮，认证成功后才提交。
+	next := s.clone()
+	if next.dhr == nil || !bytes.Equal(m.dh, next.dhr.Bytes()) {
+		if err := next.skipMessageKeys(m.pn); err != nil {
+			return nil, err
+		}
+		if err := next.ratchetReceive(m.dh); err != nil {
+			return nil, err
+		}
+	}
+	if err := next.skipMessageKeys(m.n); err != nil {
+		return nil, err
+	}
+	chainKey, messageKey := kdfChain(next.ckr)
+	defer clear(messageKey)
+	plainText, err := open(messageKey, next.ad, m.header, m.cipherText)
+	if err != nil {
+		return nil, err
+	}
+	next.ckr = chainKey
+	next.nr++
+	for _, k := range s.skipped {
+		clear(k.key)
+	}
+	*s = *next
+	s.received()
+	return plainText, nil
+}
+
+// received 在成功解密对端消息后调用：发起方由此确认对端已建立会话，不再附带 X3DH 头部。
+func (s *Session) received() {
+	if s.initiator {
+		s.preKeyHeader = nil
+	}
+}
+
+// clone 复制会话用于试探性解密；跳过的消息密钥逐个复制，副本淘汰并清零密钥时不影响原会话。
+func (s *Session) clone() *Session {
+	c := *s
+	c.skipped = make([]skippedKey, len(s.skipped))
+	for i, k := range s.skipped {
+		c.skipped[i] = skippedKey{dh: k.dh, n: k.n, key: bytes.Clone(k.key)}
+	}
+	return &c
+}
+
+func (s *Session) findSkipped(dh []byte, n uint32) int {
+	for i := range s.skipped {
+		if s.skipped[i].n == n && bytes.Equal(s.skipped[i].dh[:], dh) {
+			return i
+		}
+	}
+	return -1
+}
+
+// skipMessageKeys 把当前接收链推进到序号 until，保存途经的消息密钥。
+func (s *Session) skipMessageKeys(until uint32) error {
+	if s.ckr == nil || until <= s.nr {
+		return nil
+	}
+	if uint64(until-s.nr) > uint64(s.opts.maxSkip) {
+		return ErrTooManySkipped
+	}
+	var dh [keySize]byte
+	copy(dh[:], s.dhr.Bytes())
+	for s.nr < until {
+		chainKey, messageKey := kdfChain(s.ckr)
+		s.skipped = append(s.skipped, skippedKey{dh: dh, n: s.nr, key: messageKey})
+		s.ckr = chainKey
+		s.nr++
+	}
+	if extra := len(s.skipped) - s.opts.maxSkip; extra > 0 {
+		for _, k := range s.skipped[:extra] {
+			clear(k.key)
+		}
+		s.skipped = append([]skippedKey(nil), s.skipped[extra:]...)
+	}
+	return nil
+}
+
+// ratchetReceive 执行一次 DH 棘轮：用对端新的棘轮公钥派生接收链，再生成本方新棘轮密钥派生发送链。
+func (s *Session) ratchetReceive(dh []byte) error {
+	pub, err := ecdh.X25519().NewPublicKey(dh)
+	if err != nil {
+		return ErrInvalidMessage
+	}
+	s.pn, s.ns, s.nr = s.ns, 0, 0
+	s.dhr = pub
+	if s.rootKey, s.ckr, err = s.kdfRoot(); err != nil {
+		return err
+	}
+	if s.dhs, err = s.opts.generateKey(); err != nil {
+		return err
+	}
+	return s.ratchetSend()
+}
+
+// ratchetSend 用本方当前棘轮私钥与 dhr 从根密钥派生新的发送链。
+func (s *Session) ratchetSend() error {
+	var err error
+	s.rootKey, s.cks, err = s.kdfRoot()
+	return err
+}
+
+// kdfRoot 为 KDF_RK：HKDF(salt = 根密钥, ikm = DH(dhs, dhr))，输出新根密钥与链密钥。
+func (s *Session) kdfRoot() ([]byte, []byte, error) {
+	shared, err := s.dhs.ECDH(s.dhr)
+	if err != nil {
+		return nil, nil, ErrInvalidMessage
+	}
+	defer clear(shared)
+	out, err := hkdf.Derive(shared, s.rootKey, rootInfo, 2*keySize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out[:keySize], out[keySize:], nil
+}
+
+// kdfChain 为 KDF_CK：消息密钥 = HMAC(ck, 0x01)，下一链密钥 = HMAC(ck, 0x02)。
+func kdfChain(chainKey []byte) ([]byte, []byte) {
+	mac := hmac.New(sha256.New, chainKey)
+	mac.Write([]byte{0x01})
+	messageKey := mac.Sum(nil)
+	mac.Reset()
+	mac.Write([]byte{0x02})
+	return mac.Sum(nil), messageKey
+}
+
+// messageAEAD 由消息密钥派生 ChaCha20-Poly1305 密钥与 nonce；每把消息密钥只加密一条消息。
+func messageAEAD(messageKey []byte) ([]byte, []byte, error) {
+	out, err := hkdf.Derive(messageKey, nil, messageInfo, chacha20poly1305.KeySize+chacha20poly1305.NonceSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out[:chacha20poly1305.KeySize], out[chacha20poly1305.KeySize:], nil
+}
+
+func seal(messageKey, ad, header, plainText []byte) ([]byte, error) {
+	key, nonce, err := messageAEAD(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(header, nonce, plainText, append(bytes.Clone(ad), header...)), nil
+}
+
+func open(messageKey, ad, header, cipherText []byte) ([]byte, error) {
+	key, nonce, err := messageAEAD(messageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	plainText, err := aead.Open(nil, nonce, cipherText, append(bytes.Clone(ad), header...))
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	return plainText, nil
+}
@@ -0,0 +1,148 @@
+package e2e
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"encoding/binary"
+)
+
+// 会话状态格式：版本(1) || 标志(1) || 对端身份公钥(32) || AD(64) || X3DH 头部长度(1) || X3DH 头部 ||
+// 根密钥(32) || 本方棘轮私钥(32) || [对端棘轮公钥(32)] || [发送链密钥(32)] || [接收链密钥(32)] ||
+// Ns(4) || Nr(4) || PN(4) || 跳过密钥数(4) || {棘轮公钥(32) || N(4) || 消息密钥(32)}...
+const stateVersion byte = 1
+
+const (
+	stateInitiator byte = 1 << iota
+	stateHasDHR
+	stateHasCKS
+	stateHasCKR
+)
+
+// MarshalBinary 序列化会话状态（实现 encoding.BinaryMarshaler）。结果包含全部会话密钥，须加密存储；
+// 每次 Encrypt/Decrypt 之后都应保存最新状态，否则回滚到旧状态会重用消息密钥。
+func (s *Session) MarshalBinary() ([]byte, error) {
+	var flags byte
+	if s.initiator {
+		flags |= stateInitiator
+	}
+	out := []byte{stateVersion, 0}
+	out = append(out, s.peer...)
+	out = append(out, s.ad...)
+	out = append(out, byte(len(s.preKeyHeader)))
+	out = append(out, s.preKeyHeader...)
+	out = append(out, s.rootKey...)
+	out = append(out, s.dhs.Bytes()...)
+	if s.dhr != nil {
+		flags |= stateHasDHR
+		out = append(out, s.dhr.Bytes()...)
+	}
+	if s.cks != nil {
+		flags |= stateHasCKS
+		out = append(out, s.cks...)
+	}
+	if s.ckr != nil {
+		flags |= stateHasCKR
+		out = append(out, s.ckr...)
+	}
+	out[1] = flags
+	out = binary.BigEndian.AppendUint32(out, s.ns)
+	out = binary.BigEndian.AppendUint32(out, s.nr)
+	out = binary.BigEndian.AppendUint32(out, s.pn)
+	out = binary.BigEndian.AppendUint32(out, uint32(len(s.skipped))) // #nosec G115 -- bounded by maxSkip.
+	for _, k := range s.skipped {
+		out = append(out, k.dh[:]...)
+		out = binary.BigEndian.AppendUint32(out, k.n)
+		out = append(out, k.key...)
+	}
+	return out, nil
+}
+
+// UnmarshalSession 还原 MarshalBinary 序列化的会话；opts 不随状态保存，需按需重新传入。
+func UnmarshalSession(data []byte, opts ...Option) (*Session, error) {
+	r := stateReader{data: data}
+	if version := r.byte(); version != stateVersion {
+		return nil, ErrInvalidState
+	}
+	flags := r.byte()
+	s := &Session{
+		opts:      newOptions(opts),
+		initiator: flags&stateInitiator != 0,
+		peer:      r.bytes(ed25519.PublicKeySize),
+		ad:        r.bytes(2 * ed25519.PublicKeySize),
+	}
+	switch n := int(r.byte()); n {
+	case 0:
+	case preKeyHeaderSize:
+		s.preKeyHeader = r.bytes(n)
+	default:
+		return nil, ErrInvalidState
+	}
+	s.rootKey = r.bytes(keySize)
+	dhs := r.bytes(keySize)
+	var dhr []byte
+	if flags&stateHasDHR != 0 {
+		dhr = r.bytes(keySize)
+	}
+	if flags&stateHasCKS != 0 {
+		s.cks = r.bytes(keySize)
+	}
+	if flags&stateHasCKR != 0 {
+		s.ckr = r.bytes(keySize)
+	}
+	s.ns, s.nr, s.pn = r.uint32(), r.uint32(), r.uint32()
+	count := r.uint32()
+	if r.err || uint64(count)*(keySize+4+keySize) > uint64(len(r.data)) {
+		return nil, ErrInvalidState
+	}
+	s.skipped = make([]skippedKey, count)
+	for i := range s.skipped {
+		copy(s.skipped[i].dh[:], r.bytes(keySize))
+		s.skipped[i].n = r.uint32()
+		s.skipped[i].key = r.bytes(keySize)
+	}
+	if r.err || len(r.data) != 0 || s.cks == nil {
+		return nil, ErrInvalidState
+	}
+	if extra := len(s.skipped) - s.opts.maxSkip; extra > 0 {
+		for _, k := range s.skipped[:extra] {
+			clear(k.key)
+		}
+		s.skipped = s.skipped[extra:]
+	}
+
+	var err error
+	if s.dhs, err = ecdh.X25519().NewPrivateKey(dhs); err != nil {
+		return nil, ErrInvalidState
+	}
+	if dhr != nil {
+		if s.dhr, err = ecdh.X25519().NewPublicKey(dhr); err != nil {
+			return nil, ErrInvalidState
+		}
+	}
+	return s, nil
+}
+
+// stateReader 顺序读取定长字段，越界时置 err 并返回零值，由调用方统一检查。
+type stateReader struct {
+	data []byte
+	err  bool
+}
+
+func (r *stateReader) bytes(n int) []byte {
+	if r.err || len(r.data) < n {
+		r.err = true
+		return make([]byte, n)
+	}
+	b := bytes.Clone(r.data[:n])
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *stateReader) byte() byte {
+	return r.bytes(1)[0]
+}
+
+func (r *stateReader) uint32() uint32 {
+	return binary.BigEndian.Uint32(r.bytes(4))
+}
@@ -0,0 +1,288 @@
+package e2e
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"encoding/binary"
+
+	encryecdh "github.com/gtkit/encry/ecdh"
+	"github.com/gtkit/encry/ed"
+	"github.com/gtkit/encry/hkdf"
+)
+
+const (
+	// signedPreKeyContext 为签名预密钥签名使用的 Ed25519ctx 上下文，签名无法挪作他用。
+	signedPreKeyContext = "encry e2e signed prekey v1"
+	x3dhInfo            = "encry e2e X3DH v1"
+
+	// preKeyHeaderSize = IK_A(32) || EK_A(32) || SPK ID(4) || OPK 标志(1) || OPK ID(4)。
+	preKeyHeaderSize = ed25519.PublicKeySize + keySize + 4 + 1 + 4
+)
+
+// SignedPreKey 是接收方的签名预密钥（私钥部分），应定期轮换；旧的签名预密钥在
+// 引用它的首条消息都处理完之前须保留。
+type SignedPreKey struct {
+	ID         uint32
+	PrivateKey *ecdh.PrivateKey
+	// Signature 为身份密钥对 X25519 公钥的 Ed25519ctx 签名。
+	Signature []byte
+}
+
+// OneTimePreKey 是接收方的一次性预密钥（私钥部分），被一条首消息使用后应立即删除。
+type OneTimePreKey struct {
+	ID         uint32
+	PrivateKey *ecdh.PrivateKey
+}
+
+// PreKeyBundle 是接收方发布到服务器的公开信息；服务器每次分发时附带一把未用过的一次性预密钥
+// （用尽时 OneTimePreKey 为 nil，X3DH 仍然成立，只是少了一层针对签名预密钥泄露的保护）。
+type PreKeyBundle struct {
+	IdentityKey           ed25519.PublicKey
+	SignedPreKeyID        uint32
+	SignedPreKey          []byte
+	SignedPreKeySignature []byte
+	OneTimePreKeyID       uint32
+	OneTimePreKey         []byte
+}
+
+// PreKeyMessageInfo 是首条消息中 X3DH 头部的公开字段，供接收方查找对应的预密钥并核对发起方身份。
+type PreKeyMessageInfo struct {
+	IdentityKey      ed25519.PublicKey
+	SignedPreKeyID   uint32
+	OneTimePreKeyID  uint32
+	HasOneTimePreKey bool
+}
+
+// GenerateSignedPreKey 生成一把 X25519 预密钥并用身份私钥签名。
+func GenerateSignedPreKey(identity ed25519.PrivateKey, id uint32) (*SignedPreKey, error) {
+	if len(identity) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidIdentityKey
+	}
+	priv, err := encryecdh.GenerateX25519()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := ed.SignCtx(identity, priv.PublicKey().Bytes(), signedPreKeyContext)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedPreKey{ID: id, PrivateKey: priv, Signature: signature}, nil
+}
+
+// GenerateOneTimePreKeys 生成 n 把一次性预密钥，ID 从 firstID 起依次递增。
+func GenerateOneTimePreKeys(firstID uint32, n int) ([]*OneTimePreKey, error) {
+	if n < 0 {
+		return nil, ErrInvalidPreKey
+	}
+	keys := make([]*OneTimePreKey, 0, n)
+	for i := range n {
+		priv, err := encryecdh.GenerateX25519()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, &OneTimePreKey{ID: firstID + uint32(i), PrivateKey: priv}) // #nosec G115 -- IDs wrap by design.
+	}
+	return keys, nil
+}
+
+// NewPreKeyBundle 由接收方的身份公钥、签名预密钥（不可为 nil）与可选的一次性预密钥组装 Bundle。
+func NewPreKeyBundle(identity ed25519.PublicKey, signedPreKey *SignedPreKey, oneTimePreKey *OneTimePreKey) *PreKeyBundle {
+	b := &PreKeyBundle{
+		IdentityKey:           bytes.Clone(identity),
+		SignedPreKeyID:        signedPreKey.ID,
+		SignedPreKey:          signedPreKey.PrivateKey.PublicKey().Bytes(),
+		SignedPreKeySignature: bytes.Clone(signedPreKey.Signature),
+	}
+	if oneTimePreKey != nil {
+		b.OneTimePreKeyID = oneTimePreKey.ID
+		b.OneTimePreKey = oneTimePreKey.PrivateKey.PublicKey().Bytes()
+	}
+	return b
+}
+
+// Verify 校验签名预密钥的签名与各公钥编码。
+func (b *PreKeyBundle) Verify() error {
+	_, _, _, err := b.parse()
+	return err
+}
+
+func (b *PreKeyBundle) parse() (*ecdh.PublicKey, *ecdh.PublicKey, *ecdh.PublicKey, error) {
+	identity, err := ed.ToX25519PublicKey(b.IdentityKey)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidIdentityKey
+	}
+	ok, err := ed.VerifyCtx(b.IdentityKey, b.SignedPreKey, b.SignedPreKeySignature, signedPreKeyContext)
+	if err != nil || !ok {
+		return nil, nil, nil, ErrInvalidSignature
+	}
+	signedPreKey, err := ecdh.X25519().NewPublicKey(b.SignedPreKey)
+	if err != nil {
+		return nil, nil, nil, ErrInvalidPreKey
+	}
+	var oneTimePreKey *ecdh.PublicKey
+	if b.OneTimePreKey != nil {
+		if oneTimePreKey, err = ecdh.X25519().NewPublicKey(b.OneTimePreKey); err != nil {
+			return nil, nil, nil, ErrInvalidPreKey
+		}
+	}
+	return identity, signedPreKey, oneTimePreKey, nil
+}
+
+// InitiateSession 由发起方用自己的身份私钥与对方的 Bundle 完成 X3DH，返回可立即 Encrypt 的会话。
+func InitiateSession(identity ed25519.PrivateKey, bundle *PreKeyBundle, opts ...Option) (*Session, error) {
+	if bundle == nil {
+		return nil, ErrInvalidPreKey
+	}
+	ourIdentity, err := ed.ToX25519PrivateKey(identity)
+	if err != nil {
+		return nil, ErrInvalidIdentityKey
+	}
+	theirIdentity, signedPreKey, oneTimePreKey, err := bundle.parse()
+	if err != nil {
+		return nil, err
+	}
+	o := newOptions(opts)
+	ephemeral, err := o.generateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := []dhPair{
+		{ourIdentity, signedPreKey},
+		{ephemeral, theirIdentity},
+		{ephemeral, signedPreKey},
+	}
+	if oneTimePreKey != nil {
+		pairs = append(pairs, dhPair{ephemeral, oneTimePreKey})
+	}
+	sk, err := x3dhSecret(pairs)
+	if err != nil {
+		return nil, ErrInvalidPreKey
+	}
+
+	ourPub := identity.Public().(ed25519.PublicKey)
+	header := make([]byte, 0, preKeyHeaderSize)
+	header = append(header, ourPub...)
+	header = append(header, ephemeral.PublicKey().Bytes()...)
+	header = binary.BigEndian.AppendUint32(header, bundle.SignedPreKeyID)
+	if oneTimePreKey != nil {
+		header = append(header, 1)
+	} else {
+		header = append(header, 0)
+	}
+	header = binary.BigEndian.AppendUint32(header, bundle.OneTimePreKeyID)
+
+	s := &Session{
+		opts:         o,
+		initiator:    true,
+		peer:         bytes.Clone(bundle.IdentityKey),
+		ad:           append(bytes.Clone(ourPub), bundle.IdentityKey...),
+		preKeyHeader: header,
+		rootKey:      sk,
+		dhr:          signedPreKey,
+	}
+	if s.dhs, err = o.generateKey(); err != nil {
+		return nil, err
+	}
+	if err := s.ratchetSend(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// PeekPreKeyMessage 解析首条消息的 X3DH 头部（不做解密），用于查找 AcceptSession 所需的预密钥。
+func PeekPreKeyMessage(msg []byte) (*PreKeyMessageInfo, error) {
+	m, err := parseMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	if m.preKeyHeader == nil {
+		return nil, ErrNotPreKeyMessage
+	}
+	h := m.preKeyHeader
+	return &PreKeyMessageInfo{
+		IdentityKey:      bytes.Clone(h[:ed25519.PublicKeySize]),
+		SignedPreKeyID:   binary.BigEndian.Uint32(h[2*keySize:]),
+		HasOneTimePreKey: h[2*keySize+4] == 1,
+		OneTimePreKeyID:  binary.BigEndian.Uint32(h[2*keySize+5:]),
+	}, nil
+}
+
+// AcceptSession 由接收方处理发起方的首条消息：按头部中的 ID 传入对应的签名预密钥与一次性预密钥
+// （头部未使用一次性预密钥时传 nil），完成 X3DH 并解密，返回会话与首条明文。
+// 调用前应先核对 PeekPreKeyMessage 返回的发起方身份公钥是否可信。
+func AcceptSession(identity ed25519.PrivateKey, signedPreKey *SignedPreKey, oneTimePreKey *OneTimePreKey, msg []byte, opts ...Option) (*Session, []byte, error) {
+	ourIdentity, err := ed.ToX25519PrivateKey(identity)
+	if err != nil {
+		return nil, nil, ErrInvalidIdentityKey
+	}
+	info, err := PeekPreKeyMessage(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if signedPreKey == nil || signedPreKey.PrivateKey == nil || signedPreKey.ID != info.SignedPreKeyID {
+		return nil, nil, ErrInvalidPreKey
+	}
+	if info.HasOneTimePreKey != (oneTimePreKey != nil) ||
+		(oneTimePreKey != nil && (oneTimePreKey.PrivateKey == nil || oneTimePreKey.ID != info.OneTimePreKeyID)) {
+		return nil, nil, ErrInvalidPreKey
+	}
+	theirIdentity, err := ed.ToX25519PublicKey(info.IdentityKey)
+	if err != nil {
+		return nil, nil, ErrInvalidIdentityKey
+	}
+	m, _ := parseMessage(msg)
+	ephemeral, err := ecdh.X25519().NewPublicKey(m.preKeyHeader[ed25519.PublicKeySize : ed25519.PublicKeySize+keySize])
+	if err != nil {
+		return nil, nil, ErrInvalidMessage
+	}
+
+	pairs := []dhPair{
+		{signedPreKey.PrivateKey, theirIdentity},
+		{ourIdentity, ephemeral},
+		{signedPreKey.PrivateKey, ephemeral},
+	}
+	if oneTimePreKey != nil {
+		pairs = append(pairs, dhPair{oneTimePreKey.PrivateKey, ephemeral})
+	}
+	sk, err := x3dhSecret(pairs)
+	if err != nil {
+		return nil, nil, ErrInvalidMessage
+	}
+
+	ourPub := identity.Public().(ed25519.PublicKey)
+	s := &Session{
+		opts:         newOptions(opts),
+		peer:         info.IdentityKey,
+		ad:           append(bytes.Clone(info.IdentityKey), ourPub...),
+		preKeyHeader: bytes.Clone(m.preKeyHeader),
+		rootKey:      sk,
+		dhs:          signedPreKey.PrivateKey,
+	}
+	plainText, err := s.Decrypt(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, plainText, nil
+}
+
+type dhPair struct {
+	priv *ecdh.PrivateKey
+	pub  *ecdh.PublicKey
+}
+
+// x3dhSecret 计算 SK = HKDF(F || DH1 || DH2 || DH3 [|| DH4])，F 为 32 字节 0xFF（X3DH §2.2）。
+func x3dhSecret(pairs []dhPair) ([]byte, error) {
+	ikm := bytes.Repeat([]byte{0xff}, keySize)
+	defer func() { clear(ikm) }()
+	for _, dh := range pairs {
+		shared, err := dh.priv.ECDH(dh.pub)
+		if err != nil {
+			return nil, err
+		}
+		ikm = append(ikm, shared...)
+		clear(shared)
+	}
+	return hkdf.Derive(ikm, nil, x3dhInfo, keySize)
+}
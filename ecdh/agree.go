@@ -0,0 +1,195 @@
+package ecdh
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/gtkit/encry/aes"
+	"github.com/gtkit/encry/chacha"
+	"github.com/gtkit/encry/hkdf"
+)
+
+// agreeProtocol 为 Agree 记录（transcript）的协议标识，记录格式变化时递增版本。
+const agreeProtocol = "encry/ecdh agree v1"
+
+// 派生各用途子密钥的 HKDF-Expand-Label 标签。
+const (
+	agreeLabelChaCha = "agree xchacha20poly1305"
+	agreeLabelAESGCM = "agree aes-256-gcm"
+	agreeLabelExport = "agree export "
+)
+
+var (
+	// ErrInvalidMode 表示 AgreeOptions.Mode 未设置或取值非法。
+	ErrInvalidMode = errors.New("ecdh: invalid agreement mode")
+	// ErrInvalidLabel 表示 AgreeOptions.Label 为空。
+	ErrInvalidLabel = errors.New("ecdh: agreement label is required")
+)
+
+// Mode 是密钥协商模式，必须显式指定，且双方须各自选择对应的一侧。
+type Mode uint8
+
+const (
+	// EphemeralStaticSender：本方私钥为仅用一次的临时密钥，对端为接收方的静态公钥。
+	// 发送方需把临时公钥随密文发给接收方；通常直接使用 AgreeEphemeral。
+	EphemeralStaticSender Mode = iota + 1
+	// EphemeralStaticRecipient：本方私钥为静态密钥，对端公钥为发送方的临时公钥。
+	EphemeralStaticRecipient
+	// StaticStatic：双方都使用静态密钥，同一对密钥在相同 Label/Context 下总是得到相同的会话密钥，
+	// 因此不具备前向安全性，Context 中应放入会话标识或双方交换的随机数。
+	StaticStatic
+)
+
+// AgreeOptions 描述一次密钥协商，双方必须使用相同的 Label 与 Context（Mode 取各自一侧）。
+type AgreeOptions struct {
+	// Mode 为协商模式，必填。
+	Mode Mode
+	// Label 为协议标识（如 "myapp file-share v1"），必填；不同协议的会话密钥互不相关。
+	Label string
+	// Context 为可选的附加上下文（会话 ID、双方身份等），长度不限。
+	Context []byte
+}
+
+// Agreement 是一次密钥协商的结果，所有子密钥都绑定了曲线、模式、双方公钥、Label 与 Context。
+// 创建后只读，可并发使用。
+type Agreement struct {
+	prk        []byte
+	transcript []byte
+}
+
+// Agree 用本方私钥与对端公钥完成认证的密钥协商，返回可直接构造 AEAD 的 Agreement。
+//
+// 与 SharedSecret 不同，Agree 不暴露原始 DH 输出：共享密钥经 HKDF-SHA256 派生，
+// salt 为协商记录的摘要，记录依次包含协议版本、模式、曲线、双方公钥（发送方在前；
+// StaticStatic 按字节序排列）、Label 与 Context，任一项不一致双方都会得到不同的密钥。
+func Agree(priv *ecdh.PrivateKey, peerPub *ecdh.PublicKey, opts AgreeOptions) (*Agreement, error) {
+	if priv == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	if peerPub == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	if opts.Label == "" {
+		return nil, ErrInvalidLabel
+	}
+
+	ours, theirs := priv.PublicKey().Bytes(), peerPub.Bytes()
+	var first, second []byte
+	switch opts.Mode {
+	case EphemeralStaticSender:
+		first, second = ours, theirs
+	case EphemeralStaticRecipient:
+		first, second = theirs, ours
+	case StaticStatic:
+		first, second = ours, theirs
+		if bytes.Compare(first, second) > 0 {
+			first, second = second, first
+		}
+	default:
+		return nil, ErrInvalidMode
+	}
+
+	shared, err := priv.ECDH(peerPub)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(shared)
+
+	transcript := agreeTranscript(opts.Mode, curveName(priv.Curve()), first, second, opts.Label, opts.Context)
+	prk, err := hkdf.Extract(shared, transcript)
+	if err != nil {
+		return nil, err
+	}
+	return &Agreement{prk: prk, transcript: transcript}, nil
+}
+
+// AgreeEphemeral 在 peerPub 的曲线上生成一次性临时密钥，以 EphemeralStaticSender 模式完成协商，
+// 返回需要发送给对端的临时公钥；opts.Mode 被忽略。临时私钥在返回前即被丢弃。
+func AgreeEphemeral(peerPub *ecdh.PublicKey, opts AgreeOptions) (*ecdh.PublicKey, *Agreement, error) {
+	if peerPub == nil {
+		return nil, nil, ErrInvalidPublicKey
+	}
+	ephemeral, err := peerPub.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts.Mode = EphemeralStaticSender
+	agreement, err := Agree(ephemeral, peerPub, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ephemeral.PublicKey(), agreement, nil
+}
+
+// ChaCha 返回以派生密钥构造的 XChaCha20-Poly1305 实例。
+func (a *Agreement) ChaCha() (*chacha.ChaCha, error) {
+	key, err := a.expand(agreeLabelChaCha, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	return chacha.NewChaCha(key)
+}
+
+// AESGCM 返回以派生密钥构造的 AES-256-GCM 实例（与 ChaCha 使用不同的子密钥）。
+func (a *Agreement) AESGCM() (*aes.GCM, error) {
+	key, err := a.expand(agreeLabelAESGCM, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	return aes.NewGCM(string(key)), nil
+}
+
+// Key 按用途标签导出 length 字节的附加密钥（如 MAC 密钥、文件密钥），
+// 不同 label 或 length 得到互不相关的密钥，也与 ChaCha/AESGCM 的密钥无关。
+func (a *Agreement) Key(label string, length int) ([]byte, error) {
+	return a.expand(agreeLabelExport+label, length)
+}
+
+// Transcript 返回协商记录的 SHA-256 摘要，可用于双方密钥确认或写入审计日志，本身不含秘密。
+func (a *Agreement) Transcript() []byte {
+	return bytes.Clone(a.transcript)
+}
+
+func (a *Agreement) expand(label string, length int) ([]byte, error) {
+	return hkdf.ExpandLabel(a.prk, label, a.transcript, length)
+}
+
+// agreeTranscript 对协商参数做长度前缀编码后取 SHA-256，避免字段拼接产生歧义。
+func agreeTranscript(mode Mode, curve string, first, second []byte, label string, context []byte) []byte {
+	h := sha256.New()
+	for _, field := range [][]byte{
+		[]byte(agreeProtocol),
+		{byte(mode.kind())},
+		[]byte(curve),
+		first,
+		second,
+		[]byte(label),
+		context,
+	} {
+		h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(field))))
+		h.Write(field)
+	}
+	return h.Sum(nil)
+}
+
+// kind 返回写入记录的模式：发送方与接收方属于同一种 ephemeral-static 协商。
+func (m Mode) kind() Mode {
+	if m == EphemeralStaticRecipient {
+		return EphemeralStaticSender
+	}
+	return m
+}
+
+// curveName 返回写入记录的曲线名（crypto/ecdh.Curve 的 String 形式，如 "X25519"、"P-256"）。
+func curveName(curve ecdh.Curve) string {
+	if s, ok := curve.(interface{ String() string }); ok {
+		return s.String()
+	}
+	return ""
+}
@@ -0,0 +1,248 @@
+package ecdh_test
+
+import (
+	stdecdh "crypto/ecdh"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/gtkit/encry/ecdh"
+	"github.com/gtkit/encry/hkdf"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func mustX25519(t *testing.T, hexKey string) *stdecdh.PrivateKey {
+	t.Helper()
+	priv, err := ecdh.ParsePrivateKey(stdecdh.X25519(), mustHex(t, hexKey))
+	require.NoError(t, err)
+	return priv
+}
+
+// 用 RFC 7748 §6.1 的密钥对独立重算协商记录与派生结果，固定 v1 格式。
+func TestAgreeKnownAnswer(t *testing.T) {
+	t.Parallel()
+
+	alice := mustX25519(t, "77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a")
+	bob := mustX25519(t, "5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb")
+	opts := ecdh.AgreeOptions{Mode: ecdh.EphemeralStaticSender, Label: "kat", Context: []byte("ctx")}
+
+	sender, err := ecdh.Agree(alice, bob.PublicKey(), opts)
+	require.NoError(t, err)
+
+	h := sha256.New()
+	for _, field := range [][]byte{
+		[]byte("encry/ecdh agree v1"), {byte(ecdh.EphemeralStaticSender)}, []byte("X25519"),
+		alice.PublicKey().Bytes(), bob.PublicKey().Bytes(), []byte("kat"), []byte("ctx"),
+	} {
+		h.Write(binary.BigEndian.AppendUint64(nil, uint64(len(field))))
+		h.Write(field)
+	}
+	transcript := h.Sum(nil)
+	require.Equal(t, transcript, sender.Transcript())
+
+	shared := mustHex(t, "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742")
+	prk, err := hkdf.Extract(shared, transcript)
+	require.NoError(t, err)
+	want, err := hkdf.ExpandLabel(prk, "agree export file", transcript, 40)
+	require.NoError(t, err)
+
+	got, err := sender.Key("file", 40)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	opts.Mode = ecdh.EphemeralStaticRecipient
+	recipient, err := ecdh.Agree(bob, alice.PublicKey(), opts)
+	require.NoError(t, err)
+	got, err = recipient.Key("file", 40)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestAgreeModes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		curve      stdecdh.Curve
+		modeA      ecdh.Mode
+		modeB      ecdh.Mode
+		wantShared bool
+	}{
+		{"X25519 ephemeral-static", stdecdh.X25519(), ecdh.EphemeralStaticSender, ecdh.EphemeralStaticRecipient, true},
+		{"P-256 ephemeral-static", stdecdh.P256(), ecdh.EphemeralStaticSender, ecdh.EphemeralStaticRecipient, true},
+		{"P-384 static-static", stdecdh.P384(), ecdh.StaticStatic, ecdh.StaticStatic, true},
+		{"双方都自认发送方", stdecdh.X25519(), ecdh.EphemeralStaticSender, ecdh.EphemeralStaticSender, false},
+		{"模式不一致", stdecdh.X25519(), ecdh.StaticStatic, ecdh.EphemeralStaticRecipient, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a, err := ecdh.Generate(tt.curve)
+			require.NoError(t, err)
+			b, err := ecdh.Generate(tt.curve)
+			require.NoError(t, err)
+
+			agreeA, err := ecdh.Agree(a, b.PublicKey(), ecdh.AgreeOptions{Mode: tt.modeA, Label: "test"})
+			require.NoError(t, err)
+			agreeB, err := ecdh.Agree(b, a.PublicKey(), ecdh.AgreeOptions{Mode: tt.modeB, Label: "test"})
+			require.NoError(t, err)
+
+			keyA, err := agreeA.Key("k", 32)
+			require.NoError(t, err)
+			keyB, err := agreeB.Key("k", 32)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantShared, string(keyA) == string(keyB))
+		})
+	}
+}
+
+func TestAgreeAEAD(t *testing.T) {
+	t.Parallel()
+
+	recipient, err := ecdh.GenerateX25519()
+	require.NoError(t, err)
+	opts := ecdh.AgreeOptions{Label: "aead test", Context: []byte("session-7")}
+
+	ephemeralPub, sender, err := ecdh.AgreeEphemeral(recipient.PublicKey(), opts)
+	require.NoError(t, err)
+	opts.Mode = ecdh.EphemeralStaticRecipient
+	receiver, err := ecdh.Agree(recipient, ephemeralPub, opts)
+	require.NoError(t, err)
+
+	sc, err := sender.ChaCha()
+	require.NoError(t, err)
+	rc, err := receiver.ChaCha()
+	require.NoError(t, err)
+	ct, err := sc.Encrypt([]byte("hello"))
+	require.NoError(t, err)
+	pt, err := rc.Decrypt(ct)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(pt))
+
+	sg, err := sender.AESGCM()
+	require.NoError(t, err)
+	rg, err := receiver.AESGCM()
+	require.NoError(t, err)
+	ct, err = sg.Encrypt([]byte("hello"))
+	require.NoError(t, err)
+	s, err := rg.Decrypt(ct)
+	require.NoError(t, err)
+	require.Equal(t, "hello", s)
+
+	// ChaCha 与 AES-GCM 使用不同子密钥：AES-GCM 密文不能被同一协商的其他密钥解开。
+	other, err := ecdh.Agree(recipient, ephemeralPub, ecdh.AgreeOptions{Mode: ecdh.EphemeralStaticRecipient, Label: "aead test"})
+	require.NoError(t, err)
+	og, err := other.AESGCM()
+	require.NoError(t, err)
+	_, err = og.Decrypt(ct)
+	require.Error(t, err)
+}
+
+func TestAgreeBindsInputs(t *testing.T) {
+	t.Parallel()
+
+	a, err := ecdh.GenerateX25519()
+	require.NoError(t, err)
+	b, err := ecdh.GenerateX25519()
+	require.NoError(t, err)
+	c, err := ecdh.GenerateX25519()
+	require.NoError(t, err)
+
+	base := ecdh.AgreeOptions{Mode: ecdh.StaticStatic, Label: "bind", Context: []byte("c1")}
+	ref, err := ecdh.Agree(a, b.PublicKey(), base)
+	require.NoError(t, err)
+	refKey, err := ref.Key("k", 32)
+	require.NoError(t, err)
+
+	withLabel, withContext, noContext := base, base, base
+	withLabel.Label = "bind2"
+	withContext.Context = []byte("c2")
+	noContext.Context = nil
+	variants := []struct {
+		name string
+		peer *stdecdh.PublicKey
+		opts ecdh.AgreeOptions
+	}{
+		{"label", b.PublicKey(), withLabel},
+		{"context", b.PublicKey(), withContext},
+		{"no context", b.PublicKey(), noContext},
+		{"peer", c.PublicKey(), base},
+	}
+	for _, v := range variants {
+		name := v.name
+		agreement, err := ecdh.Agree(a, v.peer, v.opts)
+		require.NoError(t, err, name)
+		key, err := agreement.Key("k", 32)
+		require.NoError(t, err, name)
+		require.NotEqual(t, refKey, key, name)
+	}
+
+	other, err := ref.Key("k2", 32)
+	require.NoError(t, err)
+	require.NotEqual(t, refKey, other)
+	longer, err := ref.Key("k", 64)
+	require.NoError(t, err)
+	require.NotEqual(t, refKey, longer[:32])
+}
+
+func TestAgreeErrors(t *testing.T) {
+	t.Parallel()
+
+	a, err := ecdh.GenerateX25519()
+	require.NoError(t, err)
+	p256, err := ecdh.Generate(stdecdh.P256())
+	require.NoError(t, err)
+	opts := ecdh.AgreeOptions{Mode: ecdh.StaticStatic, Label: "err"}
+
+	_, err = ecdh.Agree(nil, a.PublicKey(), opts)
+	require.ErrorIs(t, err, ecdh.ErrInvalidPrivateKey)
+	_, err = ecdh.Agree(a, nil, opts)
+	require.ErrorIs(t, err, ecdh.ErrInvalidPublicKey)
+	_, _, err = ecdh.AgreeEphemeral(nil, opts)
+	require.ErrorIs(t, err, ecdh.ErrInvalidPublicKey)
+
+	_, err = ecdh.Agree(a, a.PublicKey(), ecdh.AgreeOptions{Label: "err"})
+	require.ErrorIs(t, err, ecdh.ErrInvalidMode)
+	_, err = ecdh.Agree(a, a.PublicKey(), ecdh.AgreeOptions{Mode: 9, Label: "err"})
+	require.ErrorIs(t, err, ecdh.ErrInvalidMode)
+	_, err = ecdh.Agree(a, a.PublicKey(), ecdh.AgreeOptions{Mode: ecdh.StaticStatic})
+	require.ErrorIs(t, err, ecdh.ErrInvalidLabel)
+
+	// 曲线不一致。
+	_, err = ecdh.Agree(a, p256.PublicKey(), opts)
+	require.Error(t, err)
+
+	// X25519 小阶点（全零）导致共享密钥为零，被拒绝。
+	zero, err := ecdh.ParsePublicKey(stdecdh.X25519(), make([]byte, 32))
+	require.NoError(t, err)
+	_, err = ecdh.Agree(a, zero, opts)
+	require.Error(t, err)
+}
+
+func ExampleAgreeEphemeral() {
+	// 接收方持有长期静态密钥，公钥预先分发给发送方。
+	recipient, _ := ecdh.GenerateX25519()
+	opts := ecdh.AgreeOptions{Label: "myapp file-share v1", Context: []byte("file-42")}
+
+	// 发送方：生成临时密钥并协商，把临时公钥连同密文一起发出。
+	ephemeralPub, sender, _ := ecdh.AgreeEphemeral(recipient.PublicKey(), opts)
+	sealer, _ := sender.ChaCha()
+	cipherText, _ := sealer.Encrypt([]byte("hello"))
+
+	// 接收方：用静态私钥与收到的临时公钥完成协商。
+	opts.Mode = ecdh.EphemeralStaticRecipient
+	receiver, _ := ecdh.Agree(recipient, ephemeralPub, opts)
+	opener, _ := receiver.ChaCha()
+	plainText, _ := opener.Decrypt(cipherText)
+	fmt.Println(string(plainText))
+	// Output: hello
+}
@@ -0,0 +1,55 @@
+package ecdh
+
+import (
+	"crypto/ecdh"
+	"crypto/elliptic"
+)
+
+// MarshalCompressedPublicKey 将 NIST 曲线公钥编码为 SEC1 压缩点（0x02/0x03 || X），
+// P-256 为 33 字节；X25519 公钥本身即 32 字节 u 坐标，返回 ErrUnsupportedCurve。
+func MarshalCompressedPublicKey(pub *ecdh.PublicKey) ([]byte, error) {
+	if pub == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	if nistCurve(pub.Curve()) == nil {
+		return nil, ErrUnsupportedCurve
+	}
+	// Bytes() 为未压缩点 0x04 || X || Y。
+	raw := pub.Bytes()
+	size := (len(raw) - 1) / 2
+	out := make([]byte, 1+size)
+	out[0] = 0x02 | raw[len(raw)-1]&1
+	copy(out[1:], raw[1:1+size])
+	return out, nil
+}
+
+// ParseCompressedPublicKey 用指定 NIST 曲线解析 SEC1 压缩点公钥，并校验点在曲线上。
+func ParseCompressedPublicKey(curve ecdh.Curve, b []byte) (*ecdh.PublicKey, error) {
+	ec := nistCurve(curve)
+	if ec == nil {
+		return nil, ErrUnsupportedCurve
+	}
+	x, y := elliptic.UnmarshalCompressed(ec, b)
+	if x == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	size := (ec.Params().BitSize + 7) / 8
+	raw := make([]byte, 1+2*size)
+	raw[0] = 0x04
+	x.FillBytes(raw[1 : 1+size])
+	y.FillBytes(raw[1+size:])
+	return curve.NewPublicKey(raw)
+}
+
+func nistCurve(curve ecdh.Curve) elliptic.Curve {
+	switch curve {
+	case ecdh.P256():
+		return elliptic.P256()
+	case ecdh.P384():
+		return elliptic.P384()
+	case ecdh.P521():
+		return elliptic.P521()
+	default:
+		return nil
+	}
+}
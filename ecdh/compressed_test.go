@@ -0,0 +1,91 @@
+package ecdh_test
+
+import (
+	"bytes"
+	stdecdh "crypto/ecdh"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/ecdh"
+	"github.com/stretchr/testify/require"
+)
+
+// 压缩点夹具由 openssl ec -pubout -conv_form compressed 生成。
+func TestCompressedPublicKeyOpenSSL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		curve stdecdh.Curve
+		size  int
+	}{
+		{"p256", stdecdh.P256(), 33},
+		{"p384", stdecdh.P384(), 49},
+		{"p521", stdecdh.P521(), 67},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			want, err := hex.DecodeString(strings.TrimSpace(string(readTestdata(t, tt.name+"_compressed.hex"))))
+			require.NoError(t, err)
+			require.Len(t, want, tt.size)
+
+			pub, err := ecdh.ParsePublicKeyPEM(readTestdata(t, tt.name+"_pub.pem"))
+			require.NoError(t, err)
+			got, err := ecdh.MarshalCompressedPublicKey(pub)
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+
+			parsed, err := ecdh.ParseCompressedPublicKey(tt.curve, want)
+			require.NoError(t, err)
+			require.True(t, pub.Equal(parsed))
+		})
+	}
+}
+
+func TestCompressedPublicKeyRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, curve := range []stdecdh.Curve{stdecdh.P256(), stdecdh.P384(), stdecdh.P521()} {
+		// 多次生成以覆盖 Y 奇偶两种前缀。
+		for range 8 {
+			priv, err := ecdh.Generate(curve)
+			require.NoError(t, err)
+			b, err := ecdh.MarshalCompressedPublicKey(priv.PublicKey())
+			require.NoError(t, err)
+			require.Contains(t, []byte{0x02, 0x03}, b[0])
+			pub, err := ecdh.ParseCompressedPublicKey(curve, b)
+			require.NoError(t, err)
+			require.True(t, priv.PublicKey().Equal(pub))
+		}
+	}
+}
+
+func TestCompressedPublicKeyInvalid(t *testing.T) {
+	t.Parallel()
+	x, err := ecdh.GenerateX25519()
+	require.NoError(t, err)
+	_, err = ecdh.MarshalCompressedPublicKey(x.PublicKey())
+	require.ErrorIs(t, err, ecdh.ErrUnsupportedCurve)
+	_, err = ecdh.ParseCompressedPublicKey(stdecdh.X25519(), x.PublicKey().Bytes())
+	require.ErrorIs(t, err, ecdh.ErrUnsupportedCurve)
+	_, err = ecdh.MarshalCompressedPublicKey(nil)
+	require.ErrorIs(t, err, ecdh.ErrInvalidPublicKey)
+
+	priv, err := ecdh.Generate(stdecdh.P256())
+	require.NoError(t, err)
+	// 未压缩点、错误前缀、长度错误、不在曲线上的 X 均拒绝。
+	_, err = ecdh.ParseCompressedPublicKey(stdecdh.P256(), priv.PublicKey().Bytes())
+	require.ErrorIs(t, err, ecdh.ErrInvalidPublicKey)
+	b, err := ecdh.MarshalCompressedPublicKey(priv.PublicKey())
+	require.NoError(t, err)
+	bad := append([]byte{0x04}, b[1:]...)
+	_, err = ecdh.ParseCompressedPublicKey(stdecdh.P256(), bad)
+	require.ErrorIs(t, err, ecdh.ErrInvalidPublicKey)
+	_, err = ecdh.ParseCompressedPublicKey(stdecdh.P256(), b[:32])
+	require.ErrorIs(t, err, ecdh.ErrInvalidPublicKey)
+	_, err = ecdh.ParseCompressedPublicKey(stdecdh.P384(), b)
+	require.ErrorIs(t, err, ecdh.ErrInvalidPublicKey)
+	allFF := append([]byte{0x02}, bytes.Repeat([]byte{0xff}, 32)...)
+	_, err = ecdh.ParseCompressedPublicKey(stdecdh.P256(), allFF)
+	require.ErrorIs(t, err, ecdh.ErrInvalidPublicKey)
+}
@@ -2,25 +2,45 @@
 //
 // 双方各自生成密钥对、交换公钥，即可各自算出相同的共享密钥。默认使用 X25519。
 //
-// 注意：协商出的共享密钥不应直接用作对称加密密钥，应再经 hkdf 等 KDF 派生。
+// 密钥可编码为 PKIX/PKCS#8 PEM（解析时由 OID 识别曲线），NIST 曲线公钥另支持 SEC1 压缩点。
+//
+// 注意：SharedSecret 返回的是原始 DH 输出，不应直接用作对称加密密钥。优先使用 Agree：
+// 它显式区分 ephemeral-static 与 static-static 模式，把双方公钥、协议标签与上下文绑定进
+// HKDF 派生，并直接返回 chacha/aes AEAD 实例。
 package ecdh
 
 import (
 	"crypto/ecdh"
 	"crypto/rand"
+	"errors"
 )
 
+// X25519SeedSize 为 X25519 私钥种子长度。
+const X25519SeedSize = 32
+
+// ErrInvalidSeed 表示种子长度不是 X25519SeedSize。
+var ErrInvalidSeed = errors.New("ecdh: X25519 seed must be 32 bytes")
+
 // GenerateX25519 生成一对 X25519 密钥。
 func GenerateX25519() (*ecdh.PrivateKey, error) {
 	return ecdh.X25519().GenerateKey(rand.Reader)
 }
 
+// X25519FromSeed 由 32 字节种子确定性地生成 X25519 私钥，同一种子总是得到同一密钥。
+// 种子即私钥标量（由 X25519 函数内部 clamp），必须按私钥保管。
+func X25519FromSeed(seed []byte) (*ecdh.PrivateKey, error) {
+	if len(seed) != X25519SeedSize {
+		return nil, ErrInvalidSeed
+	}
+	return ecdh.X25519().NewPrivateKey(seed)
+}
+
 // Generate 使用指定曲线生成密钥对（如 ecdh.P256()、ecdh.X25519()）。
 func Generate(curve ecdh.Curve) (*ecdh.PrivateKey, error) {
 	return curve.GenerateKey(rand.Reader)
 }
 
-// SharedSecret 用本方私钥与对端公钥计算共享密钥。
+// SharedSecret 用本方私钥与对端公钥计算共享密钥（原始 DH 输出，须再经 KDF 派生；见 Agree）。
 func SharedSecret(priv *ecdh.PrivateKey, peerPub *ecdh.PublicKey) ([]byte, error) {
 	return priv.ECDH(peerPub)
 }
@@ -2,6 +2,7 @@ package ecdh_test
 
 import (
 	stdecdh "crypto/ecdh"
+	"encoding/hex"
 	"fmt"
 	"testing"
 
@@ -61,3 +62,19 @@ func ExampleSharedSecret() {
 	fmt.Println(string(a) == string(b))
 	// Output: true
 }
+
+func TestX25519FromSeed(t *testing.T) {
+	// RFC 7748 §6.1 Alice 的私钥与公钥。
+	seed, err := hex.DecodeString("77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a")
+	require.NoError(t, err)
+	priv, err := ecdh.X25519FromSeed(seed)
+	require.NoError(t, err)
+	require.Equal(t, "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a", hex.EncodeToString(priv.PublicKey().Bytes()))
+
+	again, err := ecdh.X25519FromSeed(seed)
+	require.NoError(t, err)
+	require.True(t, priv.Equal(again))
+
+	_, err = ecdh.X25519FromSeed(seed[:16])
+	require.ErrorIs(t, err, ecdh.ErrInvalidSeed)
+}
@@ -0,0 +1,93 @@
+package ecdh
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+)
+
+var (
+	// ErrInvalidPrivateKey 表示 PEM 不是有效的 ECDH 私钥。
+	ErrInvalidPrivateKey = errors.New("ecdh: invalid private key")
+	// ErrInvalidPublicKey 表示 PEM 或压缩点不是有效的 ECDH 公钥。
+	ErrInvalidPublicKey = errors.New("ecdh: invalid public key")
+	// ErrUnsupportedCurve 表示曲线不支持该操作（如 X25519 没有压缩点编码）。
+	ErrUnsupportedCurve = errors.New("ecdh: unsupported curve")
+)
+
+// MarshalPrivateKeyPEM 将私钥编码为 PKCS#8 PEM（支持 X25519 与 P-256/384/521）。
+func MarshalPrivateKeyPEM(priv *ecdh.PrivateKey) ([]byte, error) {
+	if priv == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalPublicKeyPEM 将公钥编码为 PKIX PEM（支持 X25519 与 P-256/384/521）。
+func MarshalPublicKeyPEM(pub *ecdh.PublicKey) ([]byte, error) {
+	if pub == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParsePrivateKeyPEM 从 PKCS#8 PEM（或 OpenSSL 的 SEC1 "EC PRIVATE KEY"）解析私钥，
+// 曲线由 OID 自动识别，无需调用方指定。
+func ParsePrivateKeyPEM(data []byte) (*ecdh.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, ErrInvalidPrivateKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *ecdh.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		// NIST 曲线的 PKCS#8/SEC1 使用 id-ecPublicKey，x509 解析为 ECDSA 密钥。
+		return k.ECDH()
+	default:
+		return nil, ErrInvalidPrivateKey
+	}
+}
+
+// ParsePublicKeyPEM 从 PKIX PEM 解析公钥，曲线由 OID 自动识别。
+func ParsePublicKeyPEM(data []byte) (*ecdh.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, ErrInvalidPublicKey
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *ecdh.PublicKey:
+		return k, nil
+	case *ecdsa.PublicKey:
+		return k.ECDH()
+	default:
+		return nil, ErrInvalidPublicKey
+	}
+}
@@ -1,17 +1,23 @@
 // Package ecdsa 提供 ECDSA 签名/验签与密钥 PEM 序列化（基于 crypto/ecdsa）。
 //
-// 默认曲线 P-256、摘要 SHA-256，签名为 ASN.1 DER 编码。风格对齐 ed / rsa 包。
+// 默认曲线 P-256，摘要按曲线选择（P-256 → SHA-256、P-384 → SHA-384、P-521 → SHA-512），
+// 签名为 ASN.1 DER 编码。风格对齐 ed / rsa 包。对接 JWS ES256/ES384/ES512 或 WebCrypto 时，
+// 使用 WithEncoding(EncodingP1363) 输出定长 r||s；WithHash 可显式覆盖摘要。
+// 除 PKCS#8/PKIX PEM 外，也支持 OpenSSH 私钥格式与 authorized_keys 公钥行。
 package ecdsa
 
 import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
-	"crypto/sha256"
+	_ "crypto/sha256" // 注册 SHA-256 供 crypto.Hash.New 使用。
+	_ "crypto/sha512" // 注册 SHA-384/512 供 crypto.Hash.New 使用。
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/pem"
 	"errors"
+
+	"github.com/gtkit/encry/internal/sshkey"
 )
 
 var (
@@ -19,6 +25,11 @@ var (
 	ErrInvalidPrivateKey = errors.New("ecdsa: invalid private key")
 	// ErrInvalidPublicKey 表示 PEM 不是有效的 ECDSA 公钥。
 	ErrInvalidPublicKey = errors.New("ecdsa: invalid public key")
+	// ErrUnsupportedHash 表示摘要算法不是 SHA-256/384/512。
+	ErrUnsupportedHash = errors.New("ecdsa: unsupported hash")
+	// ErrInvalidComment 表示 authorized_keys 注释含 CR/LF，原样写入会注入额外的行（如追加受信任公钥）。
+	// 与 ed.ErrInvalidComment 是同一个哨兵错误。
+	ErrInvalidComment = sshkey.ErrInvalidComment
 )
 
 // GenerateKey 使用默认曲线 P-256 生成密钥对。
@@ -26,46 +37,76 @@ func GenerateKey() (*ecdsa.PrivateKey, error) {
 	return GenerateKeyWithCurve(elliptic.P256())
 }
 
-// GenerateKeyWithCurve 使用指定曲线（如 elliptic.P384()）生成密钥对。
+// GenerateKeyWithCurve 使用指定曲线（如 elliptic.P384()、elliptic.P521()）生成密钥对。
 func GenerateKeyWithCurve(curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
 	return ecdsa.GenerateKey(curve, rand.Reader)
 }
 
-// Sign 对消息做 SHA-256 摘要后用 ECDSA 签名，返回 ASN.1 DER 签名。
-func Sign(priv *ecdsa.PrivateKey, msg []byte) ([]byte, error) {
+// Sign 对消息做摘要后用 ECDSA 签名。默认按曲线选择摘要（HashForCurve）+ ASN.1 DER；
+// 可用 WithHash 显式指定摘要、WithEncoding(EncodingP1363) 输出定长 r||s，
+// WithDeterministic 使用 RFC 6979 确定性签名。
+func Sign(priv *ecdsa.PrivateKey, msg []byte, opts ...Option) ([]byte, error) {
 	if priv == nil {
 		return nil, ErrInvalidPrivateKey
 	}
-	digest := sha256.Sum256(msg)
-	return ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	o, err := newOptions(priv.Curve, opts)
+	if err != nil {
+		return nil, err
+	}
+	var sig []byte
+	if o.deterministic {
+		// 标准库在随机源为 nil 时按 RFC 6979 以 o.hash 派生 nonce。
+		sig, err = priv.Sign(nil, o.digest(msg), o.hash)
+	} else {
+		sig, err = ecdsa.SignASN1(rand.Reader, priv, o.digest(msg))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if o.encoding == EncodingP1363 {
+		return DERToP1363(sig, priv.Curve)
+	}
+	return sig, nil
 }
 
 // SignBase64 签名并返回 Base64 字符串。
-func SignBase64(priv *ecdsa.PrivateKey, msg []byte) (string, error) {
-	sig, err := Sign(priv, msg)
+func SignBase64(priv *ecdsa.PrivateKey, msg []byte, opts ...Option) (string, error) {
+	sig, err := Sign(priv, msg, opts...)
 	if err != nil {
 		return "", err
 	}
 	return base64.StdEncoding.EncodeToString(sig), nil
 }
 
-// Verify 校验签名是否匹配消息（对消息做 SHA-256 摘要），返回 (是否有效, 操作性错误)。
+// Verify 校验签名是否匹配消息，返回 (是否有效, 操作性错误)。
+// 摘要与编码选项须与签名时一致（默认按曲线选择摘要 + DER）；P1363 签名长度与曲线不符时返回 (false, nil)。
 // 公钥为 nil 时返回 (false, ErrInvalidPublicKey)。
-func Verify(pub *ecdsa.PublicKey, msg, sig []byte) (bool, error) {
+func Verify(pub *ecdsa.PublicKey, msg, sig []byte, opts ...Option) (bool, error) {
 	if pub == nil {
 		return false, ErrInvalidPublicKey
 	}
-	digest := sha256.Sum256(msg)
-	return ecdsa.VerifyASN1(pub, digest[:], sig), nil
+	o, err := newOptions(pub.Curve, opts)
+	if err != nil {
+		return false, err
+	}
+	if o.encoding == EncodingP1363 {
+		if len(sig) != 2*scalarSize(pub.Curve) {
+			return false, nil
+		}
+		if sig, err = P1363ToDER(sig); err != nil {
+			return false, nil
+		}
+	}
+	return ecdsa.VerifyASN1(pub, o.digest(msg), sig), nil
 }
 
 // VerifyBase64 校验 Base64 编码的签名；Base64 非法时返回错误。
-func VerifyBase64(pub *ecdsa.PublicKey, msg []byte, sigB64 string) (bool, error) {
+func VerifyBase64(pub *ecdsa.PublicKey, msg []byte, sigB64 string, opts ...Option) (bool, error) {
 	sig, err := base64.StdEncoding.DecodeString(sigB64)
 	if err != nil {
 		return false, err
 	}
-	return Verify(pub, msg, sig)
+	return Verify(pub, msg, sig, opts...)
 }
 
 // MarshalPrivateKeyPEM 将私钥编码为 PKCS#8 PEM。
@@ -0,0 +1,72 @@
+package ecdsa
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"math/big"
+
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// ErrInvalidSignature 表示签名编码非法，无法在 DER 与 P1363 之间转换。
+var ErrInvalidSignature = errors.New("ecdsa: invalid signature encoding")
+
+// DERToP1363 将 ASN.1 DER 签名转换为 IEEE P1363 定长 r||s（JWS ES256/384/512、WebCrypto 使用的格式），
+// r、s 各按曲线阶的字节长度左补零（P-256 共 64 字节、P-384 96 字节、P-521 132 字节）。
+func DERToP1363(sig []byte, curve elliptic.Curve) ([]byte, error) {
+	if curve == nil {
+		return nil, ErrInvalidSignature
+	}
+	r, s, err := parseDER(sig)
+	if err != nil {
+		return nil, err
+	}
+	size := scalarSize(curve)
+	if r.BitLen() > size*8 || s.BitLen() > size*8 {
+		return nil, ErrInvalidSignature
+	}
+	out := make([]byte, 2*size)
+	r.FillBytes(out[:size])
+	s.FillBytes(out[size:])
+	return out, nil
+}
+
+// P1363ToDER 将 IEEE P1363 定长 r||s 签名转换为 ASN.1 DER。
+func P1363ToDER(sig []byte) ([]byte, error) {
+	if len(sig) == 0 || len(sig)%2 != 0 {
+		return nil, ErrInvalidSignature
+	}
+	half := len(sig) / 2
+	r := new(big.Int).SetBytes(sig[:half])
+	s := new(big.Int).SetBytes(sig[half:])
+	if r.Sign() == 0 || s.Sign() == 0 {
+		return nil, ErrInvalidSignature
+	}
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BigInt(r)
+		b.AddASN1BigInt(s)
+	})
+	return b.Bytes()
+}
+
+func parseDER(sig []byte) (*big.Int, *big.Int, error) {
+	var inner cryptobyte.String
+	r, s := new(big.Int), new(big.Int)
+	input := cryptobyte.String(sig)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!input.Empty() ||
+		!inner.ReadASN1Integer(r) ||
+		!inner.ReadASN1Integer(s) ||
+		!inner.Empty() ||
+		r.Sign() <= 0 || s.Sign() <= 0 {
+		return nil, nil, ErrInvalidSignature
+	}
+	return r, s, nil
+}
+
+// scalarSize 返回曲线阶的字节长度（P-521 为 66）。
+func scalarSize(curve elliptic.Curve) int {
+	return (curve.Params().N.BitLen() + 7) / 8
+}
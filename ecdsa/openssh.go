@@ -0,0 +1,112 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/gtkit/encry/internal/sshkey"
+	"golang.org/x/crypto/ssh"
+)
+
+// pemTypeOpenSSH 为 ssh-keygen 默认私钥格式的 PEM 类型。
+const pemTypeOpenSSH = "OPENSSH PRIVATE KEY"
+
+// ParseOpenSSHPrivateKey 解析未加密的 OpenSSH 格式 ECDSA 私钥（ssh-keygen -t ecdsa 生成的 id_ecdsa）。
+// 私钥受口令保护时返回 *ssh.PassphraseMissingError。
+func ParseOpenSSHPrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	if err := checkOpenSSHBlock(data); err != nil {
+		return nil, err
+	}
+	key, err := ssh.ParseRawPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidPrivateKey
+	}
+	return priv, nil
+}
+
+// ParseOpenSSHPrivateKeyWithPassphrase 使用口令解析加密的 OpenSSH 格式 ECDSA 私钥。
+func ParseOpenSSHPrivateKeyWithPassphrase(data, passphrase []byte) (*ecdsa.PrivateKey, error) {
+	if err := checkOpenSSHBlock(data); err != nil {
+		return nil, err
+	}
+	key, err := ssh.ParseRawPrivateKeyWithPassphrase(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidPrivateKey
+	}
+	return priv, nil
+}
+
+// MarshalOpenSSHPrivateKey 将私钥编码为未加密的 OpenSSH 格式（仅支持 P-256/384/521）。
+func MarshalOpenSSHPrivateKey(priv *ecdsa.PrivateKey, comment string) ([]byte, error) {
+	if priv == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	block, err := ssh.MarshalPrivateKey(priv, comment)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// MarshalOpenSSHPrivateKeyWithPassphrase 将私钥编码为口令加密的 OpenSSH 格式。
+func MarshalOpenSSHPrivateKeyWithPassphrase(priv *ecdsa.PrivateKey, comment string, passphrase []byte) ([]byte, error) {
+	if priv == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(priv, comment, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// ParseAuthorizedKey 解析 authorized_keys / id_ecdsa.pub 中的一行，返回公钥与注释。
+func ParseAuthorizedKey(line []byte) (*ecdsa.PublicKey, string, error) {
+	sshKey, comment, _, _, err := ssh.ParseAuthorizedKey(line)
+	if err != nil {
+		return nil, "", err
+	}
+	cryptoKey, ok := sshKey.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, "", ErrInvalidPublicKey
+	}
+	pub, ok := cryptoKey.CryptoPublicKey().(*ecdsa.PublicKey)
+	if !ok {
+		return nil, "", ErrInvalidPublicKey
+	}
+	return pub, comment, nil
+}
+
+// MarshalAuthorizedKey 将公钥编码为 authorized_keys 行（"ecdsa-sha2-nistp256 AAAA... comment\n"）。
+// 注释含 CR/LF 时返回 ErrInvalidComment。
+func MarshalAuthorizedKey(pub *ecdsa.PublicKey, comment string) ([]byte, error) {
+	if pub == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	sshKey, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	line, err := sshkey.MarshalAuthorizedKey(sshKey, comment)
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa: %w", err)
+	}
+	return line, nil
+}
+
+func checkOpenSSHBlock(data []byte) error {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemTypeOpenSSH {
+		return ErrInvalidPrivateKey
+	}
+	return nil
+}
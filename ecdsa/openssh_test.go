@@ -0,0 +1,138 @@
+package ecdsa_test
+
+import (
+	stdecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/ecdsa"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// testdata 中的密钥由 ssh-keygen 生成；id_ecdsa384_enc 的口令为 "s3cret"。
+func TestOpenSSHFixtures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		file       string
+		passphrase []byte
+		curve      elliptic.Curve
+		comment    string
+	}{
+		{name: "p256", file: "id_ecdsa", curve: elliptic.P256(), comment: "ci@example.com"},
+		{name: "p384 encrypted", file: "id_ecdsa384_enc", passphrase: []byte("s3cret"), curve: elliptic.P384(), comment: "enc@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			privData, err := os.ReadFile(filepath.Join("testdata", tt.file))
+			require.NoError(t, err)
+			pubLine, err := os.ReadFile(filepath.Join("testdata", tt.file+".pub"))
+			require.NoError(t, err)
+
+			var priv *stdecdsa.PrivateKey
+			if tt.passphrase != nil {
+				_, err = ecdsa.ParseOpenSSHPrivateKey(privData)
+				var missing *ssh.PassphraseMissingError
+				require.ErrorAs(t, err, &missing)
+				priv, err = ecdsa.ParseOpenSSHPrivateKeyWithPassphrase(privData, tt.passphrase)
+			} else {
+				priv, err = ecdsa.ParseOpenSSHPrivateKey(privData)
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.curve, priv.Curve)
+
+			pub, comment, err := ecdsa.ParseAuthorizedKey(pubLine)
+			require.NoError(t, err)
+			require.Equal(t, tt.comment, comment)
+			require.True(t, priv.PublicKey.Equal(pub))
+
+			line, err := ecdsa.MarshalAuthorizedKey(pub, comment)
+			require.NoError(t, err)
+			require.Equal(t, string(pubLine), string(line))
+		})
+	}
+}
+
+func TestOpenSSHRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKey()
+	require.NoError(t, err)
+
+	data, err := ecdsa.MarshalOpenSSHPrivateKey(priv, "ci")
+	require.NoError(t, err)
+	got, err := ecdsa.ParseOpenSSHPrivateKey(data)
+	require.NoError(t, err)
+	require.True(t, priv.Equal(got))
+
+	encrypted, err := ecdsa.MarshalOpenSSHPrivateKeyWithPassphrase(priv, "ci", []byte("pw"))
+	require.NoError(t, err)
+	got, err = ecdsa.ParseOpenSSHPrivateKeyWithPassphrase(encrypted, []byte("pw"))
+	require.NoError(t, err)
+	require.True(t, priv.Equal(got))
+
+	line, err := ecdsa.MarshalAuthorizedKey(&priv.PublicKey, "")
+	require.NoError(t, err)
+	pub, _, err := ecdsa.ParseAuthorizedKey(line)
+	require.NoError(t, err)
+	require.True(t, priv.PublicKey.Equal(pub))
+}
+
+func TestOpenSSHErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ecdsa.MarshalOpenSSHPrivateKey(nil, "")
+	require.ErrorIs(t, err, ecdsa.ErrInvalidPrivateKey)
+	_, err = ecdsa.MarshalOpenSSHPrivateKeyWithPassphrase(nil, "", nil)
+	require.ErrorIs(t, err, ecdsa.ErrInvalidPrivateKey)
+	_, err = ecdsa.MarshalAuthorizedKey(nil, "")
+	require.ErrorIs(t, err, ecdsa.ErrInvalidPublicKey)
+
+	priv, err := ecdsa.GenerateKey()
+	require.NoError(t, err)
+	pkcs8, err := ecdsa.MarshalPrivateKeyPEM(priv)
+	require.NoError(t, err)
+	_, err = ecdsa.ParseOpenSSHPrivateKey(pkcs8)
+	require.ErrorIs(t, err, ecdsa.ErrInvalidPrivateKey)
+	_, err = ecdsa.ParseOpenSSHPrivateKeyWithPassphrase(pkcs8, []byte("pw"))
+	require.ErrorIs(t, err, ecdsa.ErrInvalidPrivateKey)
+
+	edPub, err := os.ReadFile(filepath.Join("..", "ed", "testdata", "id_ed25519.pub"))
+	require.NoError(t, err)
+	_, _, err = ecdsa.ParseAuthorizedKey(edPub)
+	require.ErrorIs(t, err, ecdsa.ErrInvalidPublicKey)
+	edPriv, err := os.ReadFile(filepath.Join("..", "ed", "testdata", "id_ed25519"))
+	require.NoError(t, err)
+	_, err = ecdsa.ParseOpenSSHPrivateKey(edPriv)
+	require.ErrorIs(t, err, ecdsa.ErrInvalidPrivateKey)
+}
+
+func TestMarshalAuthorizedKeyRejectsLineInjection(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKey()
+	require.NoError(t, err)
+	attacker, err := ecdsa.MarshalAuthorizedKey(&priv.PublicKey, "attacker")
+	require.NoError(t, err)
+
+	// 注释中的换行会在 authorized_keys 中多出一行受信任的公钥。
+	for _, comment := range []string{"ci\n" + string(attacker), "ci\r" + string(attacker), "ci\n"} {
+		line, err := ecdsa.MarshalAuthorizedKey(&priv.PublicKey, comment)
+		require.ErrorIs(t, err, ecdsa.ErrInvalidComment)
+		require.True(t, strings.HasPrefix(err.Error(), "ecdsa: "), err.Error())
+		require.Nil(t, line)
+	}
+
+	line, err := ecdsa.MarshalAuthorizedKey(&priv.PublicKey, "ci runner #1")
+	require.NoError(t, err)
+	_, comment, err := ecdsa.ParseAuthorizedKey(line)
+	require.NoError(t, err)
+	require.Equal(t, "ci runner #1", comment)
+}
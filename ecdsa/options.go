@@ -0,0 +1,89 @@
+package ecdsa
+
+import (
+	"crypto"
+	"crypto/elliptic"
+)
+
+// Encoding 表示签名的编码格式。
+type Encoding int
+
+const (
+	// EncodingDER 为 ASN.1 DER 编码（默认，X.509/TLS/OpenSSL 使用）。
+	EncodingDER Encoding = iota
+	// EncodingP1363 为 IEEE P1363 定长 r||s 编码（JWS ES256/384/512、WebCrypto 使用）。
+	EncodingP1363
+)
+
+// Option 用于定制 Sign/Verify 的摘要算法与签名编码（Functional Options）。
+type Option func(*options)
+
+type options struct {
+	// hash 为 0 表示按曲线选择（HashForCurve）。
+	hash          crypto.Hash
+	encoding      Encoding
+	deterministic bool
+}
+
+// WithHash 显式指定摘要算法（SHA-256、SHA-384、SHA-512），覆盖按曲线选择的默认值，
+// 用于对接固定使用某一摘要的外部系统（如 P-384 密钥配 SHA-256）。
+func WithHash(h crypto.Hash) Option {
+	return func(o *options) { o.hash = h }
+}
+
+// WithCurveHash 按曲线选择摘要算法（见 HashForCurve），即默认行为；可用于撤销此前的 WithHash。
+func WithCurveHash() Option {
+	return func(o *options) { o.hash = 0 }
+}
+
+// WithDeterministic 使用 RFC 6979 确定性 nonce 签名：同一私钥、摘要算法与消息总得到同一签名，
+// 签名安全性不依赖运行时随机数质量，便于审计复现。仅影响签名，验签无需此选项。
+// 默认仍为随机化签名（nonce 混入随机数，可抵御部分故障注入/侧信道攻击）。
+func WithDeterministic() Option {
+	return func(o *options) { o.deterministic = true }
+}
+
+// WithEncoding 指定签名编码，默认 EncodingDER。
+func WithEncoding(e Encoding) Option {
+	return func(o *options) { o.encoding = e }
+}
+
+// HashForCurve 返回与曲线安全强度匹配的摘要算法，也是 Sign/Verify 的默认摘要：
+// P-256 → SHA-256、P-384 → SHA-384、P-521 → SHA-512（与 JWS ES256/ES384/ES512 一致），未知曲线返回 SHA-256。
+func HashForCurve(curve elliptic.Curve) crypto.Hash {
+	switch curve {
+	case elliptic.P384():
+		return crypto.SHA384
+	case elliptic.P521():
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
+
+func newOptions(curve elliptic.Curve, opts []Option) (*options, error) {
+	o := &options{encoding: EncodingDER}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	if o.hash == 0 {
+		o.hash = HashForCurve(curve)
+	}
+	switch o.hash {
+	case crypto.SHA256, crypto.SHA384, crypto.SHA512:
+	default:
+		return nil, ErrUnsupportedHash
+	}
+	if o.encoding != EncodingDER && o.encoding != EncodingP1363 {
+		return nil, ErrInvalidSignature
+	}
+	return o, nil
+}
+
+func (o *options) digest(msg []byte) []byte {
+	h := o.hash.New()
+	h.Write(msg)
+	return h.Sum(nil)
+}
@@ -0,0 +1,229 @@
+package ecdsa_test
+
+import (
+	"crypto"
+	stdecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gtkit/encry/ecdsa"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/p{384,521}.* 由 `openssl dgst -sha384/-sha512 -sign` 生成的 DER 签名及对应公钥。
+func TestVerifyOpenSSLCurveHash(t *testing.T) {
+	t.Parallel()
+
+	msg, err := os.ReadFile(filepath.Join("testdata", "jws_input.txt"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		pub   string
+		sig   string
+		curve elliptic.Curve
+		size  int
+	}{
+		{name: "P-384 SHA-384", pub: "p384.pub.pem", sig: "p384.sha384.der", curve: elliptic.P384(), size: 96},
+		{name: "P-521 SHA-512", pub: "p521.pub.pem", sig: "p521.sha512.der", curve: elliptic.P521(), size: 132},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pubPEM, err := os.ReadFile(filepath.Join("testdata", tt.pub))
+			require.NoError(t, err)
+			pub, err := ecdsa.ParsePublicKeyPEM(pubPEM)
+			require.NoError(t, err)
+			der, err := os.ReadFile(filepath.Join("testdata", tt.sig))
+			require.NoError(t, err)
+
+			// 默认按曲线选择摘要，与 openssl 一致。
+			ok, err := ecdsa.Verify(pub, msg, der)
+			require.NoError(t, err)
+			require.True(t, ok)
+			ok, err = ecdsa.Verify(pub, msg, der, ecdsa.WithCurveHash())
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			// 显式指定 SHA-256 时摘要不同，验签失败。
+			ok, err = ecdsa.Verify(pub, msg, der, ecdsa.WithHash(crypto.SHA256))
+			require.NoError(t, err)
+			require.False(t, ok)
+
+			p1363, err := ecdsa.DERToP1363(der, tt.curve)
+			require.NoError(t, err)
+			require.Len(t, p1363, tt.size)
+			ok, err = ecdsa.Verify(pub, msg, p1363, ecdsa.WithCurveHash(), ecdsa.WithEncoding(ecdsa.EncodingP1363))
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			back, err := ecdsa.P1363ToDER(p1363)
+			require.NoError(t, err)
+			require.Equal(t, der, back)
+		})
+	}
+}
+
+func TestSignOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		curve elliptic.Curve
+		hash  crypto.Hash
+		size  int
+	}{
+		{name: "ES256", curve: elliptic.P256(), hash: crypto.SHA256, size: 64},
+		{name: "ES384", curve: elliptic.P384(), hash: crypto.SHA384, size: 96},
+		{name: "ES512", curve: elliptic.P521(), hash: crypto.SHA512, size: 132},
+	}
+	msg := []byte("header.payload")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.hash, ecdsa.HashForCurve(tt.curve))
+
+			priv, err := ecdsa.GenerateKeyWithCurve(tt.curve)
+			require.NoError(t, err)
+
+			sig, err := ecdsa.Sign(priv, msg, ecdsa.WithCurveHash(), ecdsa.WithEncoding(ecdsa.EncodingP1363))
+			require.NoError(t, err)
+			require.Len(t, sig, tt.size)
+
+			// 与直接用标准库按曲线摘要验签一致。
+			h := tt.hash.New()
+			h.Write(msg)
+			half := tt.size / 2
+			r := new(big.Int).SetBytes(sig[:half])
+			s := new(big.Int).SetBytes(sig[half:])
+			require.True(t, stdecdsa.Verify(&priv.PublicKey, h.Sum(nil), r, s))
+
+			ok, err := ecdsa.Verify(&priv.PublicKey, msg, sig, ecdsa.WithHash(tt.hash), ecdsa.WithEncoding(ecdsa.EncodingP1363))
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			// 编码不一致时判为无效而非报错。
+			ok, err = ecdsa.Verify(&priv.PublicKey, msg, sig, ecdsa.WithCurveHash())
+			require.NoError(t, err)
+			require.False(t, ok)
+			ok, err = ecdsa.Verify(&priv.PublicKey, msg, sig[1:], ecdsa.WithCurveHash(), ecdsa.WithEncoding(ecdsa.EncodingP1363))
+			require.NoError(t, err)
+			require.False(t, ok)
+
+			b64, err := ecdsa.SignBase64(priv, msg, ecdsa.WithCurveHash())
+			require.NoError(t, err)
+			ok, err = ecdsa.VerifyBase64(&priv.PublicKey, msg, b64, ecdsa.WithCurveHash())
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+	}
+}
+
+func TestDefaultHashFollowsCurve(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		curve elliptic.Curve
+		hash  crypto.Hash
+	}{
+		{name: "P-256 SHA-256", curve: elliptic.P256(), hash: crypto.SHA256},
+		{name: "P-384 SHA-384", curve: elliptic.P384(), hash: crypto.SHA384},
+		{name: "P-521 SHA-512", curve: elliptic.P521(), hash: crypto.SHA512},
+	}
+	msg := []byte("header.payload")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			priv, err := ecdsa.GenerateKeyWithCurve(tt.curve)
+			require.NoError(t, err)
+			sig, err := ecdsa.Sign(priv, msg)
+			require.NoError(t, err)
+
+			h := tt.hash.New()
+			h.Write(msg)
+			require.True(t, stdecdsa.VerifyASN1(&priv.PublicKey, h.Sum(nil), sig))
+
+			ok, err := ecdsa.Verify(&priv.PublicKey, msg, sig, ecdsa.WithHash(tt.hash))
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+	}
+}
+
+func TestWithHashOverridesCurveHash(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKeyWithCurve(elliptic.P384())
+	require.NoError(t, err)
+	msg := []byte("m")
+	digest := sha256.Sum256(msg)
+
+	sig, err := ecdsa.Sign(priv, msg, ecdsa.WithHash(crypto.SHA256))
+	require.NoError(t, err)
+	require.True(t, stdecdsa.VerifyASN1(&priv.PublicKey, digest[:], sig))
+	ok, err := ecdsa.Verify(&priv.PublicKey, msg, sig)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// 后出现的选项生效。
+	sig, err = ecdsa.Sign(priv, msg, ecdsa.WithCurveHash(), ecdsa.WithHash(crypto.SHA256))
+	require.NoError(t, err)
+	require.True(t, stdecdsa.VerifyASN1(&priv.PublicKey, digest[:], sig))
+	sig, err = ecdsa.Sign(priv, msg, ecdsa.WithHash(crypto.SHA256), ecdsa.WithCurveHash())
+	require.NoError(t, err)
+	require.False(t, stdecdsa.VerifyASN1(&priv.PublicKey, digest[:], sig))
+}
+
+func TestSignatureConversion(t *testing.T) {
+	t.Parallel()
+
+	// r 的高位字节为 0 时 P1363 需左补零，DER 则去掉前导零。
+	r := big.NewInt(0x1234)
+	s := new(big.Int).Lsh(big.NewInt(1), 255) // 最高位为 1，DER 需补 0x00
+	p1363 := make([]byte, 64)
+	r.FillBytes(p1363[:32])
+	s.FillBytes(p1363[32:])
+
+	der, err := ecdsa.P1363ToDER(p1363)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x30, 0x27, 0x02, 0x02, 0x12, 0x34, 0x02, 0x21, 0x00, 0x80}, der[:10])
+
+	back, err := ecdsa.DERToP1363(der, elliptic.P256())
+	require.NoError(t, err)
+	require.Equal(t, p1363, back)
+}
+
+func TestSignatureConversionErrors(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKeyWithCurve(elliptic.P521())
+	require.NoError(t, err)
+	der, err := ecdsa.Sign(priv, []byte("m"), ecdsa.WithCurveHash())
+	require.NoError(t, err)
+
+	_, err = ecdsa.DERToP1363(der, nil)
+	require.ErrorIs(t, err, ecdsa.ErrInvalidSignature)
+	// P-521 的 r/s 放不进 P-256 的 32 字节。
+	_, err = ecdsa.DERToP1363(der, elliptic.P256())
+	require.ErrorIs(t, err, ecdsa.ErrInvalidSignature)
+	_, err = ecdsa.DERToP1363(append(der, 0), elliptic.P521())
+	require.ErrorIs(t, err, ecdsa.ErrInvalidSignature)
+	_, err = ecdsa.DERToP1363([]byte{0x30, 0x00}, elliptic.P256())
+	require.ErrorIs(t, err, ecdsa.ErrInvalidSignature)
+
+	for _, sig := range [][]byte{nil, {1, 2, 3}, make([]byte, 64)} {
+		_, err = ecdsa.P1363ToDER(sig)
+		require.ErrorIs(t, err, ecdsa.ErrInvalidSignature)
+	}
+
+	_, err = ecdsa.Sign(priv, []byte("m"), ecdsa.WithHash(crypto.MD5))
+	require.ErrorIs(t, err, ecdsa.ErrUnsupportedHash)
+	_, err = ecdsa.Verify(&priv.PublicKey, []byte("m"), der, ecdsa.WithHash(crypto.SHA1))
+	require.ErrorIs(t, err, ecdsa.ErrUnsupportedHash)
+}
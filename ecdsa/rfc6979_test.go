@@ -0,0 +1,111 @@
+package ecdsa_test
+
+import (
+	"crypto"
+	stdecdsa "crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/ecdsa"
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 6979 附录 A.2.5（P-256）与 A.2.6（P-384）测试向量，签名以 P1363 r||s 比对。
+func TestDeterministicRFC6979Vectors(t *testing.T) {
+	t.Parallel()
+
+	const (
+		p256D = "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721"
+		p384D = "6B9D3DAD2E1B8C1C05B19875B6659F4DE23C3B667BF297BA9AA47740787137D896D5724E4C70A825F872C9EA60D2EDF5"
+	)
+	tests := []struct {
+		name  string
+		curve elliptic.Curve
+		d     string
+		hash  crypto.Hash
+		msg   string
+		r, s  string
+	}{
+		{
+			name: "P-256 SHA-256 sample", curve: elliptic.P256(), d: p256D, hash: crypto.SHA256, msg: "sample",
+			r: "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716",
+			s: "F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8",
+		},
+		{
+			name: "P-256 SHA-256 test", curve: elliptic.P256(), d: p256D, hash: crypto.SHA256, msg: "test",
+			r: "F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367",
+			s: "019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083",
+		},
+		{
+			name: "P-384 SHA-384 sample", curve: elliptic.P384(), d: p384D, hash: crypto.SHA384, msg: "sample",
+			r: "94EDBB92A5ECB8AAD4736E56C691916B3F88140666CE9FA73D64C4EA95AD133C81A648152E44ACF96E36DD1E80FABE46",
+			s: "99EF4AEB15F178CEA1FE40DB2603138F130E740A19624526203B6351D0A3A94FA329C145786E679E7B82C71A38628AC8",
+		},
+		{
+			name: "P-384 SHA-384 test", curve: elliptic.P384(), d: p384D, hash: crypto.SHA384, msg: "test",
+			r: "8203B63D3C853E8D77227FB377BCF7B7B772E97892A80F36AB775D509D7A5FEB0542A7F0812998DA8F1DD3CA3CF023DB",
+			s: "DDD0760448D42D8A43AF45AF836FCE4DE8BE06B485E9B61B827C2F13173923E06A739F040649A667BF3B828246BAA5A5",
+		},
+		{
+			name: "P-384 SHA-256 sample", curve: elliptic.P384(), d: p384D, hash: crypto.SHA256, msg: "sample",
+			r: "21B13D1E013C7FA1392D03C5F99AF8B30C570C6F98D4EA8E354B63A21D3DAA33BDE1E888E63355D92FA2B3C36D8FB2CD",
+			s: "F3AA443FB107745BF4BD77CB3891674632068A10CA67E3D45DB2266FA7D1FEEBEFDC63ECCD1AC42EC0CB8668A4FA0AB0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			d, err := hex.DecodeString(tt.d)
+			require.NoError(t, err)
+			priv, err := stdecdsa.ParseRawPrivateKey(tt.curve, d)
+			require.NoError(t, err)
+
+			sig, err := ecdsa.Sign(priv, []byte(tt.msg),
+				ecdsa.WithDeterministic(), ecdsa.WithHash(tt.hash), ecdsa.WithEncoding(ecdsa.EncodingP1363))
+			require.NoError(t, err)
+			require.Equal(t, strings.ToLower(tt.r+tt.s), hex.EncodeToString(sig))
+
+			ok, err := ecdsa.Verify(&priv.PublicKey, []byte(tt.msg), sig, ecdsa.WithHash(tt.hash), ecdsa.WithEncoding(ecdsa.EncodingP1363))
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+	}
+}
+
+func TestDeterministicReproducible(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdsa.GenerateKeyWithCurve(elliptic.P521())
+	require.NoError(t, err)
+	msg := []byte("audit record #42")
+
+	a, err := ecdsa.Sign(priv, msg, ecdsa.WithDeterministic(), ecdsa.WithCurveHash())
+	require.NoError(t, err)
+	b, err := ecdsa.Sign(priv, msg, ecdsa.WithDeterministic(), ecdsa.WithCurveHash())
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+
+	// 默认随机化签名每次不同。
+	c, err := ecdsa.Sign(priv, msg, ecdsa.WithCurveHash())
+	require.NoError(t, err)
+	d, err := ecdsa.Sign(priv, msg, ecdsa.WithCurveHash())
+	require.NoError(t, err)
+	require.NotEqual(t, c, d)
+
+	// 消息或摘要算法不同，确定性签名也不同。
+	e, err := ecdsa.Sign(priv, []byte("audit record #43"), ecdsa.WithDeterministic(), ecdsa.WithCurveHash())
+	require.NoError(t, err)
+	require.NotEqual(t, a, e)
+	f, err := ecdsa.Sign(priv, msg, ecdsa.WithDeterministic(), ecdsa.WithHash(crypto.SHA256))
+	require.NoError(t, err)
+	require.NotEqual(t, a, f)
+
+	for _, sig := range [][]byte{a, c} {
+		ok, err := ecdsa.Verify(&priv.PublicKey, msg, sig, ecdsa.WithCurveHash())
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+}
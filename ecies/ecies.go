@@ -0,0 +1,195 @@
+// Package ecies 提供 NIST 曲线（P-256/P-384/P-521）上的 ECIES 公钥加密，
+// 与移动端常见实现互通：
+//
+//   - Apple CryptoKit / Security 框架：eciesEncryptionStandardVariableIVX963SHA256AESGCM
+//     （AppleVariableIV，默认）与 eciesEncryptionStandardX963SHA256AESGCM（AppleX963）；
+//     Cofactor 变体在这些素数阶曲线上结果相同，同样适用。
+//   - Android Tink：ECIES-AEAD-HKDF + AES-GCM（Tink）。
+//
+// 每次加密生成一把临时密钥，密文以未压缩的临时公钥（0x04 || X || Y）开头。
+// 接收方公钥可用 ecdh.ParsePublicKey 从 X9.63 编码（CryptoKit 的 x963Representation）解析。
+//
+// 新系统之间通信优先使用 hpke；本包用于对接上述平台。
+package ecies
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/gtkit/encry/hkdf"
+	"github.com/gtkit/encry/kdf"
+)
+
+const (
+	// appleIVSize 为 Apple 变体 AES-GCM 使用的 16 字节 IV。
+	appleIVSize = 16
+	// tinkIVSize 为 Tink AES-GCM DEM 的 12 字节随机 IV。
+	tinkIVSize = 12
+	tagSize    = 16
+)
+
+var (
+	// ErrInvalidPublicKey 表示接收方公钥为空。
+	ErrInvalidPublicKey = errors.New("ecies: invalid public key")
+	// ErrInvalidPrivateKey 表示接收方私钥为空。
+	ErrInvalidPrivateKey = errors.New("ecies: invalid private key")
+	// ErrUnsupportedCurve 表示密钥不在 P-256/P-384/P-521 上（X25519 请使用 hpke）。
+	ErrUnsupportedCurve = errors.New("ecies: unsupported curve")
+	// ErrUnsupportedScheme 表示 WithScheme 指定的变体不存在。
+	ErrUnsupportedScheme = errors.New("ecies: unsupported scheme")
+	// ErrInvalidCiphertext 表示密文格式非法、临时公钥无效或认证失败。
+	ErrInvalidCiphertext = errors.New("ecies: invalid ciphertext")
+)
+
+// Encrypt 用接收方公钥加密 plainText。
+func Encrypt(publicKey *ecdh.PublicKey, plainText []byte, opts ...Option) ([]byte, error) {
+	if publicKey == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	ephemeral, err := publicKey.Curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return encrypt(publicKey, ephemeral, rand.Reader, plainText, opts)
+}
+
+func encrypt(publicKey *ecdh.PublicKey, ephemeral *ecdh.PrivateKey, random io.Reader, plainText []byte, opts []Option) ([]byte, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pointSize(publicKey.Curve()); err != nil {
+		return nil, err
+	}
+	z, err := ephemeral.ECDH(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(z)
+
+	ephemeralPub := ephemeral.PublicKey().Bytes()
+	aead, nonce, err := o.dem(publicKey.Curve(), ephemeralPub, z)
+	if err != nil {
+		return nil, err
+	}
+	out := bytes.Clone(ephemeralPub)
+	if o.scheme == Tink {
+		nonce = make([]byte, tinkIVSize)
+		if _, err := io.ReadFull(random, nonce); err != nil {
+			return nil, err
+		}
+		out = append(out, nonce...)
+	}
+	return aead.Seal(out, nonce, plainText, nil), nil
+}
+
+// Decrypt 用接收方私钥解密 Encrypt（或 Apple/Tink 对应变体）生成的密文，选项须与加密时一致。
+func Decrypt(privateKey *ecdh.PrivateKey, cipherText []byte, opts ...Option) ([]byte, error) {
+	if privateKey == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	curve := privateKey.Curve()
+	size, err := pointSize(curve)
+	if err != nil {
+		return nil, err
+	}
+	overhead := size + tagSize
+	if o.scheme == Tink {
+		overhead += tinkIVSize
+	}
+	if len(cipherText) < overhead || cipherText[0] != 0x04 {
+		return nil, ErrInvalidCiphertext
+	}
+
+	ephemeralPub := cipherText[:size]
+	peer, err := curve.NewPublicKey(ephemeralPub)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	z, err := privateKey.ECDH(peer)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	defer clear(z)
+
+	aead, nonce, err := o.dem(curve, ephemeralPub, z)
+	if err != nil {
+		return nil, err
+	}
+	payload := cipherText[size:]
+	if o.scheme == Tink {
+		nonce, payload = payload[:tinkIVSize], payload[tinkIVSize:]
+	}
+	plainText, err := aead.Open(nil, nonce, payload, nil)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plainText, nil
+}
+
+// dem 由共享密钥派生 AES-GCM 实例；Apple 变体同时返回 IV，Tink 变体的 IV 随密文传输。
+func (o *options) dem(curve ecdh.Curve, ephemeralPub, z []byte) (cipher.AEAD, []byte, error) {
+	var (
+		key, nonce []byte
+		err        error
+	)
+	switch o.scheme {
+	case AppleVariableIV, AppleX963:
+		keySize := 32
+		if curve == ecdh.P256() {
+			keySize = 16
+		}
+		n := keySize
+		if o.scheme == AppleVariableIV {
+			n += appleIVSize
+		}
+		material, err := kdf.X963(z, ephemeralPub, n, kdf.WithHash(o.hash))
+		if err != nil {
+			return nil, nil, err
+		}
+		key, nonce = material[:keySize], make([]byte, appleIVSize)
+		copy(nonce, material[keySize:])
+	case Tink:
+		ikm := append(bytes.Clone(ephemeralPub), z...)
+		defer clear(ikm)
+		key, err = hkdf.Derive(ikm, o.salt, string(o.info), o.keySize, hkdf.WithHash(o.hash))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	defer clear(key)
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o.scheme == Tink {
+		aead, err := cipher.NewGCM(block)
+		return aead, nil, err
+	}
+	aead, err := cipher.NewGCMWithNonceSize(block, appleIVSize)
+	return aead, nonce, err
+}
+
+// pointSize 返回曲线未压缩点的字节长度，仅支持 NIST 曲线。
+func pointSize(curve ecdh.Curve) (int, error) {
+	switch curve {
+	case ecdh.P256():
+		return 65, nil
+	case ecdh.P384():
+		return 97, nil
+	case ecdh.P521():
+		return 133, nil
+	default:
+		return 0, ErrUnsupportedCurve
+	}
+}
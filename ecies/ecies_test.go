@@ -0,0 +1,237 @@
+package ecies_test
+
+import (
+	"crypto"
+	stdecdh "crypto/ecdh"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/gtkit/encry/ecdh"
+	"github.com/gtkit/encry/ecies"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+type vector struct {
+	Name       string `json:"name"`
+	Source     string `json:"source"`
+	Curve      string `json:"curve"`
+	Scheme     string `json:"scheme"`
+	Hash       string `json:"hash"`
+	KeySize    int    `json:"keySize"`
+	Salt       string `json:"salt"`
+	Info       string `json:"info"`
+	Recipient  string `json:"recipient"`
+	Ephemeral  string `json:"ephemeral"`
+	IV         string `json:"iv"`
+	PlainText  string `json:"plainText"`
+	CipherText string `json:"cipherText"`
+}
+
+func (v vector) options(t *testing.T) []ecies.Option {
+	t.Helper()
+	schemes := map[string]ecies.Scheme{"AppleVariableIV": ecies.AppleVariableIV, "AppleX963": ecies.AppleX963, "Tink": ecies.Tink}
+	hashes := map[string]crypto.Hash{"SHA-256": crypto.SHA256, "SHA-384": crypto.SHA384, "SHA-512": crypto.SHA512}
+	opts := []ecies.Option{ecies.WithScheme(schemes[v.Scheme]), ecies.WithHash(hashes[v.Hash])}
+	if v.Scheme == "Tink" {
+		opts = append(opts, ecies.WithAESKeySize(v.KeySize), ecies.WithSalt(mustHex(t, v.Salt)), ecies.WithContextInfo(mustHex(t, v.Info)))
+	}
+	return opts
+}
+
+// testdata/vectors.json 由 Node.js（OpenSSL）crypto 按 Apple/Tink 的公开格式独立生成：
+// ECDH、X9.63 KDF / HKDF 与 AES-GCM 均不经过本包代码。
+func TestKnownAnswer(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("testdata/vectors.json")
+	require.NoError(t, err)
+	var vectors []vector
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	require.NotEmpty(t, vectors)
+
+	curves := map[string]stdecdh.Curve{"P-256": stdecdh.P256(), "P-384": stdecdh.P384()}
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			t.Parallel()
+			curve := curves[v.Curve]
+			recipient, err := ecdh.ParsePrivateKey(curve, mustHex(t, v.Recipient))
+			require.NoError(t, err)
+			ephemeral, err := ecdh.ParsePrivateKey(curve, mustHex(t, v.Ephemeral))
+			require.NoError(t, err)
+			opts := v.options(t)
+
+			got, err := ecies.EncryptDerand(recipient.PublicKey(), ephemeral, mustHex(t, v.IV), mustHex(t, v.PlainText), opts...)
+			require.NoError(t, err)
+			require.Equal(t, v.CipherText, hex.EncodeToString(got))
+
+			plainText, err := ecies.Decrypt(recipient, mustHex(t, v.CipherText), opts...)
+			require.NoError(t, err)
+			require.Equal(t, mustHex(t, v.PlainText), plainText)
+		})
+	}
+}
+
+// testdata/platform.json 是平台库自身产出的密文（无临时私钥，只能解密）：
+// tink-go HybridEncrypt 现场生成的一条与 Tink 发布的跨语言测试向量，来源见各条 source 字段。
+// Apple SecKeyCreateEncryptedData 的密文只能在 Apple 平台上生成：在 macOS 上运行
+// testdata/apple_seckey.swift，把输出的 P-256/P-384 条目追加到 platform.json 即可由本测试覆盖。
+// 这些条目目前尚未收录，Apple 变体暂由 TestKnownAnswer 的独立实现向量覆盖。
+func TestPlatformCiphertexts(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile("testdata/platform.json")
+	require.NoError(t, err)
+	var vectors []vector
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	require.NotEmpty(t, vectors)
+
+	curves := map[string]stdecdh.Curve{"P-256": stdecdh.P256(), "P-384": stdecdh.P384(), "P-521": stdecdh.P521()}
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			t.Parallel()
+			recipient, err := ecdh.ParsePrivateKey(curves[v.Curve], mustHex(t, v.Recipient))
+			require.NoError(t, err)
+			opts := v.options(t)
+
+			plainText, err := ecies.Decrypt(recipient, mustHex(t, v.CipherText), opts...)
+			require.NoError(t, err, v.Source)
+			require.Equal(t, v.PlainText, hex.EncodeToString(plainText))
+
+			// 换 contextInfo 后认证失败。
+			_, err = ecies.Decrypt(recipient, mustHex(t, v.CipherText), append(opts, ecies.WithContextInfo([]byte("other")))...)
+			require.ErrorIs(t, err, ecies.ErrInvalidCiphertext)
+		})
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		curve stdecdh.Curve
+		opts  []ecies.Option
+	}{
+		{"默认 P-256", stdecdh.P256(), nil},
+		{"Apple X963 P-384", stdecdh.P384(), []ecies.Option{ecies.WithScheme(ecies.AppleX963)}},
+		{"Apple P-521 SHA-512", stdecdh.P521(), []ecies.Option{ecies.WithHash(crypto.SHA512)}},
+		{"Tink AES-256 P-256", stdecdh.P256(), []ecies.Option{ecies.WithScheme(ecies.Tink), ecies.WithAESKeySize(32), ecies.WithContextInfo([]byte("ctx"))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			priv, err := ecdh.Generate(tt.curve)
+			require.NoError(t, err)
+			msg := []byte("ecies round trip")
+
+			a, err := ecies.Encrypt(priv.PublicKey(), msg, tt.opts...)
+			require.NoError(t, err)
+			b, err := ecies.Encrypt(priv.PublicKey(), msg, tt.opts...)
+			require.NoError(t, err)
+			require.NotEqual(t, a, b) // 每次使用新的临时密钥
+
+			got, err := ecies.Decrypt(priv, a, tt.opts...)
+			require.NoError(t, err)
+			require.Equal(t, msg, got)
+
+			empty, err := ecies.Encrypt(priv.PublicKey(), nil, tt.opts...)
+			require.NoError(t, err)
+			got, err = ecies.Decrypt(priv, empty, tt.opts...)
+			require.NoError(t, err)
+			require.Empty(t, got)
+		})
+	}
+}
+
+func TestDecryptRejects(t *testing.T) {
+	t.Parallel()
+
+	priv, err := ecdh.Generate(stdecdh.P256())
+	require.NoError(t, err)
+	other, err := ecdh.Generate(stdecdh.P256())
+	require.NoError(t, err)
+	ct, err := ecies.Encrypt(priv.PublicKey(), []byte("secret"))
+	require.NoError(t, err)
+
+	tampered := func(i int) []byte {
+		c := append([]byte(nil), ct...)
+		c[i] ^= 1
+		return c
+	}
+	cases := map[string]struct {
+		priv *stdecdh.PrivateKey
+		ct   []byte
+		opts []ecies.Option
+	}{
+		"错误私钥":           {other, ct, nil},
+		"篡改临时公钥":         {priv, tampered(10), nil},
+		"篡改密文":           {priv, tampered(70), nil},
+		"篡改 tag":         {priv, tampered(len(ct) - 1), nil},
+		"截断":             {priv, ct[:len(ct)-17], nil},
+		"非未压缩点":          {priv, tampered(0), nil},
+		"变体不一致":          {priv, ct, []ecies.Option{ecies.WithScheme(ecies.AppleX963)}},
+		"Tink 变体解 Apple": {priv, ct, []ecies.Option{ecies.WithScheme(ecies.Tink)}},
+		"摘要不一致":          {priv, ct, []ecies.Option{ecies.WithHash(crypto.SHA384)}},
+	}
+	for name, c := range cases {
+		_, err := ecies.Decrypt(c.priv, c.ct, c.opts...)
+		require.ErrorIs(t, err, ecies.ErrInvalidCiphertext, name)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	p256, err := ecdh.Generate(stdecdh.P256())
+	require.NoError(t, err)
+	x25519, err := ecdh.GenerateX25519()
+	require.NoError(t, err)
+
+	_, err = ecies.Encrypt(nil, nil)
+	require.ErrorIs(t, err, ecies.ErrInvalidPublicKey)
+	_, err = ecies.Decrypt(nil, nil)
+	require.ErrorIs(t, err, ecies.ErrInvalidPrivateKey)
+
+	_, err = ecies.Encrypt(x25519.PublicKey(), nil)
+	require.ErrorIs(t, err, ecies.ErrUnsupportedCurve)
+	_, err = ecies.Decrypt(x25519, make([]byte, 100))
+	require.ErrorIs(t, err, ecies.ErrUnsupportedCurve)
+
+	_, err = ecies.Encrypt(p256.PublicKey(), nil, ecies.WithScheme(0))
+	require.ErrorIs(t, err, ecies.ErrUnsupportedScheme)
+	_, err = ecies.Decrypt(p256, nil, ecies.WithScheme(9))
+	require.ErrorIs(t, err, ecies.ErrUnsupportedScheme)
+	_, err = ecies.Encrypt(p256.PublicKey(), nil, ecies.WithHash(crypto.SHA1))
+	require.ErrorIs(t, err, ecies.ErrUnsupportedHash)
+	_, err = ecies.Encrypt(p256.PublicKey(), nil, ecies.WithScheme(ecies.Tink), ecies.WithAESKeySize(24))
+	require.ErrorIs(t, err, ecies.ErrInvalidKeySize)
+
+	// nil Option 被忽略。
+	_, err = ecies.Encrypt(p256.PublicKey(), nil, nil)
+	require.NoError(t, err)
+}
+
+func ExampleEncrypt() {
+	// 接收方私钥；公钥以 X9.63 编码（CryptoKit 的 x963Representation）下发给 iOS 客户端。
+	recipient, _ := ecdh.Generate(stdecdh.P256())
+	x963 := recipient.PublicKey().Bytes()
+
+	// 客户端侧：SecKeyCreateEncryptedData(.eciesEncryptionStandardVariableIVX963SHA256AESGCM)
+	// 与下面的 Encrypt 输出格式相同。
+	publicKey, _ := ecdh.ParsePublicKey(stdecdh.P256(), x963)
+	cipherText, _ := ecies.Encrypt(publicKey, []byte("hello"))
+
+	plainText, _ := ecies.Decrypt(recipient, cipherText)
+	fmt.Println(string(plainText))
+	// Output: hello
+}
@@ -0,0 +1,11 @@
+package ecies
+
+import (
+	"bytes"
+	"crypto/ecdh"
+)
+
+// EncryptDerand 使用给定的临时私钥与 Tink IV 加密，用于已知答案测试。
+func EncryptDerand(publicKey *ecdh.PublicKey, ephemeral *ecdh.PrivateKey, iv, plainText []byte, opts ...Option) ([]byte, error) {
+	return encrypt(publicKey, ephemeral, bytes.NewReader(iv), plainText, opts)
+}
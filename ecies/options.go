@@ -0,0 +1,90 @@
+package ecies
+
+import (
+	"crypto"
+	"errors"
+)
+
+// ErrUnsupportedHash 表示 WithHash 指定的摘要算法不受支持。
+var ErrUnsupportedHash = errors.New("ecies: unsupported hash")
+
+// ErrInvalidKeySize 表示 WithAESKeySize 指定的长度不是 16 或 32。
+var ErrInvalidKeySize = errors.New("ecies: AES key size must be 16 or 32 bytes")
+
+// Scheme 是 ECIES 的具体变体，加解密双方必须一致。
+type Scheme uint8
+
+const (
+	// AppleVariableIV 对应 Apple Security 框架的
+	// eciesEncryptionStandardVariableIVX963SHA256AESGCM（CryptoKit/SecKey 推荐用法，默认）：
+	// X9.63 KDF（SharedInfo 为临时公钥）输出 AES 密钥 || 16 字节 IV。
+	AppleVariableIV Scheme = iota + 1
+	// AppleX963 对应 eciesEncryptionStandardX963SHA256AESGCM：KDF 只输出 AES 密钥，IV 为 16 字节全零。
+	AppleX963
+	// Tink 对应 Google Tink 的 ECIES-AEAD-HKDF（未压缩点、AES-GCM DEM、RAW 输出前缀）：
+	// 密钥为 HKDF(ikm = 临时公钥 || Z, salt, contextInfo)，密文为 临时公钥 || IV(12) || 密文 || tag。
+	// 使用 TINK 输出前缀的密钥集时，调用方须先去掉 5 字节前缀。
+	Tink
+)
+
+// Option 用于定制 ECIES 变体与参数（Functional Options）。
+type Option func(*options)
+
+type options struct {
+	scheme  Scheme
+	hash    crypto.Hash
+	keySize int
+	salt    []byte
+	info    []byte
+}
+
+// WithScheme 指定 ECIES 变体，默认 AppleVariableIV。
+func WithScheme(s Scheme) Option {
+	return func(o *options) { o.scheme = s }
+}
+
+// WithHash 指定 KDF 摘要算法，支持 SHA-256（默认）、SHA-384、SHA-512；
+// 对应 Apple 的 ...X963SHA384AESGCM 等变体及 Tink 的 HKDF-HMAC-SHA384/512。
+func WithHash(h crypto.Hash) Option {
+	return func(o *options) { o.hash = h }
+}
+
+// WithAESKeySize 指定 Tink 变体的 AES-GCM 密钥长度（16 或 32，默认 16，
+// 即 ECIES_P256_HKDF_HMAC_SHA256_AES128_GCM 模板）。Apple 变体的密钥长度由曲线决定：
+// P-256 为 16 字节，P-384/P-521 为 32 字节，此选项对其无效。
+func WithAESKeySize(n int) Option {
+	return func(o *options) { o.keySize = n }
+}
+
+// WithSalt 指定 Tink 变体的 HKDF salt（对应密钥参数 hkdf_salt），默认为空。
+func WithSalt(salt []byte) Option {
+	return func(o *options) { o.salt = salt }
+}
+
+// WithContextInfo 指定 Tink 变体的 contextInfo（HKDF info，即 HybridEncrypt 的 contextInfo 参数）。
+func WithContextInfo(info []byte) Option {
+	return func(o *options) { o.info = info }
+}
+
+func newOptions(opts []Option) (*options, error) {
+	o := &options{scheme: AppleVariableIV, hash: crypto.SHA256, keySize: 16}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	switch o.scheme {
+	case AppleVariableIV, AppleX963, Tink:
+	default:
+		return nil, ErrUnsupportedScheme
+	}
+	switch o.hash {
+	case crypto.SHA256, crypto.SHA384, crypto.SHA512:
+	default:
+		return nil, ErrUnsupportedHash
+	}
+	if o.keySize != 16 && o.keySize != 32 {
+		return nil, ErrInvalidKeySize
+	}
+	return o, nil
+}
@@ -0,0 +1,51 @@
+// 在 macOS 上生成 Apple SecKeyCreateEncryptedData 密文，输出可直接追加到 platform.json 的条目：
+//
+//	swift ecies/testdata/apple_seckey.swift
+//
+// 每条曲线（P-256、P-384）现场生成一把 SecKey 私钥，用其公钥以
+// eciesEncryptionStandardVariableIVX963SHA256AESGCM 加密固定明文；
+// recipient 为私钥外部表示 04 || X || Y || K 中的标量 K，source 记录系统版本。
+import Foundation
+import Security
+
+func hex(_ data: Data) -> String {
+    data.map { String(format: "%02x", $0) }.joined()
+}
+
+func check<T>(_ value: T?, _ error: Unmanaged<CFError>?) -> T {
+    guard let value else {
+        fatalError(String(describing: error?.takeRetainedValue()))
+    }
+    return value
+}
+
+let algorithm = SecKeyAlgorithm.eciesEncryptionStandardVariableIVX963SHA256AESGCM
+let plainText = Data("hello from SecKeyCreateEncryptedData".utf8)
+let system = ProcessInfo.processInfo.operatingSystemVersionString
+
+var entries: [[String: Any]] = []
+for (curve, bits) in [("P-256", 256), ("P-384", 384)] {
+    var error: Unmanaged<CFError>?
+    let attributes: [String: Any] = [
+        kSecAttrKeyType as String: kSecAttrKeyTypeECSECPrimeRandom,
+        kSecAttrKeySizeInBits as String: bits,
+    ]
+    let priv = check(SecKeyCreateRandomKey(attributes as CFDictionary, &error), error)
+    let pub = check(SecKeyCopyPublicKey(priv), nil)
+    let cipherText = check(SecKeyCreateEncryptedData(pub, algorithm, plainText as CFData, &error), error) as Data
+    let external = check(SecKeyCopyExternalRepresentation(priv, &error), error) as Data
+
+    entries.append([
+        "name": "Apple SecKeyCreateEncryptedData \(curve) VariableIVX963SHA256AESGCM",
+        "source": "Security.framework SecKeyCreateEncryptedData eciesEncryptionStandardVariableIVX963SHA256AESGCM，macOS \(system)",
+        "curve": curve,
+        "scheme": "AppleVariableIV",
+        "hash": "SHA-256",
+        "recipient": hex(external.suffix(bits / 8)),
+        "plainText": hex(plainText),
+        "cipherText": hex(cipherText),
+    ])
+}
+
+let json = try JSONSerialization.data(withJSONObject: entries, options: [.prettyPrinted, .sortedKeys])
+print(String(decoding: json, as: UTF8.self))
@@ -0,0 +1,132 @@
+package ed
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+
+	"filippo.io/edwards25519"
+)
+
+// BatchItem 是批量验签中的一条 (公钥, 消息, 签名)，签名为纯 Ed25519.
+type BatchItem struct {
+	PublicKey ed25519.PublicKey
+	Message   []byte
+	Signature []byte
+}
+
+// VerifyBatch 批量验证纯 Ed25519 签名，返回 (是否全部有效, 逐条结果).
+//
+// 先用随机系数的批量方程
+//
+//	[8]( Σ zᵢRᵢ + Σ zᵢkᵢAᵢ - (Σ zᵢSᵢ)B ) = 0
+//
+// 一次多标量乘法检查整批；通过则全部有效。未通过时回退为逐条验证，
+// valid[i] 指出具体哪些签名无效。公钥或签名长度非法的条目记为无效，不返回错误。
+//
+// 批量与逐条回退都采用 ZIP-215 的带余因子规则（[8][S]B = [8]R + [8][k]A，
+// S 须为规范编码，A、R 接受非规范编码），因此无论走哪条路径，同一签名的结论相同。
+// 这与 VerifyBytes（标准库，不带余因子）不完全一致：签名者刻意构造的、
+// R 或 A 含小阶分量的签名可能被 VerifyBatch 接受而被 VerifyBytes 拒绝。
+// 正常签名者生成的签名在两者下结论相同；需要与 VerifyBytes 逐位一致时请直接逐条验签。
+func VerifyBatch(items []BatchItem) (bool, []bool) {
+	valid := make([]bool, len(items))
+	if len(items) == 0 {
+		return true, valid
+	}
+	if verifyBatchEquation(items) {
+		for i := range valid {
+			valid[i] = true
+		}
+		return true, valid
+	}
+
+	all := true
+	for i, item := range items {
+		valid[i] = verifyCofactored(item)
+		all = all && valid[i]
+	}
+	return all, valid
+}
+
+// batchTerms 是一条签名解码后的 A、R、S 与 k = SHA-512(R || A || M).
+type batchTerms struct {
+	A, R *edwards25519.Point
+	S, k *edwards25519.Scalar
+}
+
+// decodeBatchItem 按 ZIP-215 解码一条签名，长度非法、点无法解码或 S 非规范时返回 false.
+func decodeBatchItem(item BatchItem) (batchTerms, bool) {
+	if len(item.PublicKey) != ed25519.PublicKeySize || len(item.Signature) != ed25519.SignatureSize {
+		return batchTerms{}, false
+	}
+	A, err := new(edwards25519.Point).SetBytes(item.PublicKey)
+	if err != nil {
+		return batchTerms{}, false
+	}
+	R, err := new(edwards25519.Point).SetBytes(item.Signature[:32])
+	if err != nil {
+		return batchTerms{}, false
+	}
+	S, err := edwards25519.NewScalar().SetCanonicalBytes(item.Signature[32:])
+	if err != nil {
+		return batchTerms{}, false
+	}
+
+	var digest [sha512.Size]byte
+	h := sha512.New()
+	h.Write(item.Signature[:32])
+	h.Write(item.PublicKey)
+	h.Write(item.Message)
+	k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(digest[:0]))
+	if err != nil {
+		return batchTerms{}, false
+	}
+	return batchTerms{A: A, R: R, S: S, k: k}, true
+}
+
+// verifyCofactored 逐条检查 [8]([S]B - [k]A - R) = 0.
+func verifyCofactored(item BatchItem) bool {
+	t, ok := decodeBatchItem(item)
+	if !ok {
+		return false
+	}
+	negK := edwards25519.NewScalar().Negate(t.k)
+	check := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(negK, t.A, t.S)
+	check.Subtract(check, t.R)
+	return check.MultByCofactor(check).Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// verifyBatchEquation 检查批量方程，任一条目无法解码时直接返回 false 交给逐条回退.
+func verifyBatchEquation(items []BatchItem) bool {
+	scalars := make([]*edwards25519.Scalar, 0, 2*len(items)+1)
+	points := make([]*edwards25519.Point, 0, 2*len(items)+1)
+	sumZS := edwards25519.NewScalar()
+
+	// z 取 128 位随机数：高 48 字节恒为 0，SetUniformBytes 不会约减。
+	var zBytes [64]byte
+	for _, item := range items {
+		t, ok := decodeBatchItem(item)
+		if !ok {
+			return false
+		}
+
+		if _, err := rand.Read(zBytes[:16]); err != nil {
+			return false
+		}
+		z, err := edwards25519.NewScalar().SetUniformBytes(zBytes[:])
+		if err != nil {
+			return false
+		}
+
+		sumZS.MultiplyAdd(z, t.S, sumZS)
+		scalars = append(scalars, z, edwards25519.NewScalar().Multiply(z, t.k))
+		points = append(points, t.R, t.A)
+	}
+
+	scalars = append(scalars, sumZS.Negate(sumZS))
+	points = append(points, edwards25519.NewGeneratorPoint())
+
+	check := new(edwards25519.Point).VarTimeMultiScalarMult(scalars, points)
+	return check.MultByCofactor(check).Equal(edwards25519.NewIdentityPoint()) == 1
+}
@@ -0,0 +1,219 @@
+package ed_test
+
+import (
+	"crypto/ed25519"
+	"crypto/sha512"
+	"fmt"
+	"testing"
+
+	"filippo.io/edwards25519"
+	"github.com/gtkit/encry/ed"
+	"github.com/stretchr/testify/require"
+)
+
+func batchItems(tb testing.TB, n int) []ed.BatchItem {
+	tb.Helper()
+	items := make([]ed.BatchItem, n)
+	for i := range items {
+		pub, priv, err := ed.GenerateKeyPair()
+		require.NoError(tb, err)
+		msg := fmt.Appendf(nil, "webhook payload #%d", i)
+		sig, err := ed.SignBytes(priv, msg)
+		require.NoError(tb, err)
+		items[i] = ed.BatchItem{PublicKey: pub, Message: msg, Signature: sig}
+	}
+	return items
+}
+
+func TestVerifyBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+		ok, valid := ed.VerifyBatch(nil)
+		require.True(t, ok)
+		require.Empty(t, valid)
+	})
+
+	t.Run("all valid", func(t *testing.T) {
+		t.Parallel()
+		for _, n := range []int{1, 2, 17, 64} {
+			ok, valid := ed.VerifyBatch(batchItems(t, n))
+			require.True(t, ok, n)
+			require.Len(t, valid, n)
+			require.NotContains(t, valid, false)
+		}
+	})
+
+	t.Run("same key many messages", func(t *testing.T) {
+		t.Parallel()
+		pub, priv, err := ed.GenerateKeyPair()
+		require.NoError(t, err)
+		items := make([]ed.BatchItem, 32)
+		for i := range items {
+			msg := fmt.Appendf(nil, "m%d", i)
+			sig, err := ed.SignBytes(priv, msg)
+			require.NoError(t, err)
+			items[i] = ed.BatchItem{PublicKey: pub, Message: msg, Signature: sig}
+		}
+		ok, _ := ed.VerifyBatch(items)
+		require.True(t, ok)
+	})
+
+	corruptions := []struct {
+		name    string
+		corrupt func(item *ed.BatchItem)
+	}{
+		{"tampered message", func(item *ed.BatchItem) { item.Message = append([]byte("x"), item.Message...) }},
+		{"flipped R", func(item *ed.BatchItem) { item.Signature[0] ^= 1 }},
+		{"flipped S", func(item *ed.BatchItem) { item.Signature[40] ^= 1 }},
+		{"non-canonical S", func(item *ed.BatchItem) { item.Signature[63] |= 0xf0 }},
+		{"short signature", func(item *ed.BatchItem) { item.Signature = item.Signature[:10] }},
+		{"short public key", func(item *ed.BatchItem) { item.PublicKey = item.PublicKey[:10] }},
+		{"wrong public key", func(item *ed.BatchItem) {
+			pub, _, _ := ed.GenerateKeyPair()
+			item.PublicKey = pub
+		}},
+	}
+	for _, tt := range corruptions {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			items := batchItems(t, 8)
+			bad := map[int]bool{2: true, 5: true}
+			for i := range bad {
+				items[i].Signature = append([]byte(nil), items[i].Signature...)
+				tt.corrupt(&items[i])
+			}
+
+			ok, valid := ed.VerifyBatch(items)
+			require.False(t, ok)
+			for i, v := range valid {
+				require.Equal(t, !bad[i], v, "item %d", i)
+			}
+		})
+	}
+
+	t.Run("swapped signatures", func(t *testing.T) {
+		t.Parallel()
+		// 两条签名互换后各自都无效，但若系数固定为 1 可能互相抵消；随机系数使其被拒。
+		items := batchItems(t, 4)
+		items[0].Signature, items[1].Signature = items[1].Signature, items[0].Signature
+		ok, valid := ed.VerifyBatch(items)
+		require.False(t, ok)
+		require.Equal(t, []bool{false, false, true, true}, valid)
+	})
+}
+
+func TestVerifyBatchMatchesStdlib(t *testing.T) {
+	t.Parallel()
+
+	items := batchItems(t, 16)
+	for _, item := range items {
+		require.True(t, ed25519.Verify(item.PublicKey, item.Message, item.Signature))
+	}
+	ok, _ := ed.VerifyBatch(items)
+	require.True(t, ok)
+}
+
+// torsionItem 用私钥直接构造一条 R 或 A 含 8 阶分量 T 的签名：
+// [S]B = R + [k]A 与 R' = R + T（或 A' = A + T）只差一个小阶点，带余因子方程成立，不带余因子方程不成立。
+func torsionItem(t *testing.T, torsionInR bool) ed.BatchItem {
+	t.Helper()
+	T, err := new(edwards25519.Point).SetBytes(mustHex(t, "c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a"))
+	require.NoError(t, err)
+	require.Equal(t, 0, T.Equal(edwards25519.NewIdentityPoint()))
+	require.Equal(t, 1, new(edwards25519.Point).MultByCofactor(T).Equal(edwards25519.NewIdentityPoint()))
+
+	_, priv, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+	h := sha512.Sum512(priv.Seed())
+	a, err := edwards25519.NewScalar().SetBytesWithClamping(h[:32])
+	require.NoError(t, err)
+	nonce := sha512.Sum512(append(h[32:], "nonce"...))
+	r, err := edwards25519.NewScalar().SetUniformBytes(nonce[:])
+	require.NoError(t, err)
+
+	A := new(edwards25519.Point).ScalarBaseMult(a)
+	R := new(edwards25519.Point).ScalarBaseMult(r)
+	if torsionInR {
+		R.Add(R, T)
+	} else {
+		A.Add(A, T)
+	}
+	msg := []byte("small-order component")
+	digest := sha512.New()
+	digest.Write(R.Bytes())
+	digest.Write(A.Bytes())
+	digest.Write(msg)
+	k, err := edwards25519.NewScalar().SetUniformBytes(digest.Sum(nil))
+	require.NoError(t, err)
+	S := edwards25519.NewScalar().MultiplyAdd(k, a, r)
+
+	return ed.BatchItem{
+		PublicKey: A.Bytes(),
+		Message:   msg,
+		Signature: append(R.Bytes(), S.Bytes()...),
+	}
+}
+
+func TestVerifyBatchSmallOrderComponent(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		name       string
+		torsionInR bool
+	}{
+		{"torsion in R", true},
+		{"torsion in A", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			crafted := torsionItem(t, tt.torsionInR)
+
+			// 批量方程路径：整批通过。
+			ok, valid := ed.VerifyBatch([]ed.BatchItem{crafted})
+			require.True(t, ok)
+			require.Equal(t, []bool{true}, valid)
+
+			// 逐条回退路径：另一条签名无效迫使回退，构造的签名结论不变。
+			items := batchItems(t, 4)
+			items[1] = crafted
+			items[3].Message = []byte("tampered")
+			ok, valid = ed.VerifyBatch(items)
+			require.False(t, ok)
+			require.Equal(t, []bool{true, true, true, false}, valid)
+		})
+	}
+
+	// 标准库不带余因子，VerifyBytes 拒绝 R 含小阶分量的签名（见 VerifyBatch 文档）。
+	crafted := torsionItem(t, true)
+	ok, err := ed.VerifyBytes(crafted.PublicKey, crafted.Message, crafted.Signature)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	for _, n := range []int{16, 64, 256} {
+		items := batchItems(b, n)
+
+		b.Run(fmt.Sprintf("VerifyBytes/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				for _, item := range items {
+					if ok, _ := ed.VerifyBytes(item.PublicKey, item.Message, item.Signature); !ok {
+						b.Fatal("verify failed")
+					}
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/sig")
+		})
+
+		b.Run(fmt.Sprintf("VerifyBatch/%d", n), func(b *testing.B) {
+			for b.Loop() {
+				if ok, _ := ed.VerifyBatch(items); !ok {
+					b.Fatal("batch failed")
+				}
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/sig")
+		})
+	}
+}
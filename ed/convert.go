@@ -0,0 +1,64 @@
+package ed
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+
+	"filippo.io/edwards25519"
+)
+
+// 本文件实现 Ed25519 → X25519 的密钥转换（与 libsodium 的
+// crypto_sign_ed25519_pk_to_curve25519 / crypto_sign_ed25519_sk_to_curve25519 结果一致），
+// 使同一个身份密钥既能用 ed 签名，又能作为 ecdh/hpke 的接收方，类似 age 的 ssh-ed25519 收件人。
+//
+// 同一密钥跨签名与密钥协商复用在上述两种算法组合下是已分析过的安全用法，但仍应只在
+// 确有“单一身份密钥”需求时使用；新设计优先为两种用途分别生成密钥。
+
+// ErrSmallOrderPublicKey 表示公钥不在素数阶子群中（小阶点或含小阶分量），转换后会泄露或固定共享密钥.
+var ErrSmallOrderPublicKey = errors.New("invalid Ed25519 public key: not in the prime-order subgroup")
+
+// ToX25519PrivateKey 将 Ed25519 私钥转换为 X25519 私钥：取 SHA-512(seed) 前 32 字节并 clamp，
+// 与 Ed25519 签名使用的秘密标量相同.
+func ToX25519PrivateKey(privateKey ed25519.PrivateKey) (*ecdh.PrivateKey, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidPrivateKey
+	}
+	h := sha512.Sum512(privateKey.Seed())
+	defer clear(h[:])
+	scalar := h[:32]
+	scalar[0] &= 248
+	scalar[31] &= 127
+	scalar[31] |= 64
+	return ecdh.X25519().NewPrivateKey(scalar)
+}
+
+// ToX25519PublicKey 通过双有理映射 u = (1+y)/(1-y) 将 Ed25519 公钥转换为 X25519 公钥.
+// 与 libsodium 一致，拒绝小阶点以及不在素数阶子群中的点（返回 ErrSmallOrderPublicKey）.
+func ToX25519PublicKey(publicKey ed25519.PublicKey) (*ecdh.PublicKey, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	point, err := new(edwards25519.Point).SetBytes(publicKey)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	if !inPrimeOrderSubgroup(point) {
+		return nil, ErrSmallOrderPublicKey
+	}
+	return ecdh.X25519().NewPublicKey(point.BytesMontgomery())
+}
+
+// inPrimeOrderSubgroup 报告 p 是否为素数阶子群中的非单位元点：[8]p ≠ O 排除小阶点，
+// [ℓ]p = O 排除含小阶分量的混合阶点（ℓ 无法表示为约减后的标量，按 [ℓ-1]p + p 计算）.
+func inPrimeOrderSubgroup(p *edwards25519.Point) bool {
+	identity := edwards25519.NewIdentityPoint()
+	if new(edwards25519.Point).MultByCofactor(p).Equal(identity) == 1 {
+		return false
+	}
+	one, _ := edwards25519.NewScalar().SetCanonicalBytes(append([]byte{1}, make([]byte, 31)...))
+	lMinusOne := edwards25519.NewScalar().Negate(one)
+	q := new(edwards25519.Point).ScalarMult(lMinusOne, p)
+	return q.Add(q, p).Equal(identity) == 1
+}
@@ -0,0 +1,113 @@
+package ed_test
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gtkit/encry/ecdh"
+	"github.com/gtkit/encry/ed"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/libsodium_convert.json 由 libsodium 1.0.18 的 crypto_sign_seed_keypair、
+// crypto_sign_ed25519_pk_to_curve25519 与 crypto_sign_ed25519_sk_to_curve25519 生成；
+// rejected 中的公钥 libsodium 均返回 -1。
+type convertVectors struct {
+	Valid []struct {
+		Seed          string `json:"seed"`
+		Ed25519Public string `json:"ed25519_public"`
+		X25519Private string `json:"x25519_private"`
+		X25519Public  string `json:"x25519_public"`
+	} `json:"valid"`
+	Rejected []struct {
+		Name          string `json:"name"`
+		Ed25519Public string `json:"ed25519_public"`
+	} `json:"rejected"`
+}
+
+func loadConvertVectors(t *testing.T) convertVectors {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "libsodium_convert.json"))
+	require.NoError(t, err)
+	var v convertVectors
+	require.NoError(t, json.Unmarshal(data, &v))
+	return v
+}
+
+func TestToX25519LibsodiumVectors(t *testing.T) {
+	t.Parallel()
+
+	vectors := loadConvertVectors(t)
+	require.NotEmpty(t, vectors.Valid)
+	for _, v := range vectors.Valid {
+		pub, priv, err := ed.NewKeyFromSeed(mustHex(t, v.Seed))
+		require.NoError(t, err)
+		require.Equal(t, v.Ed25519Public, hex.EncodeToString(pub))
+
+		xPriv, err := ed.ToX25519PrivateKey(priv)
+		require.NoError(t, err)
+		require.Equal(t, v.X25519Private, hex.EncodeToString(xPriv.Bytes()))
+
+		xPub, err := ed.ToX25519PublicKey(pub)
+		require.NoError(t, err)
+		require.Equal(t, v.X25519Public, hex.EncodeToString(xPub.Bytes()))
+
+		// 私钥转换与公钥转换一致。
+		require.True(t, xPriv.PublicKey().Equal(xPub))
+	}
+}
+
+func TestToX25519PublicKeyRejectsSmallOrder(t *testing.T) {
+	t.Parallel()
+
+	vectors := loadConvertVectors(t)
+	require.NotEmpty(t, vectors.Rejected)
+	for _, v := range vectors.Rejected {
+		_, err := ed.ToX25519PublicKey(mustHex(t, v.Ed25519Public))
+		require.ErrorIs(t, err, ed.ErrSmallOrderPublicKey, v.Name)
+	}
+}
+
+func TestToX25519KeyAgreement(t *testing.T) {
+	t.Parallel()
+
+	// 双方只持有 Ed25519 身份密钥，转换后即可做 X25519 协商。
+	alicePub, alicePriv, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+	bobPub, bobPriv, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+
+	aliceX, err := ed.ToX25519PrivateKey(alicePriv)
+	require.NoError(t, err)
+	bobX, err := ed.ToX25519PrivateKey(bobPriv)
+	require.NoError(t, err)
+	alicePubX, err := ed.ToX25519PublicKey(alicePub)
+	require.NoError(t, err)
+	bobPubX, err := ed.ToX25519PublicKey(bobPub)
+	require.NoError(t, err)
+
+	s1, err := ecdh.SharedSecret(aliceX, bobPubX)
+	require.NoError(t, err)
+	s2, err := ecdh.SharedSecret(bobX, alicePubX)
+	require.NoError(t, err)
+	require.Equal(t, s1, s2)
+}
+
+func TestToX25519Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ed.ToX25519PrivateKey(nil)
+	require.ErrorIs(t, err, ed.ErrInvalidPrivateKey)
+	_, err = ed.ToX25519PublicKey(ed25519.PublicKey{1, 2, 3})
+	require.ErrorIs(t, err, ed.ErrInvalidPublicKey)
+
+	// y = 2 不在曲线上。
+	notOnCurve := make([]byte, 32)
+	notOnCurve[0] = 2
+	_, err = ed.ToX25519PublicKey(notOnCurve)
+	require.ErrorIs(t, err, ed.ErrInvalidPublicKey)
+}
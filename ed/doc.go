@@ -1,2 +1,8 @@
-// Package ed 提供 Ed25519 签名/验签，以及密钥的 PEM 序列化与文件读写。
+// Package ed 提供 Ed25519 签名/验签，以及密钥的 PEM 序列化与文件读写；
+// 也支持 OpenSSH 私钥格式（OPENSSH PRIVATE KEY）与 authorized_keys 公钥行。
+//
+// 除纯 Ed25519 外，还支持 RFC 8032 的 Ed25519ctx（SignCtx/VerifyCtx，上下文域分离）
+// 与 Ed25519ph（SignPh/VerifyPh，SHA-512 预哈希）；SignReader/VerifyReader 基于
+// Ed25519ph 流式处理任意大小的数据。VerifyBatch 用于高吞吐场景的批量验签。
+// ToX25519PrivateKey/ToX25519PublicKey 把同一身份密钥转换为 X25519 密钥，供 ecdh/hpke 使用。
 package ed
@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/gtkit/encry/ed"
 )
@@ -56,3 +57,25 @@ func ExampleWriteKeyPair() {
 	// Output:
 	// true
 }
+
+func ExampleSignReader() {
+	out := log.New(os.Stdout, "", 0)
+	publicKey, privateKey, err := ed.GenerateKeyPair()
+	if err != nil {
+		panic(err)
+	}
+
+	// 实际使用时传入 *os.File，大文件不会整体读入内存。
+	signature, err := ed.SignReader(privateKey, strings.NewReader("large file content"), "backup/v1")
+	if err != nil {
+		panic(err)
+	}
+
+	ok, err := ed.VerifyReader(publicKey, strings.NewReader("large file content"), signature, "backup/v1")
+	if err != nil {
+		panic(err)
+	}
+	out.Println(ok)
+	// Output:
+	// true
+}
@@ -0,0 +1,121 @@
+package ed
+
+import (
+	"crypto/ed25519"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/gtkit/encry/internal/sshkey"
+	"golang.org/x/crypto/ssh"
+)
+
+// pemTypeOpenSSH 为 ssh-keygen 默认私钥格式的 PEM 类型.
+const pemTypeOpenSSH = "OPENSSH PRIVATE KEY"
+
+// ParseOpenSSHPrivateKey 解析未加密的 OpenSSH 格式私钥（ssh-keygen -t ed25519 生成的 id_ed25519）.
+// 私钥受口令保护时返回 *ssh.PassphraseMissingError.
+func ParseOpenSSHPrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	if err := checkOpenSSHBlock(data); err != nil {
+		return nil, err
+	}
+	key, err := ssh.ParseRawPrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519FromRaw(key)
+}
+
+// ParseOpenSSHPrivateKeyWithPassphrase 使用口令解析加密的 OpenSSH 格式私钥.
+func ParseOpenSSHPrivateKeyWithPassphrase(data, passphrase []byte) (ed25519.PrivateKey, error) {
+	if err := checkOpenSSHBlock(data); err != nil {
+		return nil, err
+	}
+	key, err := ssh.ParseRawPrivateKeyWithPassphrase(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519FromRaw(key)
+}
+
+// MarshalOpenSSHPrivateKey 将私钥编码为未加密的 OpenSSH 格式，comment 写入密钥注释.
+func MarshalOpenSSHPrivateKey(privateKey ed25519.PrivateKey, comment string) ([]byte, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidPrivateKey
+	}
+	block, err := ssh.MarshalPrivateKey(privateKey, comment)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// MarshalOpenSSHPrivateKeyWithPassphrase 将私钥编码为口令加密（bcrypt KDF + AES-256-CTR）的 OpenSSH 格式.
+func MarshalOpenSSHPrivateKeyWithPassphrase(privateKey ed25519.PrivateKey, comment string, passphrase []byte) ([]byte, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidPrivateKey
+	}
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(privateKey, comment, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(block), nil
+}
+
+// ParseAuthorizedKey 解析 authorized_keys / id_ed25519.pub 中的一行，返回公钥与注释.
+// 行首的 authorized_keys 选项（如 from="..."）会被忽略.
+func ParseAuthorizedKey(line []byte) (ed25519.PublicKey, string, error) {
+	sshKey, comment, _, _, err := ssh.ParseAuthorizedKey(line)
+	if err != nil {
+		return nil, "", err
+	}
+	cryptoKey, ok := sshKey.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, "", ErrInvalidPublicKey
+	}
+	publicKey, ok := cryptoKey.CryptoPublicKey().(ed25519.PublicKey)
+	if !ok {
+		return nil, "", ErrInvalidPublicKey
+	}
+	return publicKey, comment, nil
+}
+
+// ErrInvalidComment 表示 authorized_keys 注释含 CR/LF，原样写入会注入额外的行（如追加受信任公钥）.
+// 与 ecdsa.ErrInvalidComment 是同一个哨兵错误.
+var ErrInvalidComment = sshkey.ErrInvalidComment
+
+// MarshalAuthorizedKey 将公钥编码为 authorized_keys 行（"ssh-ed25519 AAAA... comment\n"）.
+// 注释含 CR/LF 时返回 ErrInvalidComment.
+func MarshalAuthorizedKey(publicKey ed25519.PublicKey, comment string) ([]byte, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	sshKey, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	line, err := sshkey.MarshalAuthorizedKey(sshKey, comment)
+	if err != nil {
+		return nil, fmt.Errorf("ed: %w", err)
+	}
+	return line, nil
+}
+
+func checkOpenSSHBlock(data []byte) error {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemTypeOpenSSH {
+		return ErrInvalidPrivateKey
+	}
+	return nil
+}
+
+// ed25519FromRaw 兼容 x/crypto/ssh 返回的 ed25519.PrivateKey 与 *ed25519.PrivateKey 两种形式.
+func ed25519FromRaw(key any) (ed25519.PrivateKey, error) {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return k, nil
+	case *ed25519.PrivateKey:
+		return *k, nil
+	default:
+		return nil, ErrInvalidPrivateKey
+	}
+}
@@ -0,0 +1,20 @@
+package ed
+
+import (
+	"crypto/ed25519"
+	"errors"
+)
+
+// ErrInvalidSeed 表示种子长度不是 ed25519.SeedSize（32 字节）.
+var ErrInvalidSeed = errors.New("invalid Ed25519 seed")
+
+// NewKeyFromSeed 由 32 字节种子确定性地生成 Ed25519 密钥对（RFC 8032 中的私钥种子），
+// 同一种子总是得到同一密钥对；用于备份恢复与可复现的测试夹具.
+// 种子等同于私钥，必须按私钥保管.
+func NewKeyFromSeed(seed []byte) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, nil, ErrInvalidSeed
+	}
+	privateKey := ed25519.NewKeyFromSeed(seed)
+	return privateKey.Public().(ed25519.PublicKey), privateKey, nil
+}
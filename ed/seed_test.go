@@ -0,0 +1,22 @@
+package ed_test
+
+import (
+	"testing"
+
+	"github.com/gtkit/encry/ed"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyFromSeed(t *testing.T) {
+	t.Parallel()
+
+	// RFC 8032 §7.1 TEST 1。
+	seed := mustHex(t, "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	pub, priv, err := ed.NewKeyFromSeed(seed)
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"), []byte(pub))
+	require.Equal(t, seed, priv.Seed())
+
+	_, _, err = ed.NewKeyFromSeed(seed[:31])
+	require.ErrorIs(t, err, ed.ErrInvalidSeed)
+}
@@ -0,0 +1,128 @@
+package ed
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"io"
+)
+
+// 本文件提供 RFC 8032 的 Ed25519ctx 与 Ed25519ph 变体。三种变体的签名互不通用：
+// 纯 Ed25519 签名无法用 VerifyCtx/VerifyPh 验证，反之亦然。
+//
+//   - Ed25519ctx：带上下文字符串做域分离，同一密钥用于多个协议时防止签名被跨协议复用。
+//   - Ed25519ph：先对消息做 SHA-512 再签名，消息可流式读取，适合大文件（SignReader/VerifyReader）。
+
+// maxContextLen 为 RFC 8032 规定的上下文字符串最大长度.
+const maxContextLen = 255
+
+var (
+	// ErrInvalidContext 表示上下文字符串非法：Ed25519ctx 要求 1~255 字节，Ed25519ph 要求不超过 255 字节.
+	ErrInvalidContext = errors.New("invalid Ed25519 context")
+	// ErrInvalidDigest 表示 Ed25519ph 的摘要长度不是 SHA-512 的 64 字节.
+	ErrInvalidDigest = errors.New("invalid Ed25519ph SHA-512 digest")
+	// ErrNilReader 表示 SignReader/VerifyReader 收到 nil io.Reader.
+	ErrNilReader = errors.New("nil reader")
+)
+
+// SignCtx 使用 Ed25519ctx 签名，context 不可为空.
+func SignCtx(privateKey ed25519.PrivateKey, msg []byte, context string) ([]byte, error) {
+	if context == "" {
+		return nil, ErrInvalidContext
+	}
+	return signWithOptions(privateKey, msg, &ed25519.Options{Context: context})
+}
+
+// VerifyCtx 验证 Ed25519ctx 签名，返回 (是否有效, 操作性错误).
+func VerifyCtx(publicKey ed25519.PublicKey, msg, signature []byte, context string) (bool, error) {
+	if context == "" {
+		return false, ErrInvalidContext
+	}
+	return verifyWithOptions(publicKey, msg, signature, &ed25519.Options{Context: context})
+}
+
+// SignPh 对 msg 做 SHA-512 后使用 Ed25519ph 签名；context 可为空.
+func SignPh(privateKey ed25519.PrivateKey, msg []byte, context string) ([]byte, error) {
+	digest := sha512.Sum512(msg)
+	return SignPhDigest(privateKey, digest[:], context)
+}
+
+// VerifyPh 验证 SignPh 生成的签名，返回 (是否有效, 操作性错误).
+func VerifyPh(publicKey ed25519.PublicKey, msg, signature []byte, context string) (bool, error) {
+	digest := sha512.Sum512(msg)
+	return VerifyPhDigest(publicKey, digest[:], signature, context)
+}
+
+// SignPhDigest 对调用方已计算好的 SHA-512 摘要做 Ed25519ph 签名.
+func SignPhDigest(privateKey ed25519.PrivateKey, digest []byte, context string) ([]byte, error) {
+	if len(digest) != sha512.Size {
+		return nil, ErrInvalidDigest
+	}
+	return signWithOptions(privateKey, digest, &ed25519.Options{Hash: crypto.SHA512, Context: context})
+}
+
+// VerifyPhDigest 使用 SHA-512 摘要验证 Ed25519ph 签名，返回 (是否有效, 操作性错误).
+func VerifyPhDigest(publicKey ed25519.PublicKey, digest, signature []byte, context string) (bool, error) {
+	if len(digest) != sha512.Size {
+		return false, ErrInvalidDigest
+	}
+	return verifyWithOptions(publicKey, digest, signature, &ed25519.Options{Hash: crypto.SHA512, Context: context})
+}
+
+// SignReader 流式读取 r 并做 Ed25519ph 签名，内存占用与数据大小无关.
+func SignReader(privateKey ed25519.PrivateKey, r io.Reader, context string) ([]byte, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidPrivateKey
+	}
+	digest, err := sha512Reader(r)
+	if err != nil {
+		return nil, err
+	}
+	return SignPhDigest(privateKey, digest, context)
+}
+
+// VerifyReader 流式读取 r 并验证 Ed25519ph 签名，返回 (是否有效, 操作性错误)；读取失败时返回错误.
+func VerifyReader(publicKey ed25519.PublicKey, r io.Reader, signature []byte, context string) (bool, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return false, ErrInvalidPublicKey
+	}
+	digest, err := sha512Reader(r)
+	if err != nil {
+		return false, err
+	}
+	return VerifyPhDigest(publicKey, digest, signature, context)
+}
+
+func sha512Reader(r io.Reader) ([]byte, error) {
+	if r == nil {
+		return nil, ErrNilReader
+	}
+	h := sha512.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func signWithOptions(privateKey ed25519.PrivateKey, msg []byte, opts *ed25519.Options) ([]byte, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidPrivateKey
+	}
+	if len(opts.Context) > maxContextLen {
+		return nil, ErrInvalidContext
+	}
+	return privateKey.Sign(rand.Reader, msg, opts)
+}
+
+// verifyWithOptions 先校验公钥与上下文，使 VerifyWithOptions 的错误只可能来自签名无效.
+func verifyWithOptions(publicKey ed25519.PublicKey, msg, signature []byte, opts *ed25519.Options) (bool, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return false, ErrInvalidPublicKey
+	}
+	if len(opts.Context) > maxContextLen {
+		return false, ErrInvalidContext
+	}
+	return ed25519.VerifyWithOptions(publicKey, msg, signature, opts) == nil, nil
+}
@@ -0,0 +1,209 @@
+package ed_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/ed"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// RFC 8032 §7.2（Ed25519ctx）与 §7.3（Ed25519ph）测试向量。
+func TestRFC8032Vectors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ph      bool
+		seed    string
+		pub     string
+		msg     string
+		context string
+		sig     string
+	}{
+		{
+			name:    "ctx foo",
+			seed:    "0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+			pub:     "dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292",
+			msg:     "f726936d19c800494e3fdaff20b276a8",
+			context: "foo",
+			sig:     "55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d",
+		},
+		{
+			name:    "ctx bar",
+			seed:    "0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+			pub:     "dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292",
+			msg:     "f726936d19c800494e3fdaff20b276a8",
+			context: "bar",
+			sig:     "fc60d5872fc46b3aa69f8b5b4351d5808f92bcc044606db097abab6dbcb1aee3216c48e8b3b66431b5b186d1d28f8ee15a5ca2df6668346291c2043d4eb3e90d",
+		},
+		{
+			name: "ph abc",
+			ph:   true,
+			seed: "833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+			pub:  "ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf",
+			msg:  "616263",
+			sig:  "98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			priv := ed25519.NewKeyFromSeed(mustHex(t, tt.seed))
+			pub := ed25519.PublicKey(mustHex(t, tt.pub))
+			require.Equal(t, pub, priv.Public())
+			msg := mustHex(t, tt.msg)
+			want := mustHex(t, tt.sig)
+
+			var (
+				sig []byte
+				ok  bool
+				err error
+			)
+			if tt.ph {
+				sig, err = ed.SignPh(priv, msg, tt.context)
+				require.NoError(t, err)
+				ok, err = ed.VerifyPh(pub, msg, sig, tt.context)
+				require.NoError(t, err)
+				require.True(t, ok)
+
+				streamSig, err := ed.SignReader(priv, bytes.NewReader(msg), tt.context)
+				require.NoError(t, err)
+				require.Equal(t, want, streamSig)
+				ok, err = ed.VerifyReader(pub, bytes.NewReader(msg), want, tt.context)
+				require.NoError(t, err)
+				require.True(t, ok)
+			} else {
+				sig, err = ed.SignCtx(priv, msg, tt.context)
+				require.NoError(t, err)
+				ok, err = ed.VerifyCtx(pub, msg, sig, tt.context)
+				require.NoError(t, err)
+				require.True(t, ok)
+			}
+			require.Equal(t, want, sig)
+		})
+	}
+}
+
+func TestVariantsDoNotCrossVerify(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+	msg := []byte("domain separated")
+
+	pure, err := ed.SignBytes(priv, msg)
+	require.NoError(t, err)
+	ctxSig, err := ed.SignCtx(priv, msg, "proto-a")
+	require.NoError(t, err)
+	phSig, err := ed.SignPh(priv, msg, "")
+	require.NoError(t, err)
+
+	checks := []struct {
+		name   string
+		verify func() (bool, error)
+	}{
+		{"pure as ctx", func() (bool, error) { return ed.VerifyCtx(pub, msg, pure, "proto-a") }},
+		{"pure as ph", func() (bool, error) { return ed.VerifyPh(pub, msg, pure, "") }},
+		{"ctx as pure", func() (bool, error) { return ed.VerifyBytes(pub, msg, ctxSig) }},
+		{"ctx other context", func() (bool, error) { return ed.VerifyCtx(pub, msg, ctxSig, "proto-b") }},
+		{"ph as pure", func() (bool, error) { return ed.VerifyBytes(pub, msg, phSig) }},
+		{"ph other context", func() (bool, error) { return ed.VerifyPh(pub, msg, phSig, "x") }},
+		{"ph tampered", func() (bool, error) { return ed.VerifyPh(pub, []byte("other"), phSig, "") }},
+	}
+	for _, c := range checks {
+		ok, err := c.verify()
+		require.NoError(t, err, c.name)
+		require.False(t, ok, c.name)
+	}
+}
+
+func TestSignReaderLargeStream(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+
+	// 8 MiB 的流只经过 SHA-512，不整体读入内存。
+	const size = 8 << 20
+	src := io.LimitReader(zeroReader{}, size)
+	sig, err := ed.SignReader(priv, src, "backup/v1")
+	require.NoError(t, err)
+
+	ok, err := ed.VerifyReader(pub, io.LimitReader(zeroReader{}, size), sig, "backup/v1")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = ed.VerifyReader(pub, io.LimitReader(zeroReader{}, size-1), sig, "backup/v1")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// 与一次性 SignPh 结果一致。
+	ok, err = ed.VerifyPh(pub, make([]byte, size), sig, "backup/v1")
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestVariantErrors(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+	long := strings.Repeat("c", 256)
+	readErr := errors.New("disk failure")
+
+	_, err = ed.SignCtx(priv, nil, "")
+	require.ErrorIs(t, err, ed.ErrInvalidContext)
+	_, err = ed.VerifyCtx(pub, nil, nil, "")
+	require.ErrorIs(t, err, ed.ErrInvalidContext)
+	_, err = ed.SignCtx(priv, nil, long)
+	require.ErrorIs(t, err, ed.ErrInvalidContext)
+	_, err = ed.VerifyPh(pub, nil, nil, long)
+	require.ErrorIs(t, err, ed.ErrInvalidContext)
+
+	_, err = ed.SignCtx(priv[:10], nil, "c")
+	require.ErrorIs(t, err, ed.ErrInvalidPrivateKey)
+	_, err = ed.VerifyCtx(pub[:10], nil, nil, "c")
+	require.ErrorIs(t, err, ed.ErrInvalidPublicKey)
+	_, err = ed.SignReader(nil, strings.NewReader("x"), "")
+	require.ErrorIs(t, err, ed.ErrInvalidPrivateKey)
+	_, err = ed.VerifyReader(nil, strings.NewReader("x"), nil, "")
+	require.ErrorIs(t, err, ed.ErrInvalidPublicKey)
+
+	_, err = ed.SignPhDigest(priv, []byte("short"), "")
+	require.ErrorIs(t, err, ed.ErrInvalidDigest)
+	_, err = ed.VerifyPhDigest(pub, []byte("short"), nil, "")
+	require.ErrorIs(t, err, ed.ErrInvalidDigest)
+
+	_, err = ed.SignReader(priv, nil, "")
+	require.ErrorIs(t, err, ed.ErrNilReader)
+	_, err = ed.SignReader(priv, io.MultiReader(strings.NewReader("partial"), errReader{readErr}), "")
+	require.ErrorIs(t, err, readErr)
+	ok, err := ed.VerifyReader(pub, errReader{readErr}, make([]byte, ed25519.SignatureSize), "")
+	require.ErrorIs(t, err, readErr)
+	require.False(t, ok)
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
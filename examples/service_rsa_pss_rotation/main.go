@@ -113,7 +113,7 @@ func ensureDemoKeys(keyDir string, kids ...string) error {
 }
 
 func reloadRSAKeys(ring *keyring.Ring[keyring.Record[keyring.RSAKeyPair]], keyDir, activeKID string) error {
-	keys, err := keyring.LoadRSAKeyPairRecords(keyDir)
+	keys, err := keyring.LoadRSAKeyPairRecords(keyDir, keyring.WithRSAKeyPolicy(encryrsa.DefaultKeyPolicy()))
 	if err != nil {
 		return err
 	}
@@ -1,12 +1,12 @@
 module github.com/gtkit/encry
 
-go 1.26
-
-require github.com/stretchr/testify v1.11.1
+go 1.27
 
 require (
+	filippo.io/edwards25519 v1.2.0
 	github.com/gtkit/json/v2 v2.0.7
 	github.com/sqids/sqids-go v0.4.1
+	github.com/stretchr/testify v1.11.1
 	golang.org/x/crypto v0.53.0
 )
 
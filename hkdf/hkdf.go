@@ -1,26 +1,37 @@
 // Package hkdf 提供 RFC5869 的 HKDF（HMAC-based Extract-and-Expand）密钥派生。
 //
 // 用于把一段（可能不均匀的）密钥材料——例如 ECDH 协商出的共享密钥——扩展为
-// 一个或多个强随机的对称密钥。默认使用 SHA-256。
+// 一个或多个强随机的对称密钥。默认使用 SHA-256，可用 WithHash 选择 SHA-384/512 或 SHA3。
+//
+// Derive 一次完成 Extract 与 Expand；需要从同一密钥材料派生多把子密钥时，
+// 先 Extract 一次得到伪随机密钥 PRK，再按不同 info 多次 Expand，或使用 KeySchedule
+// 按 TLS 1.3 HKDF-Expand-Label 的方式派生带标签的子密钥（enc、mac、iv 等）。
 package hkdf
 
 import (
 	"crypto/hkdf"
-	"crypto/sha256"
 	"crypto/sha512"
 	"errors"
 )
 
-// ErrInvalidKeyLength 表示请求的派生长度非法（<=0）。
-var ErrInvalidKeyLength = errors.New("hkdf: key length must be positive")
+var (
+	// ErrInvalidKeyLength 表示请求的派生长度非法（<=0 或超过 255 倍摘要长度）。
+	ErrInvalidKeyLength = errors.New("hkdf: invalid key length")
+	// ErrInvalidPRK 表示 Expand 的伪随机密钥短于摘要长度（RFC5869 要求至少 HashLen 字节）。
+	ErrInvalidPRK = errors.New("hkdf: pseudorandom key shorter than hash size")
+)
 
-// Derive 使用 HKDF-SHA256 从 secret 派生 keyLen 字节密钥。
+// Derive 使用 HKDF（默认 SHA-256）从 secret 派生 keyLen 字节密钥，等价于 Extract 后 Expand。
 // salt 可为 nil；info 用于域分隔，不同 info 派生出互不相关的密钥。
-func Derive(secret, salt []byte, info string, keyLen int) ([]byte, error) {
-	if keyLen <= 0 {
-		return nil, ErrInvalidKeyLength
+func Derive(secret, salt []byte, info string, keyLen int, opts ...Option) ([]byte, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
 	}
-	return hkdf.Key(sha256.New, secret, salt, info, keyLen)
+	if err := o.checkLength(keyLen); err != nil {
+		return nil, err
+	}
+	return hkdf.Key(o.newHash, secret, salt, info, keyLen)
 }
 
 // DeriveSHA512 使用 HKDF-SHA512 派生，适合需要更长输出或更高安全裕度的场景。
@@ -30,3 +41,33 @@ func DeriveSHA512(secret, salt []byte, info string, keyLen int) ([]byte, error)
 	}
 	return hkdf.Key(sha512.New, secret, salt, info, keyLen)
 }
+
+// Extract 执行 HKDF-Extract，返回摘要长度的伪随机密钥 PRK；salt 为 nil 时按 RFC5869 使用全零。
+// PRK 与 secret 一样须保密。
+func Extract(secret, salt []byte, opts ...Option) ([]byte, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return hkdf.Extract(o.newHash, secret, salt)
+}
+
+// Expand 执行 HKDF-Expand，由 PRK 派生 keyLen 字节密钥；keyLen 不超过 255 倍摘要长度。
+// 摘要算法须与 Extract 时一致。
+func Expand(prk []byte, info string, keyLen int, opts ...Option) ([]byte, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return o.expand(prk, info, keyLen)
+}
+
+func (o *options) expand(prk []byte, info string, keyLen int) ([]byte, error) {
+	if len(prk) < o.size() {
+		return nil, ErrInvalidPRK
+	}
+	if err := o.checkLength(keyLen); err != nil {
+		return nil, err
+	}
+	return hkdf.Expand(o.newHash, prk, info, keyLen)
+}
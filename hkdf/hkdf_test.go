@@ -1,6 +1,8 @@
 package hkdf_test
 
 import (
+	"crypto"
+	"encoding/hex"
 	"fmt"
 	"testing"
 
@@ -57,6 +59,110 @@ func TestDeriveSHA512(t *testing.T) {
 	require.Len(t, k, 48)
 }
 
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// RFC5869 测试用例 1 的输入，SHA-256 结果即 RFC 给出的 PRK/OKM；
+// 其余摘要算法的结果由 OpenSSL 3.0 HKDF 独立计算。
+func TestExtractExpandVectors(t *testing.T) {
+	t.Parallel()
+	ikm := mustHex(t, "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b")
+	salt := mustHex(t, "000102030405060708090a0b0c")
+	info := string(mustHex(t, "f0f1f2f3f4f5f6f7f8f9"))
+
+	tests := []struct {
+		hash crypto.Hash
+		prk  string
+		okm  string
+	}{
+		{crypto.SHA256, "077709362c2e32df0ddc3f0dc47bba6390b6c73bb50f9c3122ec844ad7c2b3e5", "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"},
+		{crypto.SHA384, "704b39990779ce1dc548052c7dc39f303570dd13fb39f7acc564680bef80e8dec70ee9a7e1f3e293ef68eceb072a5ade", "9b5097a86038b805309076a44b3a9f38063e25b516dcbf369f394cfab43685f748b6457763e4f0204fc5"},
+		{crypto.SHA512, "665799823737ded04a88e47e54a5890bb2c3d247c7a4254a8e61350723590a26c36238127d8661b88cf80ef802d57e2f7cebcf1e00e083848be19929c61b4237", "832390086cda71fb47625bb5ceb168e4c8e26a1a16ed34d9fc7fe92c1481579338da362cb8d9f925d7cb"},
+		{crypto.SHA3_256, "7d4194836f7a113a44677abc825640ade07af1c1d69a9a4b109b280a8fe54ef0", "0c5160501d65021deaf2c14f5abce04c5bd2635abceeba61c2edb6e8ed72674900557728f2c9f2c4c179"},
+		{crypto.SHA3_384, "7855bc9300a4db532c9cab2593796e1a4bbb77a24d417e66822beaa36fabd412515dcf388810adf27fa23d3d7def84ca", "138d8521e5a346a9cb770f762b9c04d9ca317409fb6a3ef9cb905228385589ae883bbe8b07b009f0e08b"},
+		{crypto.SHA3_512, "e1c543094f64f3d6c6658a94a94e3818ba13d0b3e77074b80f88f32e6b8433b703536cb500753967fae2ea977e11e4dd4f45389807cdf255b395e46807c87d5d", "40e9f17e9bf2ef99425c2b23ccdf20a018ea5513f9ae68e1ea8c626deb57dfa4d56c27ccf2a2a24488a5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hash.String(), func(t *testing.T) {
+			t.Parallel()
+			prk, err := hkdf.Extract(ikm, salt, hkdf.WithHash(tt.hash))
+			require.NoError(t, err)
+			require.Equal(t, mustHex(t, tt.prk), prk)
+
+			okm, err := hkdf.Expand(prk, info, 42, hkdf.WithHash(tt.hash))
+			require.NoError(t, err)
+			require.Equal(t, mustHex(t, tt.okm), okm)
+
+			okm, err = hkdf.Derive(ikm, salt, info, 42, hkdf.WithHash(tt.hash))
+			require.NoError(t, err)
+			require.Equal(t, mustHex(t, tt.okm), okm)
+		})
+	}
+}
+
+func TestDeriveDefaultsToSHA256(t *testing.T) {
+	t.Parallel()
+	a, err := hkdf.Derive([]byte("s"), []byte("salt"), "i", 32)
+	require.NoError(t, err)
+	b, err := hkdf.Derive([]byte("s"), []byte("salt"), "i", 32, hkdf.WithHash(crypto.SHA256), nil)
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+
+	c, err := hkdf.DeriveSHA512([]byte("s"), []byte("salt"), "i", 32)
+	require.NoError(t, err)
+	d, err := hkdf.Derive([]byte("s"), []byte("salt"), "i", 32, hkdf.WithHash(crypto.SHA512))
+	require.NoError(t, err)
+	require.Equal(t, c, d)
+}
+
+func TestExtractOnceExpandMany(t *testing.T) {
+	t.Parallel()
+	prk, err := hkdf.Extract([]byte("shared-secret"), []byte("salt"))
+	require.NoError(t, err)
+	require.Len(t, prk, 32)
+	enc, err := hkdf.Expand(prk, "enc", 32)
+	require.NoError(t, err)
+	mac, err := hkdf.Expand(prk, "mac", 32)
+	require.NoError(t, err)
+	require.NotEqual(t, enc, mac)
+
+	want, err := hkdf.Derive([]byte("shared-secret"), []byte("salt"), "enc", 32)
+	require.NoError(t, err)
+	require.Equal(t, want, enc)
+}
+
+func TestExpandErrors(t *testing.T) {
+	t.Parallel()
+	prk := make([]byte, 32)
+	for _, n := range []int{0, -1, 255*32 + 1} {
+		_, err := hkdf.Expand(prk, "i", n)
+		require.ErrorIs(t, err, hkdf.ErrInvalidKeyLength, n)
+		_, err = hkdf.Derive([]byte("s"), nil, "i", n)
+		require.ErrorIs(t, err, hkdf.ErrInvalidKeyLength, n)
+	}
+	okm, err := hkdf.Expand(prk, "i", 255*32)
+	require.NoError(t, err)
+	require.Len(t, okm, 255*32)
+
+	_, err = hkdf.Expand(prk[:31], "i", 16)
+	require.ErrorIs(t, err, hkdf.ErrInvalidPRK)
+	_, err = hkdf.Expand(prk, "i", 16, hkdf.WithHash(crypto.SHA512))
+	require.ErrorIs(t, err, hkdf.ErrInvalidPRK)
+
+	for _, h := range []crypto.Hash{crypto.SHA1, crypto.MD5, crypto.SHA512_256, crypto.Hash(0)} {
+		_, err = hkdf.Extract([]byte("s"), nil, hkdf.WithHash(h))
+		require.ErrorIs(t, err, hkdf.ErrUnsupportedHash)
+		_, err = hkdf.Expand(prk, "i", 16, hkdf.WithHash(h))
+		require.ErrorIs(t, err, hkdf.ErrUnsupportedHash)
+		_, err = hkdf.Derive([]byte("s"), nil, "i", 16, hkdf.WithHash(h))
+		require.ErrorIs(t, err, hkdf.ErrUnsupportedHash)
+	}
+}
+
 func ExampleDerive() {
 	key, err := hkdf.Derive([]byte("shared-secret"), []byte("salt"), "app:v1", 32)
 	if err != nil {
@@ -65,3 +171,21 @@ func ExampleDerive() {
 	fmt.Println(len(key))
 	// Output: 32
 }
+
+func ExampleExpand() {
+	// 只做一次 Extract，再按用途 Expand 出多把子密钥。
+	prk, err := hkdf.Extract([]byte("shared-secret"), []byte("salt"))
+	if err != nil {
+		panic(err)
+	}
+	encKey, err := hkdf.Expand(prk, "app:v1 enc", 32)
+	if err != nil {
+		panic(err)
+	}
+	macKey, err := hkdf.Expand(prk, "app:v1 mac", 32)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(len(encKey), len(macKey))
+	// Output: 32 32
+}
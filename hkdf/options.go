@@ -0,0 +1,66 @@
+package hkdf
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"errors"
+	"hash"
+)
+
+// ErrUnsupportedHash 表示 WithHash 指定的摘要算法不受支持。
+var ErrUnsupportedHash = errors.New("hkdf: unsupported hash")
+
+// Option 用于定制 HKDF 的摘要算法（Functional Options）。
+type Option func(*options)
+
+type options struct {
+	hash        crypto.Hash
+	newHash     func() hash.Hash
+	labelPrefix string
+}
+
+// WithHash 指定摘要算法，支持 SHA-256（默认）、SHA-384、SHA-512、SHA3-256、SHA3-384、SHA3-512。
+func WithHash(h crypto.Hash) Option {
+	return func(o *options) { o.hash = h }
+}
+
+func newOptions(opts []Option) (*options, error) {
+	o := &options{hash: crypto.SHA256, labelPrefix: defaultLabelPrefix}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	switch o.hash {
+	case crypto.SHA256:
+		o.newHash = sha256.New
+	case crypto.SHA384:
+		o.newHash = sha512.New384
+	case crypto.SHA512:
+		o.newHash = sha512.New
+	case crypto.SHA3_256:
+		o.newHash = func() hash.Hash { return sha3.New256() }
+	case crypto.SHA3_384:
+		o.newHash = func() hash.Hash { return sha3.New384() }
+	case crypto.SHA3_512:
+		o.newHash = func() hash.Hash { return sha3.New512() }
+	default:
+		return nil, ErrUnsupportedHash
+	}
+	return o, nil
+}
+
+// size 返回摘要长度，即 PRK 的长度。
+func (o *options) size() int {
+	return o.hash.Size()
+}
+
+// checkLength 校验派生长度：RFC5869 限定 Expand 输出不超过 255 个摘要块。
+func (o *options) checkLength(keyLen int) error {
+	if keyLen <= 0 || keyLen > 255*o.size() {
+		return ErrInvalidKeyLength
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package hkdf
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// defaultLabelPrefix 为 ExpandLabel 默认的标签前缀，作用与 TLS 1.3 的 "tls13 " 相同：
+// 同一 PRK 即使被其他协议按相同标签派生，也得不到相同的子密钥。
+const defaultLabelPrefix = "encry "
+
+// ErrInvalidLabel 表示标签为空、前缀与标签合计超过 255 字节，或 context 超过 255 字节。
+var ErrInvalidLabel = errors.New("hkdf: invalid label")
+
+// WithLabelPrefix 指定 ExpandLabel/KeySchedule 的标签前缀，默认 "encry "；
+// 与 TLS 1.3 密钥调度互通时使用 "tls13 "。
+func WithLabelPrefix(prefix string) Option {
+	return func(o *options) { o.labelPrefix = prefix }
+}
+
+// ExpandLabel 按 RFC 8446 §7.1 的 HKDF-Expand-Label 由 PRK 派生 length 字节：
+//
+//	info = uint16(length) || uint8(len(prefix+label)) || prefix+label || uint8(len(context)) || context
+//
+// 输出长度写入 info，同一标签请求不同长度得到互不相关的密钥，而不是彼此的前缀。
+func ExpandLabel(prk []byte, label string, context []byte, length int, opts ...Option) ([]byte, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return o.expandLabel(prk, label, context, length)
+}
+
+func (o *options) expandLabel(prk []byte, label string, context []byte, length int) ([]byte, error) {
+	fullLabel := o.labelPrefix + label
+	if label == "" || len(fullLabel) > 255 || len(context) > 255 {
+		return nil, ErrInvalidLabel
+	}
+	if length > 0xffff {
+		return nil, ErrInvalidKeyLength
+	}
+	info := make([]byte, 0, 2+1+len(fullLabel)+1+len(context))
+	info = binary.BigEndian.AppendUint16(info, uint16(length)) // #nosec G115 -- length is checked above.
+	info = append(info, byte(len(fullLabel)))
+	info = append(info, fullLabel...)
+	info = append(info, byte(len(context)))
+	info = append(info, context...)
+	return o.expand(prk, string(info), length)
+}
+
+// KeySchedule 对一段密钥材料只做一次 Extract，再按标签派生任意多把子密钥，
+// 例如同一会话密钥派生出 "enc"、"mac"、"iv"。KeySchedule 创建后只读，可并发使用。
+type KeySchedule struct {
+	prk []byte
+	o   *options
+}
+
+// NewKeySchedule 对 secret 执行 Extract 并返回密钥调度；salt 可为 nil。
+func NewKeySchedule(secret, salt []byte, opts ...Option) (*KeySchedule, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	prk, err := Extract(secret, salt, WithHash(o.hash))
+	if err != nil {
+		return nil, err
+	}
+	return &KeySchedule{prk: prk, o: o}, nil
+}
+
+// NewKeyScheduleFromPRK 由已有的伪随机密钥（如 Extract 的输出）创建密钥调度。
+func NewKeyScheduleFromPRK(prk []byte, opts ...Option) (*KeySchedule, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(prk) < o.size() {
+		return nil, ErrInvalidPRK
+	}
+	return &KeySchedule{prk: append([]byte(nil), prk...), o: o}, nil
+}
+
+// Derive 按标签派生 length 字节子密钥（ExpandLabel）；context 可为 nil，
+// 通常放入会话标识或握手记录的摘要。
+func (k *KeySchedule) Derive(label string, context []byte, length int) ([]byte, error) {
+	return k.o.expandLabel(k.prk, label, context, length)
+}
+
+// Secret 按标签派生摘要长度的子密钥，并以它为 PRK 返回下一级密钥调度
+// （即 TLS 1.3 的 Derive-Secret），用于构建多级密钥层次。
+func (k *KeySchedule) Secret(label string, context []byte) (*KeySchedule, error) {
+	prk, err := k.Derive(label, context, k.o.size())
+	if err != nil {
+		return nil, err
+	}
+	return &KeySchedule{prk: prk, o: k.o}, nil
+}
+
+// PRK 返回当前级别的伪随机密钥副本，须保密。
+func (k *KeySchedule) PRK() []byte {
+	return append([]byte(nil), k.prk...)
+}
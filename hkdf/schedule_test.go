@@ -0,0 +1,171 @@
+package hkdf_test
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/hkdf"
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 8448 §3（Simple 1-RTT Handshake）的 TLS 1.3 密钥调度片段。
+func TestExpandLabelTLS13(t *testing.T) {
+	t.Parallel()
+	tls13 := hkdf.WithLabelPrefix("tls13 ")
+
+	early, err := hkdf.NewKeySchedule(make([]byte, 32), nil, tls13)
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, "33ad0a1c607ec03b09e6cd9893680ce210adf300aa1f2660e1b22e10f170f92a"), early.PRK())
+
+	emptyHash := sha256.Sum256(nil)
+	derived, err := early.Derive("derived", emptyHash[:], 32)
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, "6f2615a108c702c5678f54fc9dbab69716c076189c48250cebeac3576c3611ba"), derived)
+
+	ecdhe := mustHex(t, "8bd4054fb55b9d63fdfbacf9f04b9f0d35e6d63f537563efd46272900f89492d")
+	handshake, err := hkdf.NewKeySchedule(ecdhe, derived, tls13)
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, "1dc826e93606aa6fdc0aadc12f741b01046aa6b99f691ed221a9f0ca043fbeac"), handshake.PRK())
+
+	transcript := mustHex(t, "860c06edc07858ee8e78f0e7428c58edd6b43f2ca3e6e95f02ed063cf0e1cad8")
+	clientHS, err := handshake.Secret("c hs traffic", transcript)
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, "b3eddb126e067f35a780b3abf45e2d8f3b1a950738f52e9600746a0e27a55a21"), clientHS.PRK())
+
+	same, err := hkdf.ExpandLabel(handshake.PRK(), "c hs traffic", transcript, 32, tls13)
+	require.NoError(t, err)
+	require.Equal(t, clientHS.PRK(), same)
+}
+
+// 默认前缀 "encry "、HKDF-SHA384；期望值由 OpenSSL 3.0 TLS13-KDF（prefix "encry "）独立计算。
+func TestKeyScheduleSubkeys(t *testing.T) {
+	t.Parallel()
+	ks, err := hkdf.NewKeySchedule(
+		mustHex(t, "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b"),
+		mustHex(t, "000102030405060708090a0b0c"),
+		hkdf.WithHash(crypto.SHA384),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		label  string
+		length int
+		want   string
+	}{
+		{"enc", 32, "371c88c6a1ae65a63e03b67bef3d5fbd51b66d77da56fd1bea254c7b0edbe49e"},
+		{"mac", 48, "092f3a8eafe9e56cf2128496d05a007082f0045204be35e6d8f71949d4539d6329376eac171e9a6b3b2df07ab1647e67"},
+		{"iv", 12, "badc670baa937d17a99e5712"},
+	}
+	for _, tt := range tests {
+		got, err := ks.Derive(tt.label, []byte("session-1"), tt.length)
+		require.NoError(t, err)
+		require.Equal(t, mustHex(t, tt.want), got, tt.label)
+	}
+}
+
+func TestExpandLabelBindsLength(t *testing.T) {
+	t.Parallel()
+	ks, err := hkdf.NewKeySchedule([]byte("secret"), nil)
+	require.NoError(t, err)
+	short, err := ks.Derive("enc", nil, 16)
+	require.NoError(t, err)
+	long, err := ks.Derive("enc", nil, 32)
+	require.NoError(t, err)
+	// 长度写入 info：短密钥不是长密钥的前缀（普通 Expand 则是）。
+	require.NotEqual(t, short, long[:16])
+
+	prk := ks.PRK()
+	a, err := hkdf.Expand(prk, "enc", 16)
+	require.NoError(t, err)
+	b, err := hkdf.Expand(prk, "enc", 32)
+	require.NoError(t, err)
+	require.Equal(t, a, b[:16])
+}
+
+func TestKeyScheduleSeparation(t *testing.T) {
+	t.Parallel()
+	ks, err := hkdf.NewKeySchedule([]byte("secret"), []byte("salt"))
+	require.NoError(t, err)
+	tls, err := hkdf.NewKeySchedule([]byte("secret"), []byte("salt"), hkdf.WithLabelPrefix("tls13 "))
+	require.NoError(t, err)
+	require.Equal(t, ks.PRK(), tls.PRK())
+
+	enc, err := ks.Derive("enc", nil, 32)
+	require.NoError(t, err)
+	tlsEnc, err := tls.Derive("enc", nil, 32)
+	require.NoError(t, err)
+	require.NotEqual(t, enc, tlsEnc)
+
+	withCtx, err := ks.Derive("enc", []byte("session-2"), 32)
+	require.NoError(t, err)
+	require.NotEqual(t, enc, withCtx)
+
+	child, err := ks.Secret("child", nil)
+	require.NoError(t, err)
+	childEnc, err := child.Derive("enc", nil, 32)
+	require.NoError(t, err)
+	require.NotEqual(t, enc, childEnc)
+
+	fromPRK, err := hkdf.NewKeyScheduleFromPRK(ks.PRK())
+	require.NoError(t, err)
+	again, err := fromPRK.Derive("enc", nil, 32)
+	require.NoError(t, err)
+	require.Equal(t, enc, again)
+}
+
+func TestKeyScheduleErrors(t *testing.T) {
+	t.Parallel()
+	ks, err := hkdf.NewKeySchedule([]byte("secret"), nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		label   string
+		context []byte
+		length  int
+		err     error
+	}{
+		{"empty label", "", nil, 32, hkdf.ErrInvalidLabel},
+		{"label too long", strings.Repeat("l", 250), nil, 32, hkdf.ErrInvalidLabel},
+		{"context too long", "enc", make([]byte, 256), 32, hkdf.ErrInvalidLabel},
+		{"zero length", "enc", nil, 0, hkdf.ErrInvalidKeyLength},
+		{"too long for hash", "enc", nil, 255*32 + 1, hkdf.ErrInvalidKeyLength},
+		{"beyond uint16", "enc", nil, 1 << 16, hkdf.ErrInvalidKeyLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ks.Derive(tt.label, tt.context, tt.length)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	_, err = ks.Derive(strings.Repeat("l", 249), make([]byte, 255), 32)
+	require.NoError(t, err)
+
+	_, err = hkdf.NewKeyScheduleFromPRK(make([]byte, 31))
+	require.ErrorIs(t, err, hkdf.ErrInvalidPRK)
+	_, err = hkdf.NewKeySchedule([]byte("s"), nil, hkdf.WithHash(crypto.SHA1))
+	require.ErrorIs(t, err, hkdf.ErrUnsupportedHash)
+	_, err = hkdf.NewKeyScheduleFromPRK(make([]byte, 32), hkdf.WithHash(crypto.SHA1))
+	require.ErrorIs(t, err, hkdf.ErrUnsupportedHash)
+	_, err = hkdf.ExpandLabel(make([]byte, 32), "enc", nil, 32, hkdf.WithHash(crypto.SHA1))
+	require.ErrorIs(t, err, hkdf.ErrUnsupportedHash)
+}
+
+func ExampleKeySchedule() {
+	sessionSecret := []byte("ecdh-shared-secret")
+	ks, err := hkdf.NewKeySchedule(sessionSecret, []byte("handshake-salt"))
+	if err != nil {
+		panic(err)
+	}
+	// 一次 Extract，按用途派生子密钥；标签与长度都参与派生。
+	encKey, _ := ks.Derive("enc", nil, 32)
+	macKey, _ := ks.Derive("mac", nil, 32)
+	iv, _ := ks.Derive("iv", nil, 12)
+	fmt.Println(len(encKey), len(macKey), len(iv))
+	// Output: 32 32 12
+}
@@ -0,0 +1,223 @@
+package hpke
+
+import (
+	"crypto/cipher"
+	"crypto/ecdh"
+	"encoding/binary"
+	"math"
+)
+
+// Sender 是发送方的多次加密上下文（RFC9180 第 5.2 节）：一次 KEM 封装后可按序
+// 加密任意多条消息，每条消息使用递增的 nonce。不能被多个 goroutine 并发使用。
+type Sender struct {
+	enc []byte
+	ctx *context
+}
+
+// Receiver 是接收方的多次解密上下文，必须按 Sender 加密的顺序依次 Open。
+// 不能被多个 goroutine 并发使用。
+type Receiver struct {
+	ctx *context
+}
+
+// context 是 key schedule 的产物：AEAD 密钥、base_nonce、exporter_secret 与序号。
+type context struct {
+	suite          Suite
+	mode           Mode
+	aead           cipher.AEAD
+	baseNonce      []byte
+	exporterSecret []byte
+	seq            uint64
+}
+
+// SetupSender 用接收方公钥建立发送上下文，info 与 Seal 的含义相同。
+// 默认为 DefaultSuite 的 base 模式；WithSuite、WithPSK、WithSenderKey 选择套件与模式。
+// 封装密钥 Enc() 需随首条消息一起发给接收方。
+func SetupSender(pub *ecdh.PublicKey, info []byte, opts ...Option) (*Sender, error) {
+	o, err := newOptions(DefaultSuite, opts)
+	if err != nil {
+		return nil, err
+	}
+	return setupSender(pub, info, o, nil)
+}
+
+// setupSender 为 SetupSender 的内核；skE 非 nil 时使用固定临时私钥（仅测试向量）。
+func setupSender(pub *ecdh.PublicKey, info []byte, o *options, skE *ecdh.PrivateKey) (*Sender, error) {
+	if o.senderPub != nil {
+		// WithSenderPublicKey 只用于接收方。
+		return nil, ErrKeyMismatch
+	}
+	k, ok := kems[o.suite.KEM]
+	if !ok || pub == nil || pub.Curve() != k.curve {
+		return nil, ErrKeyMismatch
+	}
+	sharedSecret, enc, err := k.encap(o.suite.KEM, pub, o.senderKey, skE)
+	if err != nil {
+		return nil, err
+	}
+	return newSender(sharedSecret, enc, info, o)
+}
+
+// newSender 由 KEM 输出的共享密钥建立发送上下文，并清除 sharedSecret。
+func newSender(sharedSecret, enc, info []byte, o *options) (*Sender, error) {
+	ctx, err := keySchedule(o.suite, o.mode(), sharedSecret, info, o.psk, o.pskID)
+	clear(sharedSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &Sender{enc: enc, ctx: ctx}, nil
+}
+
+// SetupReceiver 用接收方私钥与发送方的封装密钥 enc 建立接收上下文，info 与选项必须与发送方一致；
+// Auth 模式用 WithSenderPublicKey 指定期望的发送方公钥。
+func SetupReceiver(priv *ecdh.PrivateKey, enc, info []byte, opts ...Option) (*Receiver, error) {
+	o, err := newOptions(DefaultSuite, opts)
+	if err != nil {
+		return nil, err
+	}
+	return setupReceiver(priv, enc, info, o)
+}
+
+func setupReceiver(priv *ecdh.PrivateKey, enc, info []byte, o *options) (*Receiver, error) {
+	if o.senderKey != nil {
+		// WithSenderKey 只用于发送方。
+		return nil, ErrKeyMismatch
+	}
+	k, ok := kems[o.suite.KEM]
+	if !ok || priv == nil || priv.Curve() != k.curve {
+		return nil, ErrKeyMismatch
+	}
+	sharedSecret, err := k.decap(o.suite.KEM, enc, priv, o.senderPub)
+	if err != nil {
+		return nil, err
+	}
+	return newReceiver(sharedSecret, info, o)
+}
+
+// newReceiver 由 KEM 输出的共享密钥建立接收上下文，并清除 sharedSecret。
+func newReceiver(sharedSecret, info []byte, o *options) (*Receiver, error) {
+	ctx, err := keySchedule(o.suite, o.mode(), sharedSecret, info, o.psk, o.pskID)
+	clear(sharedSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &Receiver{ctx: ctx}, nil
+}
+
+// keySchedule 即 RFC9180 第 5.1 节的 KeySchedule。
+func keySchedule(suite Suite, mode Mode, sharedSecret, info, psk, pskID []byte) (*context, error) {
+	sid := suite.id()
+	kdf := kdfs[suite.KDF]
+	pskIDHash, err := kdf.labeledExtract(sid, nil, "psk_id_hash", pskID)
+	if err != nil {
+		return nil, err
+	}
+	infoHash, err := kdf.labeledExtract(sid, nil, "info_hash", info)
+	if err != nil {
+		return nil, err
+	}
+	ksContext := append([]byte{byte(mode)}, pskIDHash...)
+	ksContext = append(ksContext, infoHash...)
+
+	secret, err := kdf.labeledExtract(sid, sharedSecret, "secret", psk)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(secret)
+
+	ctx := &context{suite: suite, mode: mode}
+	if ctx.exporterSecret, err = kdf.labeledExpand(sid, secret, "exp", ksContext, kdf.nh); err != nil {
+		return nil, err
+	}
+	params := aeads[suite.AEAD]
+	if params.newAEAD == nil {
+		return ctx, nil
+	}
+	key, err := kdf.labeledExpand(sid, secret, "key", ksContext, params.nk)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	if ctx.baseNonce, err = kdf.labeledExpand(sid, secret, "base_nonce", ksContext, params.nn); err != nil {
+		return nil, err
+	}
+	if ctx.aead, err = params.newAEAD(key); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// Enc 返回 KEM 封装密钥，接收方用它调用 SetupReceiver。
+func (s *Sender) Enc() []byte {
+	return append([]byte(nil), s.enc...)
+}
+
+// Suite 返回上下文使用的套件。
+func (s *Sender) Suite() Suite {
+	return s.ctx.suite
+}
+
+// Seal 加密下一条消息并绑定 aad，返回原始密文（不含 enc，不做 Base64）。
+func (s *Sender) Seal(aad, plainText []byte) ([]byte, error) {
+	nonce, err := s.ctx.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	out := s.ctx.aead.Seal(nil, nonce, plainText, aad)
+	s.ctx.seq++
+	return out, nil
+}
+
+// Export 按 RFC9180 第 5.3 节从上下文导出 length 字节密钥，接收方用相同的
+// exporterContext 得到相同结果；不同 exporterContext 导出的密钥互不相关。
+func (s *Sender) Export(exporterContext []byte, length int) ([]byte, error) {
+	return s.ctx.export(exporterContext, length)
+}
+
+// Suite 返回上下文使用的套件。
+func (r *Receiver) Suite() Suite {
+	return r.ctx.suite
+}
+
+// Open 解密下一条消息并校验 aad；失败时 nonce 不前进，可继续解密后续正确的消息。
+func (r *Receiver) Open(aad, cipherText []byte) ([]byte, error) {
+	nonce, err := r.ctx.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	out, err := r.ctx.aead.Open(nil, nonce, cipherText, aad)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	r.ctx.seq++
+	return out, nil
+}
+
+// Export 见 Sender.Export。
+func (r *Receiver) Export(exporterContext []byte, length int) ([]byte, error) {
+	return r.ctx.export(exporterContext, length)
+}
+
+// nextNonce 即 ComputeNonce(seq) = base_nonce XOR I2OSP(seq, Nn)。
+func (c *context) nextNonce() ([]byte, error) {
+	if c.aead == nil {
+		return nil, ErrExportOnly
+	}
+	if c.seq == math.MaxUint64 {
+		return nil, ErrMessageLimit
+	}
+	nonce := make([]byte, len(c.baseNonce))
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], c.seq)
+	for i := range nonce {
+		nonce[i] ^= c.baseNonce[i]
+	}
+	return nonce, nil
+}
+
+func (c *context) export(exporterContext []byte, length int) ([]byte, error) {
+	kdf := kdfs[c.suite.KDF]
+	if length <= 0 || length > 255*kdf.nh {
+		return nil, ErrInvalidExportLength
+	}
+	return kdf.labeledExpand(c.suite.id(), c.exporterSecret, "sec", exporterContext, length)
+}
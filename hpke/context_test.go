@@ -0,0 +1,110 @@
+package hpke_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gtkit/encry/hpke"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSenderReceiverSession(t *testing.T) {
+	t.Parallel()
+	priv, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+	info := []byte("app:session")
+
+	s, err := hpke.SetupSender(priv.PublicKey(), info)
+	require.NoError(t, err)
+	r, err := hpke.SetupReceiver(priv, s.Enc(), info)
+	require.NoError(t, err)
+
+	msgs := []string{"first", "second", "third"}
+	cts := make([][]byte, len(msgs))
+	for i, m := range msgs {
+		cts[i], err = s.Seal([]byte("aad"), []byte(m))
+		require.NoError(t, err)
+	}
+	// 相同明文在不同序号下得到不同密文。
+	again, err := s.Seal([]byte("aad"), []byte("first"))
+	require.NoError(t, err)
+	require.NotEqual(t, cts[0], again)
+
+	// 乱序失败且不推进序号，之后按序仍可解密。
+	_, err = r.Open([]byte("aad"), cts[1])
+	require.Error(t, err)
+	for i, ct := range cts {
+		pt, err := r.Open([]byte("aad"), ct)
+		require.NoError(t, err)
+		require.Equal(t, msgs[i], string(pt))
+	}
+
+	// 双方导出相同的会话密钥，不同 context 互不相同。
+	k1, err := s.Export([]byte("session key"), 32)
+	require.NoError(t, err)
+	k2, err := r.Export([]byte("session key"), 32)
+	require.NoError(t, err)
+	require.Equal(t, k1, k2)
+	k3, err := r.Export([]byte("mac key"), 32)
+	require.NoError(t, err)
+	require.NotEqual(t, k1, k3)
+}
+
+func TestSetupMismatch(t *testing.T) {
+	t.Parallel()
+	priv, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+	other, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+
+	s, err := hpke.SetupSender(priv.PublicKey(), []byte("info-a"))
+	require.NoError(t, err)
+	ct, err := s.Seal(nil, []byte("x"))
+	require.NoError(t, err)
+
+	r, err := hpke.SetupReceiver(priv, s.Enc(), []byte("info-b"))
+	require.NoError(t, err)
+	_, err = r.Open(nil, ct)
+	require.Error(t, err)
+
+	r, err = hpke.SetupReceiver(other, s.Enc(), []byte("info-a"))
+	require.NoError(t, err)
+	_, err = r.Open(nil, ct)
+	require.Error(t, err)
+
+	_, err = hpke.SetupReceiver(priv, s.Enc()[:16], nil)
+	require.Error(t, err)
+}
+
+func TestExportInvalidLength(t *testing.T) {
+	t.Parallel()
+	priv, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+	s, err := hpke.SetupSender(priv.PublicKey(), nil)
+	require.NoError(t, err)
+	_, err = s.Export(nil, 0)
+	require.ErrorIs(t, err, hpke.ErrInvalidExportLength)
+	_, err = s.Export(nil, 255*32+1)
+	require.ErrorIs(t, err, hpke.ErrInvalidExportLength)
+
+	r, err := hpke.SetupReceiver(priv, s.Enc(), nil)
+	require.NoError(t, err)
+	got, err := r.Export(nil, 255*32)
+	require.NoError(t, err)
+	require.Len(t, got, 255*32)
+}
+
+func ExampleSetupSender() {
+	priv, _ := hpke.GenerateKeyPair()
+
+	// 一次封装，加密多条消息；enc 随首条消息发给接收方。
+	s, _ := hpke.SetupSender(priv.PublicKey(), []byte("ctx"))
+	ct1, _ := s.Seal(nil, []byte("hello"))
+	ct2, _ := s.Seal(nil, []byte("world"))
+
+	r, _ := hpke.SetupReceiver(priv, s.Enc(), []byte("ctx"))
+	m1, _ := r.Open(nil, ct1)
+	m2, _ := r.Open(nil, ct2)
+	fmt.Println(string(m1), string(m2))
+	// Output: hello world
+}
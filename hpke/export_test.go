@@ -0,0 +1,12 @@
+package hpke
+
+import "crypto/ecdh"
+
+// SetupSenderWithEphemeral 暴露固定临时私钥的发送内核，供 RFC9180 向量校验 enc 与密文。
+func SetupSenderWithEphemeral(pub *ecdh.PublicKey, info []byte, skE *ecdh.PrivateKey, opts ...Option) (*Sender, error) {
+	o, err := newOptions(DefaultSuite, opts)
+	if err != nil {
+		return nil, err
+	}
+	return setupSender(pub, info, o, skE)
+}
@@ -1,17 +1,60 @@
-// Package hpke 提供混合公钥加密 HPKE（RFC9180，基于 go1.26 的 crypto/hpke）。
+// Package hpke 提供混合公钥加密 HPKE（RFC9180）。
 //
 // 只持有接收方公钥即可加密；解密需对应私钥。相比"用 RSA 直接加密大数据"的反模式，
 // HPKE 内部用 KEM 协商对称密钥再做 AEAD，没有明文长度限制、且更安全。
 //
-// 默认套件：DHKEM(X25519, HKDF-SHA256) + HKDF-SHA256 + ChaCha20-Poly1305。
+// 默认套件：DHKEM(X25519, HKDF-SHA256) + HKDF-SHA256 + ChaCha20-Poly1305，base 模式。
+// WithSuite 可选 P-256/384/521、HKDF-SHA384/512、AES-128/256-GCM（如 FIPS 场景的
+// SuiteP256AES128GCM）；WithPSK 与 WithSenderKey/WithSenderPublicKey 分别启用
+// mode_psk、mode_auth（同时使用即 mode_auth_psk），用预共享密钥或发送方静态密钥认证发送方。
 // info 是可选的上下文绑定（域分隔）：Seal 与 Open 必须使用相同的 info。
+//
+// SealPQ/OpenPQ 与 SetupSenderPQ/SetupReceiverPQ 改用混合后量子 KEM X-Wing
+// （MLKEM768-X25519，密钥见 xwing 包），适合需要长期保密的数据。
+//
+// Seal/Open 每条消息都做一次 KEM；同一会话的多条消息用 SetupSender/SetupReceiver
+// 建立上下文后按序加解密，并可用 Export 从同一次握手导出其他会话密钥。
+//
+// 密码原语均来自标准库（crypto/ecdh、crypto/hkdf、AES-GCM）与 x/crypto 的
+// ChaCha20-Poly1305，实现通过 RFC9180 附录 A 的全部 DHKEM 测试向量校验。
 package hpke
 
 import (
 	"crypto/ecdh"
-	stdhpke "crypto/hpke"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/binary"
+	"errors"
+)
+
+// formatVersion 为 Seal 输出的格式版本。
+//
+// 密文格式：版本号(1) || mode(1) || kem_id(2) || kdf_id(2) || aead_id(2) || enc || ct，
+// 整体 Base64(Std) 编码。接收方据头部拒绝非预期的套件与模式。
+const (
+	formatVersion byte = 1
+	headerSize         = 8
+)
+
+var (
+	// ErrInvalidCiphertext 表示密文格式非法或解密失败（被篡改、密钥/info/选项不符）。
+	ErrInvalidCiphertext = errors.New("hpke: invalid ciphertext")
+	// ErrSuiteMismatch 表示密文头部的套件或模式与接收方期望的不一致。
+	ErrSuiteMismatch = errors.New("hpke: unexpected suite or mode")
+	// ErrUnsupportedSuite 表示 KEM、KDF 或 AEAD 标识不受支持，或 KEM 不支持所选模式。
+	ErrUnsupportedSuite = errors.New("hpke: unsupported suite")
+	// ErrKeyMismatch 表示密钥为 nil、曲线与套件 KEM 不一致，或 Auth 选项用错了一方。
+	ErrKeyMismatch = errors.New("hpke: key does not match suite or mode")
+	// ErrInvalidPSK 表示 PSK 短于 32 字节或 PSK ID 为空。
+	ErrInvalidPSK = errors.New("hpke: PSK must be at least 32 bytes with a non-empty ID")
+	// ErrInvalidExportLength 表示 Export 请求的长度非法（<=0 或超过 KDF 上限 255*Nh）。
+	ErrInvalidExportLength = errors.New("hpke: invalid export length")
+	// ErrExportOnly 表示 AEADExportOnly 套件的上下文不能加解密。
+	ErrExportOnly = errors.New("hpke: export-only suite cannot seal or open")
+	// ErrMessageLimit 表示上下文的消息序号已用尽。
+	ErrMessageLimit = errors.New("hpke: message limit reached")
+	// ErrDeriveKeyPair 表示 DeriveKeyPair 拒绝采样失败（概率可忽略）。
+	ErrDeriveKeyPair = errors.New("hpke: derive key pair failed")
 )
 
 // GenerateKeyPair 生成一对 X25519 密钥用于 HPKE。
@@ -24,28 +67,86 @@ func ParsePublicKey(b []byte) (*ecdh.PublicKey, error) {
 	return ecdh.X25519().NewPublicKey(b)
 }
 
-// Seal 用接收方公钥加密明文，返回 Base64（封装密钥 enc 与密文的拼接）。
-func Seal(pub *ecdh.PublicKey, info, plainText []byte) (string, error) {
-	pk, err := stdhpke.NewDHKEMPublicKey(pub)
+// Seal 用接收方公钥加密明文，返回 Base64 编码的 头部 || enc || 密文。
+func Seal(pub *ecdh.PublicKey, info, plainText []byte, opts ...Option) (string, error) {
+	s, err := SetupSender(pub, info, opts...)
 	if err != nil {
 		return "", err
 	}
-	blob, err := stdhpke.Seal(pk, stdhpke.HKDFSHA256(), stdhpke.ChaCha20Poly1305(), info, plainText)
+	return sealSingle(s, plainText)
+}
+
+// sealSingle 用新建的上下文加密一条消息并输出带头部的 Base64 密文。
+func sealSingle(s *Sender, plainText []byte) (string, error) {
+	ct, err := s.Seal(nil, plainText)
 	if err != nil {
 		return "", err
 	}
-	return base64.StdEncoding.EncodeToString(blob), nil
+	out := make([]byte, 0, headerSize+len(s.enc)+len(ct))
+	out = append(out, formatVersion, byte(s.ctx.mode))
+	out = binary.BigEndian.AppendUint16(out, uint16(s.ctx.suite.KEM))
+	out = binary.BigEndian.AppendUint16(out, uint16(s.ctx.suite.KDF))
+	out = binary.BigEndian.AppendUint16(out, uint16(s.ctx.suite.AEAD))
+	out = append(out, s.enc...)
+	out = append(out, ct...)
+	return base64.StdEncoding.EncodeToString(out), nil
 }
 
-// Open 用接收方私钥解密 Seal 产生的密文。info 必须与加密时一致。
-func Open(priv *ecdh.PrivateKey, info []byte, cipherText string) ([]byte, error) {
+// Open 用接收方私钥解密 Seal 产生的密文。info 与选项必须与加密时一致，
+// 头部套件或模式不符时返回 ErrSuiteMismatch。
+//
+// 不带选项时也接受 v1.2 及更早版本 Seal 输出的无头部密文（enc || ct）。
+func Open(priv *ecdh.PrivateKey, info []byte, cipherText string, opts ...Option) ([]byte, error) {
 	raw, err := base64.StdEncoding.DecodeString(cipherText)
 	if err != nil {
 		return nil, err
 	}
-	sk, err := stdhpke.NewDHKEMPrivateKey(priv)
+	o, err := newOptions(DefaultSuite, opts)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := open(priv, info, raw, o)
+	if err != nil && o.suite == DefaultSuite && o.mode() == ModeBase {
+		if legacy, legacyErr := openRaw(priv, info, raw, o); legacyErr == nil {
+			return legacy, nil
+		}
+	}
+	return plain, err
+}
+
+func open(priv *ecdh.PrivateKey, info, raw []byte, o *options) ([]byte, error) {
+	body, err := checkHeader(raw, o)
+	if err != nil {
+		return nil, err
+	}
+	return openRaw(priv, info, body, o)
+}
+
+// checkHeader 校验头部的格式版本、套件与模式，返回其后的 enc || ct。
+func checkHeader(raw []byte, o *options) ([]byte, error) {
+	if len(raw) < headerSize || raw[0] != formatVersion {
+		return nil, ErrInvalidCiphertext
+	}
+	suite := Suite{
+		KEM:  KEMID(binary.BigEndian.Uint16(raw[2:])),
+		KDF:  KDFID(binary.BigEndian.Uint16(raw[4:])),
+		AEAD: AEADID(binary.BigEndian.Uint16(raw[6:])),
+	}
+	if Mode(raw[1]) != o.mode() || suite != o.suite {
+		return nil, ErrSuiteMismatch
+	}
+	return raw[headerSize:], nil
+}
+
+// openRaw 解密 enc || ct。
+func openRaw(priv *ecdh.PrivateKey, info, raw []byte, o *options) ([]byte, error) {
+	encSize := kems[o.suite.KEM].encSize()
+	if len(raw) < encSize {
+		return nil, ErrInvalidCiphertext
+	}
+	r, err := setupReceiver(priv, raw[:encSize], info, o)
 	if err != nil {
 		return nil, err
 	}
-	return stdhpke.Open(sk, stdhpke.HKDFSHA256(), stdhpke.ChaCha20Poly1305(), info, raw)
+	return r.Open(nil, raw[encSize:])
 }
@@ -0,0 +1,113 @@
+package hpke
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// kemSuiteID 返回 DHKEM 内部使用的 suite_id = "KEM" || kem_id。
+func kemSuiteID(id KEMID) []byte {
+	return binary.BigEndian.AppendUint16([]byte("KEM"), uint16(id))
+}
+
+// DeriveKeyPair 按 RFC9180 第 7.1.3 节由输入密钥材料 ikm 确定性派生 KEM 私钥。
+// ikm 应至少与私钥等长且具有足够熵。
+func DeriveKeyPair(kem KEMID, ikm []byte) (*ecdh.PrivateKey, error) {
+	k, ok := kems[kem]
+	if !ok {
+		return nil, ErrUnsupportedSuite
+	}
+	sid := kemSuiteID(kem)
+	prk, err := k.kdf.labeledExtract(sid, nil, "dkp_prk", ikm)
+	if err != nil {
+		return nil, err
+	}
+	if k.curve == ecdh.X25519() {
+		sk, err := k.kdf.labeledExpand(sid, prk, "sk", nil, k.nsk)
+		if err != nil {
+			return nil, err
+		}
+		return k.curve.NewPrivateKey(sk)
+	}
+	// NIST 曲线：拒绝采样，候选值为 0 或不小于阶时换下一个计数器。
+	for counter := range 256 {
+		sk, err := k.kdf.labeledExpand(sid, prk, "candidate", []byte{byte(counter)}, k.nsk)
+		if err != nil {
+			return nil, err
+		}
+		sk[0] &= k.bitmask
+		if priv, err := k.curve.NewPrivateKey(sk); err == nil {
+			return priv, nil
+		}
+	}
+	return nil, ErrDeriveKeyPair
+}
+
+// encap 实现 Encap/AuthEncap：skS 为 nil 时为 base/psk 模式。
+func (k kemParams) encap(id KEMID, pkR *ecdh.PublicKey, skS, skE *ecdh.PrivateKey) (sharedSecret, enc []byte, err error) {
+	if skE == nil {
+		if skE, err = k.curve.GenerateKey(rand.Reader); err != nil {
+			return nil, nil, err
+		}
+	}
+	dh, err := skE.ECDH(pkR)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc = skE.PublicKey().Bytes()
+	kemContext := append(append([]byte(nil), enc...), pkR.Bytes()...)
+	if skS != nil {
+		dhS, err := skS.ECDH(pkR)
+		if err != nil {
+			return nil, nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, skS.PublicKey().Bytes()...)
+	}
+	sharedSecret, err = k.extractAndExpand(id, dh, kemContext)
+	clear(dh)
+	return sharedSecret, enc, err
+}
+
+// decap 实现 Decap/AuthDecap：pkS 为 nil 时为 base/psk 模式。
+func (k kemParams) decap(id KEMID, enc []byte, skR *ecdh.PrivateKey, pkS *ecdh.PublicKey) ([]byte, error) {
+	pkE, err := k.curve.NewPublicKey(enc)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	dh, err := skR.ECDH(pkE)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	kemContext := append(append([]byte(nil), enc...), skR.PublicKey().Bytes()...)
+	if pkS != nil {
+		dhS, err := skR.ECDH(pkS)
+		if err != nil {
+			return nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, pkS.Bytes()...)
+	}
+	sharedSecret, err := k.extractAndExpand(id, dh, kemContext)
+	clear(dh)
+	return sharedSecret, err
+}
+
+func (k kemParams) extractAndExpand(id KEMID, dh, kemContext []byte) ([]byte, error) {
+	sid := kemSuiteID(id)
+	prk, err := k.kdf.labeledExtract(sid, nil, "eae_prk", dh)
+	if err != nil {
+		return nil, err
+	}
+	return k.kdf.labeledExpand(sid, prk, "shared_secret", kemContext, k.nsecret)
+}
+
+// encSize 返回封装密钥 enc 的长度（未压缩点或 X25519 公钥）。
+func (k kemParams) encSize() int {
+	if k.curve == ecdh.X25519() {
+		return 32
+	}
+	// 0x04 || X || Y。
+	return 1 + 2*k.nsk
+}
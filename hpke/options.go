@@ -0,0 +1,91 @@
+package hpke
+
+import (
+	"crypto/ecdh"
+	"fmt"
+)
+
+// minPSKSize 为 RFC9180 第 5.1.2 节要求的 PSK 最小熵（32 字节）。
+const minPSKSize = 32
+
+// Option 用于定制套件与模式（Functional Options），Seal/Open 与 SetupSender/SetupReceiver 通用。
+type Option func(*options)
+
+type options struct {
+	suite     Suite
+	pskSet    bool
+	psk       []byte
+	pskID     []byte
+	senderKey *ecdh.PrivateKey
+	senderPub *ecdh.PublicKey
+}
+
+// WithSuite 指定密码套件，默认 DefaultSuite。接收方按此拒绝套件不符的密文。
+func WithSuite(s Suite) Option {
+	return func(o *options) { o.suite = s }
+}
+
+// WithPSK 启用 PSK 模式：双方必须持有相同的预共享密钥 psk（至少 32 字节）与标识 pskID。
+// psk 过短或 pskID 为空（包括 nil）时返回 ErrInvalidPSK，不会退回 base 模式。
+func WithPSK(psk, pskID []byte) Option {
+	return func(o *options) {
+		o.pskSet = true
+		o.psk = append([]byte(nil), psk...)
+		o.pskID = append([]byte(nil), pskID...)
+	}
+}
+
+// WithSenderKey 启用 Auth 模式（发送方）：用发送方静态私钥认证发送方，
+// 曲线必须与套件 KEM 一致。
+func WithSenderKey(priv *ecdh.PrivateKey) Option {
+	return func(o *options) { o.senderKey = priv }
+}
+
+// WithSenderPublicKey 启用 Auth 模式（接收方）：只接受该公钥对应私钥的持有者发来的密文。
+func WithSenderPublicKey(pub *ecdh.PublicKey) Option {
+	return func(o *options) { o.senderPub = pub }
+}
+
+// newOptions 应用选项；def 为未指定 WithSuite 时的默认套件。
+func newOptions(def Suite, opts []Option) (*options, error) {
+	o := &options{suite: def}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	if err := o.suite.Validate(); err != nil {
+		return nil, err
+	}
+	if o.pskSet {
+		if len(o.psk) < minPSKSize || len(o.pskID) == 0 {
+			return nil, ErrInvalidPSK
+		}
+	}
+	if o.suite.KEM == KEMMLKEM768X25519 {
+		if o.senderKey != nil || o.senderPub != nil {
+			return nil, fmt.Errorf("%w: KEM 0x%04x 不支持 Auth 模式", ErrUnsupportedSuite, uint16(o.suite.KEM))
+		}
+		return o, nil
+	}
+	curve := kems[o.suite.KEM].curve
+	if o.senderKey != nil && o.senderKey.Curve() != curve {
+		return nil, ErrKeyMismatch
+	}
+	if o.senderPub != nil && o.senderPub.Curve() != curve {
+		return nil, ErrKeyMismatch
+	}
+	return o, nil
+}
+
+// mode 由选项推导：WithPSK 与 WithSenderKey/WithSenderPublicKey 分别启用 PSK 与 Auth。
+func (o *options) mode() Mode {
+	m := ModeBase
+	if o.pskSet {
+		m |= ModePSK
+	}
+	if o.senderKey != nil || o.senderPub != nil {
+		m |= ModeAuth
+	}
+	return m
+}
@@ -0,0 +1,99 @@
+package hpke
+
+import (
+	"crypto/sha3"
+	"encoding/base64"
+	"encoding/binary"
+
+	"github.com/gtkit/encry/xwing"
+)
+
+// SetupSenderPQ 用 X-Wing 公钥建立发送上下文，默认套件为 SuiteXWing。
+// 支持 base 与 PSK 模式；X-Wing 没有 AuthEncap，WithSenderKey 返回 ErrUnsupportedSuite。
+func SetupSenderPQ(pub *xwing.PublicKey, info []byte, opts ...Option) (*Sender, error) {
+	o, err := newOptions(SuiteXWing, opts)
+	if err != nil {
+		return nil, err
+	}
+	if o.suite.KEM != KEMMLKEM768X25519 || pub == nil {
+		return nil, ErrKeyMismatch
+	}
+	sharedSecret, enc, err := xwing.Encapsulate(pub)
+	if err != nil {
+		return nil, err
+	}
+	return newSender(sharedSecret, enc, info, o)
+}
+
+// SetupReceiverPQ 用 X-Wing 私钥与封装密钥 enc 建立接收上下文，见 SetupReceiver。
+func SetupReceiverPQ(priv *xwing.PrivateKey, enc, info []byte, opts ...Option) (*Receiver, error) {
+	o, err := newOptions(SuiteXWing, opts)
+	if err != nil {
+		return nil, err
+	}
+	return setupReceiverPQ(priv, enc, info, o)
+}
+
+func setupReceiverPQ(priv *xwing.PrivateKey, enc, info []byte, o *options) (*Receiver, error) {
+	if o.suite.KEM != KEMMLKEM768X25519 || priv == nil {
+		return nil, ErrKeyMismatch
+	}
+	sharedSecret, err := xwing.Decapsulate(priv, enc)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return newReceiver(sharedSecret, info, o)
+}
+
+// SealPQ 与 Seal 相同，但使用混合后量子 KEM X-Wing：即使将来 X25519 被量子计算机攻破，
+// 密文仍受 ML-KEM-768 保护。输出格式与 Seal 相同，kem_id 为 0x647a。
+func SealPQ(pub *xwing.PublicKey, info, plainText []byte, opts ...Option) (string, error) {
+	s, err := SetupSenderPQ(pub, info, opts...)
+	if err != nil {
+		return "", err
+	}
+	return sealSingle(s, plainText)
+}
+
+// OpenPQ 用 X-Wing 私钥解密 SealPQ 产生的密文，info 与选项必须与加密时一致。
+func OpenPQ(priv *xwing.PrivateKey, info []byte, cipherText string, opts ...Option) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(cipherText)
+	if err != nil {
+		return nil, err
+	}
+	o, err := newOptions(SuiteXWing, opts)
+	if err != nil {
+		return nil, err
+	}
+	body, err := checkHeader(raw, o)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < xwing.CiphertextSize {
+		return nil, ErrInvalidCiphertext
+	}
+	r, err := setupReceiverPQ(priv, body[:xwing.CiphertextSize], info, o)
+	if err != nil {
+		return nil, err
+	}
+	return r.Open(nil, body[xwing.CiphertextSize:])
+}
+
+// DeriveKeyPairPQ 按 draft-ietf-hpke-pq 由输入密钥材料 ikm 确定性派生 X-Wing 私钥：
+// 私钥种子 = SHAKE256 LabeledDerive(ikm, "DeriveKeyPair", 32)。
+func DeriveKeyPairPQ(ikm []byte) (*xwing.PrivateKey, error) {
+	h := sha3.NewSHAKE256()
+	h.Write(ikm)
+	h.Write([]byte(versionLabel))
+	h.Write(kemSuiteID(KEMMLKEM768X25519))
+	const label = "DeriveKeyPair"
+	h.Write(binary.BigEndian.AppendUint16(nil, uint16(len(label))))
+	h.Write([]byte(label))
+	h.Write(binary.BigEndian.AppendUint16(nil, xwing.SeedSize))
+	seed := make([]byte, xwing.SeedSize)
+	if _, err := h.Read(seed); err != nil {
+		return nil, err
+	}
+	defer clear(seed)
+	return xwing.NewPrivateKey(seed)
+}
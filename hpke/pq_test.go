@@ -0,0 +1,148 @@
+package hpke_test
+
+import (
+	stdhpke "crypto/hpke"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/gtkit/encry/hpke"
+	"github.com/gtkit/encry/xwing"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPQVector 用 draft-ietf-hpke-pq 的 MLKEM768-X25519 向量（取自 Go 标准库 crypto/hpke 测试数据）
+// 校验 DeriveKeyPairPQ 与接收方的解密、导出。
+func TestPQVector(t *testing.T) {
+	t.Parallel()
+	raw, err := os.ReadFile("testdata/hpke_pq_vectors.json")
+	require.NoError(t, err)
+	var vectors []rfc9180Vector
+	require.NoError(t, json.Unmarshal(raw, &vectors))
+	require.NotEmpty(t, vectors)
+
+	for _, v := range vectors {
+		suite := hpke.Suite{KEM: v.KEMID, KDF: v.KDFID, AEAD: v.AEADID}
+		require.Equal(t, hpke.SuiteXWing, suite)
+
+		priv, err := hpke.DeriveKeyPairPQ(v.IkmR)
+		require.NoError(t, err)
+		require.Equal(t, []byte(v.SkRm), priv.Bytes())
+		require.Equal(t, []byte(v.PkRm), priv.PublicKey().Bytes())
+
+		r, err := hpke.SetupReceiverPQ(priv, v.Enc, v.Info)
+		require.NoError(t, err)
+		for _, e := range v.Encryptions {
+			pt, err := r.Open(e.AAD, e.CT)
+			require.NoError(t, err)
+			require.Equal(t, []byte(e.PT), pt)
+		}
+		for _, e := range v.Exports {
+			got, err := r.Export(e.Context, e.L)
+			require.NoError(t, err)
+			require.Equal(t, []byte(e.Value), got)
+		}
+	}
+}
+
+func TestSealOpenPQ(t *testing.T) {
+	t.Parallel()
+	psk := []byte("0123456789abcdef0123456789abcdef")
+	tests := []struct {
+		name string
+		opts []hpke.Option
+	}{
+		{"default", nil},
+		{"aes-256-gcm", []hpke.Option{hpke.WithSuite(hpke.Suite{KEM: hpke.KEMMLKEM768X25519, KDF: hpke.KDFHKDFSHA384, AEAD: hpke.AEADAES256GCM})}},
+		{"psk", []hpke.Option{hpke.WithPSK(psk, []byte("id"))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			priv, err := xwing.GenerateKey()
+			require.NoError(t, err)
+			ct, err := hpke.SealPQ(priv.PublicKey(), []byte("archive"), []byte("long-lived secret"), tt.opts...)
+			require.NoError(t, err)
+			pt, err := hpke.OpenPQ(priv, []byte("archive"), ct, tt.opts...)
+			require.NoError(t, err)
+			require.Equal(t, "long-lived secret", string(pt))
+
+			_, err = hpke.OpenPQ(priv, []byte("other"), ct, tt.opts...)
+			require.ErrorIs(t, err, hpke.ErrInvalidCiphertext)
+			other, err := xwing.GenerateKey()
+			require.NoError(t, err)
+			_, err = hpke.OpenPQ(other, []byte("archive"), ct, tt.opts...)
+			require.ErrorIs(t, err, hpke.ErrInvalidCiphertext)
+		})
+	}
+}
+
+func TestPQSuiteErrors(t *testing.T) {
+	t.Parallel()
+	priv, err := xwing.GenerateKey()
+	require.NoError(t, err)
+	classic, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+
+	// 经典与后量子的密文互不接受。
+	ct, err := hpke.SealPQ(priv.PublicKey(), nil, []byte("x"))
+	require.NoError(t, err)
+	_, err = hpke.Open(classic, nil, ct)
+	require.ErrorIs(t, err, hpke.ErrSuiteMismatch)
+	ct, err = hpke.Seal(classic.PublicKey(), nil, []byte("x"))
+	require.NoError(t, err)
+	_, err = hpke.OpenPQ(priv, nil, ct)
+	require.ErrorIs(t, err, hpke.ErrSuiteMismatch)
+
+	_, err = hpke.SealPQ(priv.PublicKey(), nil, []byte("x"), hpke.WithSuite(hpke.DefaultSuite))
+	require.ErrorIs(t, err, hpke.ErrKeyMismatch)
+	_, err = hpke.Seal(classic.PublicKey(), nil, []byte("x"), hpke.WithSuite(hpke.SuiteXWing))
+	require.ErrorIs(t, err, hpke.ErrKeyMismatch)
+	_, err = hpke.SealPQ(priv.PublicKey(), nil, []byte("x"), hpke.WithSenderKey(classic))
+	require.ErrorIs(t, err, hpke.ErrUnsupportedSuite)
+	_, err = hpke.SealPQ(nil, nil, []byte("x"))
+	require.ErrorIs(t, err, hpke.ErrKeyMismatch)
+	_, err = hpke.SetupReceiverPQ(priv, make([]byte, 10), nil)
+	require.ErrorIs(t, err, hpke.ErrInvalidCiphertext)
+}
+
+// TestPQInteropStdlib 与标准库 crypto/hpke 的 MLKEM768X25519 双向互通。
+func TestPQInteropStdlib(t *testing.T) {
+	t.Parallel()
+	priv, err := xwing.GenerateKey()
+	require.NoError(t, err)
+	stdPriv, err := stdhpke.MLKEM768X25519().NewPrivateKey(priv.Bytes())
+	require.NoError(t, err)
+	info := []byte("interop")
+
+	s, err := hpke.SetupSenderPQ(priv.PublicKey(), info)
+	require.NoError(t, err)
+	ct, err := s.Seal([]byte("aad"), []byte("to stdlib"))
+	require.NoError(t, err)
+	r, err := stdhpke.NewRecipient(s.Enc(), stdPriv, stdhpke.HKDFSHA256(), stdhpke.ChaCha20Poly1305(), info)
+	require.NoError(t, err)
+	pt, err := r.Open([]byte("aad"), ct)
+	require.NoError(t, err)
+	require.Equal(t, "to stdlib", string(pt))
+
+	enc, stdSender, err := stdhpke.NewSender(stdPriv.PublicKey(), stdhpke.HKDFSHA256(), stdhpke.ChaCha20Poly1305(), info)
+	require.NoError(t, err)
+	ct, err = stdSender.Seal([]byte("aad"), []byte("from stdlib"))
+	require.NoError(t, err)
+	ours, err := hpke.SetupReceiverPQ(priv, enc, info)
+	require.NoError(t, err)
+	pt, err = ours.Open([]byte("aad"), ct)
+	require.NoError(t, err)
+	require.Equal(t, "from stdlib", string(pt))
+}
+
+func ExampleSealPQ() {
+	priv, _ := xwing.GenerateKey()
+
+	// 长期归档：X25519 与 ML-KEM-768 同时保护，任一方安全即安全。
+	ct, _ := hpke.SealPQ(priv.PublicKey(), []byte("archive:2026"), []byte("backup key"))
+	pt, _ := hpke.OpenPQ(priv, []byte("archive:2026"), ct)
+	fmt.Println(string(pt))
+	// Output: backup key
+}
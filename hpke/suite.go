@@ -0,0 +1,203 @@
+package hpke
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// KEMID 是 RFC9180 第 7.1 节的 KEM 标识。
+type KEMID uint16
+
+// KDFID 是 RFC9180 第 7.2 节的 KDF 标识。
+type KDFID uint16
+
+// AEADID 是 RFC9180 第 7.3 节的 AEAD 标识。
+type AEADID uint16
+
+// 支持的算法标识。
+const (
+	KEMP256HKDFSHA256   KEMID = 0x0010
+	KEMP384HKDFSHA384   KEMID = 0x0011
+	KEMP521HKDFSHA512   KEMID = 0x0012
+	KEMX25519HKDFSHA256 KEMID = 0x0020
+	// KEMMLKEM768X25519 为混合后量子 KEM X-Wing（draft-ietf-hpke-pq），密钥类型见 xwing 包。
+	KEMMLKEM768X25519 KEMID = 0x647a
+
+	KDFHKDFSHA256 KDFID = 0x0001
+	KDFHKDFSHA384 KDFID = 0x0002
+	KDFHKDFSHA512 KDFID = 0x0003
+
+	AEADAES128GCM        AEADID = 0x0001
+	AEADAES256GCM        AEADID = 0x0002
+	AEADChaCha20Poly1305 AEADID = 0x0003
+	// AEADExportOnly 表示只用 Export 导出密钥，上下文不能 Seal/Open。
+	AEADExportOnly AEADID = 0xFFFF
+)
+
+// Suite 是 HPKE 密码套件：KEM、KDF、AEAD 三者的组合。
+type Suite struct {
+	KEM  KEMID
+	KDF  KDFID
+	AEAD AEADID
+}
+
+var (
+	// DefaultSuite 为 DHKEM(X25519, HKDF-SHA256) + HKDF-SHA256 + ChaCha20-Poly1305，
+	// 即未指定 WithSuite 时的套件。
+	DefaultSuite = Suite{KEM: KEMX25519HKDFSHA256, KDF: KDFHKDFSHA256, AEAD: AEADChaCha20Poly1305}
+	// SuiteP256AES128GCM 为 DHKEM(P-256, HKDF-SHA256) + HKDF-SHA256 + AES-128-GCM，
+	// 全部为 FIPS 批准算法，适合有合规要求的对接方。
+	SuiteP256AES128GCM = Suite{KEM: KEMP256HKDFSHA256, KDF: KDFHKDFSHA256, AEAD: AEADAES128GCM}
+	// SuiteXWing 为 MLKEM768-X25519 + HKDF-SHA256 + ChaCha20-Poly1305，
+	// 即 SealPQ/SetupSenderPQ 未指定 WithSuite 时的套件。
+	SuiteXWing = Suite{KEM: KEMMLKEM768X25519, KDF: KDFHKDFSHA256, AEAD: AEADChaCha20Poly1305}
+)
+
+// Mode 是 RFC9180 第 5 节的模式。
+type Mode uint8
+
+// 四种模式：PSK 模式用预共享密钥认证双方，Auth 模式用发送方静态私钥认证发送方。
+const (
+	ModeBase    Mode = 0x00
+	ModePSK     Mode = 0x01
+	ModeAuth    Mode = 0x02
+	ModeAuthPSK Mode = 0x03
+)
+
+// String 返回 RFC 中的模式名，如 "mode_auth"。
+func (m Mode) String() string {
+	switch m {
+	case ModeBase:
+		return "mode_base"
+	case ModePSK:
+		return "mode_psk"
+	case ModeAuth:
+		return "mode_auth"
+	case ModeAuthPSK:
+		return "mode_auth_psk"
+	default:
+		return fmt.Sprintf("mode(%d)", uint8(m))
+	}
+}
+
+// String 返回套件的十六进制标识，如 "0x0020/0x0001/0x0003"。
+func (s Suite) String() string {
+	return fmt.Sprintf("0x%04x/0x%04x/0x%04x", uint16(s.KEM), uint16(s.KDF), uint16(s.AEAD))
+}
+
+// Validate 检查套件中的三个算法是否都受支持。
+func (s Suite) Validate() error {
+	if _, ok := kems[s.KEM]; !ok && s.KEM != KEMMLKEM768X25519 {
+		return fmt.Errorf("%w: KEM 0x%04x", ErrUnsupportedSuite, uint16(s.KEM))
+	}
+	if _, ok := kdfs[s.KDF]; !ok {
+		return fmt.Errorf("%w: KDF 0x%04x", ErrUnsupportedSuite, uint16(s.KDF))
+	}
+	if _, ok := aeads[s.AEAD]; !ok {
+		return fmt.Errorf("%w: AEAD 0x%04x", ErrUnsupportedSuite, uint16(s.AEAD))
+	}
+	return nil
+}
+
+// id 返回 key schedule 使用的 suite_id = "HPKE" || kem_id || kdf_id || aead_id。
+func (s Suite) id() []byte {
+	b := []byte("HPKE")
+	b = binary.BigEndian.AppendUint16(b, uint16(s.KEM))
+	b = binary.BigEndian.AppendUint16(b, uint16(s.KDF))
+	return binary.BigEndian.AppendUint16(b, uint16(s.AEAD))
+}
+
+// kdfParams 是基于 HKDF 的 KDF，Nh 为摘要长度。
+type kdfParams struct {
+	newHash func() hash.Hash
+	nh      int
+}
+
+var kdfs = map[KDFID]kdfParams{
+	KDFHKDFSHA256: {sha256.New, sha256.Size},
+	KDFHKDFSHA384: {sha512.New384, sha512.Size384},
+	KDFHKDFSHA512: {sha512.New, sha512.Size},
+}
+
+const versionLabel = "HPKE-v1"
+
+// labeledExtract 即 RFC9180 第 4 节的 LabeledExtract(salt, label, ikm)。
+func (k kdfParams) labeledExtract(suiteID, salt []byte, label string, ikm []byte) ([]byte, error) {
+	labeled := make([]byte, 0, len(versionLabel)+len(suiteID)+len(label)+len(ikm))
+	labeled = append(labeled, versionLabel...)
+	labeled = append(labeled, suiteID...)
+	labeled = append(labeled, label...)
+	labeled = append(labeled, ikm...)
+	return hkdf.Extract(k.newHash, labeled, salt)
+}
+
+// labeledExpand 即 RFC9180 第 4 节的 LabeledExpand(prk, label, info, L)。
+func (k kdfParams) labeledExpand(suiteID, prk []byte, label string, info []byte, length int) ([]byte, error) {
+	if length > 0xFFFF {
+		return nil, ErrInvalidExportLength
+	}
+	labeled := make([]byte, 0, 2+len(versionLabel)+len(suiteID)+len(label)+len(info))
+	labeled = binary.BigEndian.AppendUint16(labeled, uint16(length)) // #nosec G115 -- 已检查 length <= 0xFFFF.
+	labeled = append(labeled, versionLabel...)
+	labeled = append(labeled, suiteID...)
+	labeled = append(labeled, label...)
+	labeled = append(labeled, info...)
+	return hkdf.Expand(k.newHash, prk, string(labeled), length)
+}
+
+// aeadParams 描述 AEAD 的密钥与 nonce 长度；newAEAD 为 nil 表示 export-only。
+type aeadParams struct {
+	nk, nn  int
+	newAEAD func(key []byte) (cipher.AEAD, error)
+}
+
+var aeads = map[AEADID]aeadParams{
+	AEADAES128GCM:        {16, 12, newGCM},
+	AEADAES256GCM:        {32, 12, newGCM},
+	AEADChaCha20Poly1305: {chacha20poly1305.KeySize, chacha20poly1305.NonceSize, chacha20poly1305.New},
+	AEADExportOnly:       {},
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// kemParams 描述 DHKEM：曲线、KEM 内部使用的 KDF、共享密钥长度 Nsecret、
+// 私钥长度 Nsk 与 DeriveKeyPair 候选值首字节掩码。
+type kemParams struct {
+	curve   ecdh.Curve
+	kdf     kdfParams
+	nsecret int
+	nsk     int
+	bitmask byte
+}
+
+var kems = map[KEMID]kemParams{
+	KEMP256HKDFSHA256:   {ecdh.P256(), kdfs[KDFHKDFSHA256], 32, 32, 0xFF},
+	KEMP384HKDFSHA384:   {ecdh.P384(), kdfs[KDFHKDFSHA384], 48, 48, 0xFF},
+	KEMP521HKDFSHA512:   {ecdh.P521(), kdfs[KDFHKDFSHA512], 64, 66, 0x01},
+	KEMX25519HKDFSHA256: {ecdh.X25519(), kdfs[KDFHKDFSHA256], 32, 32, 0},
+}
+
+// KEMForCurve 返回曲线对应的 DHKEM 标识。
+func KEMForCurve(curve ecdh.Curve) (KEMID, error) {
+	for id, k := range kems {
+		if k.curve == curve {
+			return id, nil
+		}
+	}
+	return 0, ErrUnsupportedSuite
+}
@@ -0,0 +1,327 @@
+package hpke_test
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/gtkit/encry/hpke"
+	"github.com/stretchr/testify/require"
+)
+
+type hexBytes []byte
+
+func (h *hexBytes) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	raw, err := hex.DecodeString(s)
+	*h = raw
+	return err
+}
+
+type rfc9180Vector struct {
+	Mode        hpke.Mode   `json:"mode"`
+	KEMID       hpke.KEMID  `json:"kem_id"`
+	KDFID       hpke.KDFID  `json:"kdf_id"`
+	AEADID      hpke.AEADID `json:"aead_id"`
+	Info        hexBytes    `json:"info"`
+	IkmR        hexBytes    `json:"ikmR"`
+	IkmE        hexBytes    `json:"ikmE"`
+	IkmS        hexBytes    `json:"ikmS"`
+	SkRm        hexBytes    `json:"skRm"`
+	SkEm        hexBytes    `json:"skEm"`
+	SkSm        hexBytes    `json:"skSm"`
+	PkRm        hexBytes    `json:"pkRm"`
+	PkSm        hexBytes    `json:"pkSm"`
+	PSK         hexBytes    `json:"psk"`
+	PSKID       hexBytes    `json:"psk_id"`
+	Enc         hexBytes    `json:"enc"`
+	Encryptions []struct {
+		AAD hexBytes `json:"aad"`
+		CT  hexBytes `json:"ct"`
+		PT  hexBytes `json:"pt"`
+	} `json:"encryptions"`
+	Exports []struct {
+		Context hexBytes `json:"exporter_context"`
+		L       int      `json:"L"`
+		Value   hexBytes `json:"exported_value"`
+	} `json:"exports"`
+}
+
+// loadRFC9180Vectors 读取 RFC9180 附录 A 的 DHKEM 测试向量（摘自 CFRG 官方 JSON，
+// 每组只保留前 4 条加密）。
+func loadRFC9180Vectors(t *testing.T) []rfc9180Vector {
+	t.Helper()
+	raw, err := os.ReadFile("testdata/rfc9180_vectors.json")
+	require.NoError(t, err)
+	var vectors []rfc9180Vector
+	require.NoError(t, json.Unmarshal(raw, &vectors))
+	return vectors
+}
+
+func TestRFC9180Vectors(t *testing.T) {
+	t.Parallel()
+	vectors := loadRFC9180Vectors(t)
+	require.Len(t, vectors, 96)
+
+	for _, v := range vectors {
+		suite := hpke.Suite{KEM: v.KEMID, KDF: v.KDFID, AEAD: v.AEADID}
+		t.Run(fmt.Sprintf("%s/%s", v.Mode, suite), func(t *testing.T) {
+			t.Parallel()
+			require.NoError(t, suite.Validate())
+
+			skR, err := hpke.DeriveKeyPair(v.KEMID, v.IkmR)
+			require.NoError(t, err)
+			require.Equal(t, []byte(v.SkRm), skR.Bytes())
+			require.Equal(t, []byte(v.PkRm), skR.PublicKey().Bytes())
+			skE, err := hpke.DeriveKeyPair(v.KEMID, v.IkmE)
+			require.NoError(t, err)
+			require.Equal(t, []byte(v.SkEm), skE.Bytes())
+
+			senderOpts := []hpke.Option{hpke.WithSuite(suite)}
+			receiverOpts := []hpke.Option{hpke.WithSuite(suite)}
+			if v.PSK != nil {
+				senderOpts = append(senderOpts, hpke.WithPSK(v.PSK, v.PSKID))
+				receiverOpts = append(receiverOpts, hpke.WithPSK(v.PSK, v.PSKID))
+			}
+			if v.IkmS != nil {
+				skS, err := hpke.DeriveKeyPair(v.KEMID, v.IkmS)
+				require.NoError(t, err)
+				require.Equal(t, []byte(v.SkSm), skS.Bytes())
+				senderOpts = append(senderOpts, hpke.WithSenderKey(skS))
+				receiverOpts = append(receiverOpts, hpke.WithSenderPublicKey(skS.PublicKey()))
+			}
+
+			s, err := hpke.SetupSenderWithEphemeral(skR.PublicKey(), v.Info, skE, senderOpts...)
+			require.NoError(t, err)
+			require.Equal(t, []byte(v.Enc), s.Enc())
+			r, err := hpke.SetupReceiver(skR, v.Enc, v.Info, receiverOpts...)
+			require.NoError(t, err)
+
+			for i, e := range v.Encryptions {
+				ct, err := s.Seal(e.AAD, e.PT)
+				require.NoError(t, err)
+				require.Equal(t, []byte(e.CT), ct, "encryption %d", i)
+				pt, err := r.Open(e.AAD, e.CT)
+				require.NoError(t, err)
+				require.Equal(t, []byte(e.PT), pt)
+			}
+			if v.AEADID == hpke.AEADExportOnly {
+				_, err = s.Seal(nil, []byte("x"))
+				require.ErrorIs(t, err, hpke.ErrExportOnly)
+				_, err = r.Open(nil, []byte("x"))
+				require.ErrorIs(t, err, hpke.ErrExportOnly)
+			}
+			for _, e := range v.Exports {
+				got, err := s.Export(e.Context, e.L)
+				require.NoError(t, err)
+				require.Equal(t, []byte(e.Value), got)
+				got, err = r.Export(e.Context, e.L)
+				require.NoError(t, err)
+				require.Equal(t, []byte(e.Value), got)
+			}
+		})
+	}
+}
+
+func TestSealOpenSuitesAndModes(t *testing.T) {
+	t.Parallel()
+	psk := []byte("0123456789abcdef0123456789abcdef")
+	suites := []hpke.Suite{
+		hpke.DefaultSuite,
+		hpke.SuiteP256AES128GCM,
+		{KEM: hpke.KEMP384HKDFSHA384, KDF: hpke.KDFHKDFSHA384, AEAD: hpke.AEADAES256GCM},
+		{KEM: hpke.KEMP521HKDFSHA512, KDF: hpke.KDFHKDFSHA512, AEAD: hpke.AEADAES256GCM},
+	}
+	for _, suite := range suites {
+		for _, mode := range []hpke.Mode{hpke.ModeBase, hpke.ModePSK, hpke.ModeAuth, hpke.ModeAuthPSK} {
+			t.Run(fmt.Sprintf("%s/%s", mode, suite), func(t *testing.T) {
+				t.Parallel()
+				curve := curveFor(t, suite.KEM)
+				priv, err := curve.GenerateKey(rand.Reader)
+				require.NoError(t, err)
+				senderKey, err := curve.GenerateKey(rand.Reader)
+				require.NoError(t, err)
+
+				senderOpts := []hpke.Option{hpke.WithSuite(suite)}
+				receiverOpts := []hpke.Option{hpke.WithSuite(suite)}
+				if mode&hpke.ModePSK != 0 {
+					senderOpts = append(senderOpts, hpke.WithPSK(psk, []byte("id")))
+					receiverOpts = append(receiverOpts, hpke.WithPSK(psk, []byte("id")))
+				}
+				if mode&hpke.ModeAuth != 0 {
+					senderOpts = append(senderOpts, hpke.WithSenderKey(senderKey))
+					receiverOpts = append(receiverOpts, hpke.WithSenderPublicKey(senderKey.PublicKey()))
+				}
+
+				ct, err := hpke.Seal(priv.PublicKey(), []byte("info"), []byte("hello"), senderOpts...)
+				require.NoError(t, err)
+				raw, err := base64.StdEncoding.DecodeString(ct)
+				require.NoError(t, err)
+				require.Equal(t, byte(mode), raw[1])
+				require.Equal(t, uint16(suite.KEM), binary.BigEndian.Uint16(raw[2:]))
+				require.Equal(t, uint16(suite.KDF), binary.BigEndian.Uint16(raw[4:]))
+				require.Equal(t, uint16(suite.AEAD), binary.BigEndian.Uint16(raw[6:]))
+
+				pt, err := hpke.Open(priv, []byte("info"), ct, receiverOpts...)
+				require.NoError(t, err)
+				require.Equal(t, "hello", string(pt))
+
+				// 接收方期望的套件或模式不同：按头部直接拒绝。
+				_, err = hpke.Open(priv, []byte("info"), ct, hpke.WithSuite(suite))
+				if mode != hpke.ModeBase {
+					require.ErrorIs(t, err, hpke.ErrSuiteMismatch)
+				}
+				other := hpke.Suite{KEM: suite.KEM, KDF: suite.KDF, AEAD: hpke.AEADChaCha20Poly1305}
+				if other != suite {
+					_, err = hpke.Open(priv, []byte("info"), ct, append(receiverOpts, hpke.WithSuite(other))...)
+					require.ErrorIs(t, err, hpke.ErrSuiteMismatch)
+				}
+			})
+		}
+	}
+}
+
+func TestOpenAuthWrongSender(t *testing.T) {
+	t.Parallel()
+	priv, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+	sender, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+	impostor, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+
+	ct, err := hpke.Seal(priv.PublicKey(), nil, []byte("x"), hpke.WithSenderKey(impostor))
+	require.NoError(t, err)
+	_, err = hpke.Open(priv, nil, ct, hpke.WithSenderPublicKey(sender.PublicKey()))
+	require.ErrorIs(t, err, hpke.ErrInvalidCiphertext)
+}
+
+func TestOpenPSKMismatch(t *testing.T) {
+	t.Parallel()
+	priv, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+	psk := []byte("0123456789abcdef0123456789abcdef")
+	ct, err := hpke.Seal(priv.PublicKey(), nil, []byte("x"), hpke.WithPSK(psk, []byte("id")))
+	require.NoError(t, err)
+
+	_, err = hpke.Open(priv, nil, ct, hpke.WithPSK(psk, []byte("other")))
+	require.ErrorIs(t, err, hpke.ErrInvalidCiphertext)
+	wrong := append([]byte(nil), psk...)
+	wrong[0] ^= 1
+	_, err = hpke.Open(priv, nil, ct, hpke.WithPSK(wrong, []byte("id")))
+	require.ErrorIs(t, err, hpke.ErrInvalidCiphertext)
+}
+
+func TestOpenLegacyCiphertext(t *testing.T) {
+	t.Parallel()
+	priv, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+	// 旧版格式：enc || ct，无头部。
+	s, err := hpke.SetupSender(priv.PublicKey(), []byte("info"))
+	require.NoError(t, err)
+	ct, err := s.Seal(nil, []byte("legacy"))
+	require.NoError(t, err)
+	legacy := base64.StdEncoding.EncodeToString(append(s.Enc(), ct...))
+
+	pt, err := hpke.Open(priv, []byte("info"), legacy)
+	require.NoError(t, err)
+	require.Equal(t, "legacy", string(pt))
+
+	// 指定了非默认套件或模式时不再接受无头部密文。
+	_, err = hpke.Open(priv, []byte("info"), legacy, hpke.WithPSK(make([]byte, 32), []byte("id")))
+	require.Error(t, err)
+}
+
+func TestOptionErrors(t *testing.T) {
+	t.Parallel()
+	x25519, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+	p256, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		opts []hpke.Option
+		want error
+	}{
+		{"short psk", []hpke.Option{hpke.WithPSK(make([]byte, 31), []byte("id"))}, hpke.ErrInvalidPSK},
+		{"empty psk id", []hpke.Option{hpke.WithPSK(make([]byte, 32), nil)}, hpke.ErrInvalidPSK},
+		{"nil psk and id", []hpke.Option{hpke.WithPSK(nil, nil)}, hpke.ErrInvalidPSK},
+		{"empty psk and id", []hpke.Option{hpke.WithPSK([]byte{}, []byte{})}, hpke.ErrInvalidPSK},
+		{"unknown kem", []hpke.Option{hpke.WithSuite(hpke.Suite{KEM: 0x99, KDF: hpke.KDFHKDFSHA256, AEAD: hpke.AEADAES128GCM})}, hpke.ErrUnsupportedSuite},
+		{"unknown kdf", []hpke.Option{hpke.WithSuite(hpke.Suite{KEM: hpke.KEMX25519HKDFSHA256, KDF: 9, AEAD: hpke.AEADAES128GCM})}, hpke.ErrUnsupportedSuite},
+		{"unknown aead", []hpke.Option{hpke.WithSuite(hpke.Suite{KEM: hpke.KEMX25519HKDFSHA256, KDF: hpke.KDFHKDFSHA256, AEAD: 9})}, hpke.ErrUnsupportedSuite},
+		{"sender key curve", []hpke.Option{hpke.WithSenderKey(p256)}, hpke.ErrKeyMismatch},
+		{"receiver option on sender", []hpke.Option{hpke.WithSenderPublicKey(x25519.PublicKey())}, hpke.ErrKeyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := hpke.Seal(x25519.PublicKey(), nil, []byte("x"), tt.opts...)
+			require.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	// 公钥曲线与套件不符。
+	_, err = hpke.Seal(p256.PublicKey(), nil, []byte("x"))
+	require.ErrorIs(t, err, hpke.ErrKeyMismatch)
+	_, err = hpke.SetupReceiver(x25519, make([]byte, 32), nil, hpke.WithSenderKey(x25519))
+	require.ErrorIs(t, err, hpke.ErrKeyMismatch)
+}
+
+func TestSealExportOnly(t *testing.T) {
+	t.Parallel()
+	priv, err := hpke.GenerateKeyPair()
+	require.NoError(t, err)
+	suite := hpke.Suite{KEM: hpke.KEMX25519HKDFSHA256, KDF: hpke.KDFHKDFSHA256, AEAD: hpke.AEADExportOnly}
+	_, err = hpke.Seal(priv.PublicKey(), nil, []byte("x"), hpke.WithSuite(suite))
+	require.ErrorIs(t, err, hpke.ErrExportOnly)
+}
+
+func TestKEMForCurve(t *testing.T) {
+	t.Parallel()
+	for _, id := range []hpke.KEMID{hpke.KEMP256HKDFSHA256, hpke.KEMP384HKDFSHA384, hpke.KEMP521HKDFSHA512, hpke.KEMX25519HKDFSHA256} {
+		got, err := hpke.KEMForCurve(curveFor(t, id))
+		require.NoError(t, err)
+		require.Equal(t, id, got)
+	}
+	_, err := hpke.DeriveKeyPair(0x99, make([]byte, 32))
+	require.ErrorIs(t, err, hpke.ErrUnsupportedSuite)
+	require.Equal(t, "mode_auth_psk", hpke.ModeAuthPSK.String())
+	require.Equal(t, "0x0020/0x0001/0x0003", hpke.DefaultSuite.String())
+}
+
+func curveFor(t *testing.T, id hpke.KEMID) ecdh.Curve {
+	t.Helper()
+	switch id {
+	case hpke.KEMP256HKDFSHA256:
+		return ecdh.P256()
+	case hpke.KEMP384HKDFSHA384:
+		return ecdh.P384()
+	case hpke.KEMP521HKDFSHA512:
+		return ecdh.P521()
+	default:
+		return ecdh.X25519()
+	}
+}
+
+func ExampleWithSenderKey() {
+	recipient, _ := hpke.GenerateKeyPair()
+	sender, _ := hpke.GenerateKeyPair()
+
+	// Auth 模式：接收方只接受持有 sender 私钥的一方发来的密文。
+	ct, _ := hpke.Seal(recipient.PublicKey(), []byte("ctx"), []byte("signed by sender"),
+		hpke.WithSuite(hpke.DefaultSuite), hpke.WithSenderKey(sender))
+	pt, err := hpke.Open(recipient, []byte("ctx"), ct, hpke.WithSenderPublicKey(sender.PublicKey()))
+	fmt.Println(string(pt), err)
+	// Output: signed by sender <nil>
+}
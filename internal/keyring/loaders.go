@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/gtkit/encry/ed"
+	"github.com/gtkit/encry/mlkem"
 	encryrsa "github.com/gtkit/encry/rsa"
 )
 
@@ -23,6 +24,12 @@ type RSAKeyPair struct {
 	Public  *stdrsa.PublicKey
 }
 
+// MLKEMKeyPair 表示一个可用于密钥封装的 ML-KEM 密钥对.
+type MLKEMKeyPair struct {
+	Private *mlkem.DecapsulationKey
+	Public  *mlkem.EncapsulationKey
+}
+
 // LoadStringKeys 加载形如 <kid><suffix> 的字符串密钥文件.
 func LoadStringKeys(dir, suffix string) (map[string]string, error) {
 	entries, err := os.ReadDir(dir)
@@ -74,8 +81,55 @@ func LoadEd25519KeyPairs(dir string) (map[string]Ed25519KeyPair, error) {
 	return keys, nil
 }
 
-// LoadRSAKeyPairs 从 <dir>/<kid>/{private,public}.pem 加载 RSA 密钥对.
-func LoadRSAKeyPairs(dir string) (map[string]RSAKeyPair, error) {
+// RSALoadOption 定制 RSA 密钥对的加载行为.
+type RSALoadOption func(*rsaLoadOptions)
+
+type rsaLoadOptions struct {
+	policy *encryrsa.KeyPolicy
+}
+
+// WithRSAKeyPolicy 在加载时按 policy 检查每一对私钥与公钥，任一不满足即返回包装了
+// *encryrsa.PolicyError 的错误（错误信息含文件路径），避免弱密钥进入密钥环.
+func WithRSAKeyPolicy(policy encryrsa.KeyPolicy) RSALoadOption {
+	return func(o *rsaLoadOptions) { o.policy = &policy }
+}
+
+func newRSALoadOptions(opts []RSALoadOption) rsaLoadOptions {
+	var o rsaLoadOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// readRSAKeyPair 读取 <dir>/<kid>/{private,public}.pem，配置了策略时同时做策略检查.
+func readRSAKeyPair(dir, kid string, o rsaLoadOptions) (RSAKeyPair, error) {
+	privatePath := filepath.Join(dir, kid, "private.pem")
+	publicPath := filepath.Join(dir, kid, "public.pem")
+	readPrivate, readPublic := encryrsa.ReadPrivateKey, encryrsa.ReadPublicKey
+	if o.policy != nil {
+		readPrivate, readPublic = o.policy.ReadPrivateKey, o.policy.ReadPublicKey
+	}
+
+	privateKey, err := readPrivate(privatePath)
+	if err != nil {
+		return RSAKeyPair{}, err
+	}
+	publicKey, err := readPublic(publicPath)
+	if err != nil {
+		return RSAKeyPair{}, err
+	}
+	return RSAKeyPair{
+		Private: privateKey,
+		Public:  publicKey,
+	}, nil
+}
+
+// LoadRSAKeyPairs 从 <dir>/<kid>/{private,public}.pem 加载 RSA 密钥对，可用 WithRSAKeyPolicy 强制密钥策略.
+func LoadRSAKeyPairs(dir string, opts ...RSALoadOption) (map[string]RSAKeyPair, error) {
+	o := newRSALoadOptions(opts)
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, err
@@ -87,15 +141,37 @@ func LoadRSAKeyPairs(dir string) (map[string]RSAKeyPair, error) {
 			continue
 		}
 		kid := entry.Name()
-		privateKey, err := encryrsa.ReadPrivateKey(filepath.Join(dir, kid, "private.pem"))
+		pair, err := readRSAKeyPair(dir, kid, o)
+		if err != nil {
+			return nil, err
+		}
+		keys[kid] = pair
+	}
+	return keys, nil
+}
+
+// LoadMLKEMKeyPairs 从 <dir>/<kid>/{private,public}.pem 加载 ML-KEM 密钥对，参数集由 PEM 中的 OID 识别.
+func LoadMLKEMKeyPairs(dir string) (map[string]MLKEMKeyPair, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]MLKEMKeyPair, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		kid := entry.Name()
+		privateKey, err := mlkem.ReadPrivateKey(filepath.Join(dir, kid, "private.pem"))
 		if err != nil {
 			return nil, err
 		}
-		publicKey, err := encryrsa.ReadPublicKey(filepath.Join(dir, kid, "public.pem"))
+		publicKey, err := mlkem.ReadPublicKey(filepath.Join(dir, kid, "public.pem"))
 		if err != nil {
 			return nil, err
 		}
-		keys[kid] = RSAKeyPair{
+		keys[kid] = MLKEMKeyPair{
 			Private: privateKey,
 			Public:  publicKey,
 		}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/gtkit/encry/ed"
+	"github.com/gtkit/encry/mlkem"
 	encryrsa "github.com/gtkit/encry/rsa"
 	"github.com/stretchr/testify/require"
 )
@@ -28,12 +29,18 @@ func writeEd25519Pair(t *testing.T, dir, kid string) {
 	require.NoError(t, os.WriteFile(filepath.Join(keyDir, "public.pem"), pubPEM, 0o600))
 }
 
-// writeRSAPair writes a freshly generated RSA key pair under
+// writeRSAPair writes a freshly generated 2048-bit RSA key pair under
 // <dir>/<kid>/{private,public}.pem.
 func writeRSAPair(t *testing.T, dir, kid string) {
 	t.Helper()
+	writeRSAPairBits(t, dir, kid, 2048)
+}
+
+// writeRSAPairBits is writeRSAPair with an explicit modulus size.
+func writeRSAPairBits(t *testing.T, dir, kid string, bits int) {
+	t.Helper()
 
-	priv, pub, err := encryrsa.GenerateKeyPair(2048)
+	priv, pub, err := encryrsa.GenerateKeyPair(bits)
 	require.NoError(t, err)
 	privPEM := encryrsa.MarshalPKCS1PrivateKeyPEM(priv)
 	pubPEM := encryrsa.MarshalPKCS1PublicKeyPEM(pub)
@@ -154,4 +161,98 @@ func TestLoadRSAKeyPairs(t *testing.T) {
 		_, err := LoadRSAKeyPairs(dir)
 		require.Error(t, err)
 	})
+
+	t.Run("policy accepts strong keys", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeRSAPair(t, dir, "rsa-1")
+
+		keys, err := LoadRSAKeyPairs(dir, WithRSAKeyPolicy(encryrsa.DefaultKeyPolicy()), nil)
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+	})
+
+	t.Run("policy rejects weak key", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeRSAPair(t, dir, "rsa-1")
+		writeRSAPairBits(t, dir, "rsa-weak", 1024)
+
+		// Without a policy the weak key is loaded as before.
+		keys, err := LoadRSAKeyPairs(dir)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+
+		_, err = LoadRSAKeyPairs(dir, WithRSAKeyPolicy(encryrsa.DefaultKeyPolicy()))
+		var policyErr *encryrsa.PolicyError
+		require.ErrorAs(t, err, &policyErr)
+		require.True(t, policyErr.Has(encryrsa.RuleMinBits))
+		require.ErrorIs(t, err, encryrsa.ErrKeyPolicy)
+		require.ErrorContains(t, err, filepath.Join("rsa-weak", "private.pem"))
+	})
+}
+
+// writeMLKEMPair writes a freshly generated ML-KEM key pair under
+// <dir>/<kid>/{private,public}.pem.
+func writeMLKEMPair(t *testing.T, dir, kid string, params mlkem.ParameterSet) {
+	t.Helper()
+
+	priv, err := mlkem.GenerateKey(params)
+	require.NoError(t, err)
+	privPEM, err := mlkem.MarshalPrivateKeyPEM(priv)
+	require.NoError(t, err)
+	pubPEM, err := mlkem.MarshalPublicKeyPEM(priv.EncapsulationKey())
+	require.NoError(t, err)
+
+	keyDir := filepath.Join(dir, kid)
+	require.NoError(t, os.MkdirAll(keyDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(keyDir, "private.pem"), privPEM, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(keyDir, "public.pem"), pubPEM, 0o600))
+}
+
+func TestLoadMLKEMKeyPairs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads both parameter sets", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeMLKEMPair(t, dir, "pq-768", mlkem.MLKEM768)
+		writeMLKEMPair(t, dir, "pq-1024", mlkem.MLKEM1024)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "stray.pem"), []byte("x"), 0o600))
+
+		keys, err := LoadMLKEMKeyPairs(dir)
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		require.Equal(t, mlkem.MLKEM768, keys["pq-768"].Private.Parameters())
+		require.Equal(t, mlkem.MLKEM1024, keys["pq-1024"].Public.Parameters())
+	})
+
+	t.Run("missing dir errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := LoadMLKEMKeyPairs(filepath.Join(t.TempDir(), "nope"))
+		require.Error(t, err)
+	})
+
+	t.Run("ed25519 key in mlkem dir errors", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeEd25519Pair(t, dir, "k1")
+		_, err := LoadMLKEMKeyPairs(dir)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid public pem errors", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeMLKEMPair(t, dir, "k1", mlkem.MLKEM768)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "k1", "public.pem"), []byte("garbage"), 0o600))
+		_, err := LoadMLKEMKeyPairs(dir)
+		require.Error(t, err)
+	})
 }
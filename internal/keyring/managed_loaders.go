@@ -6,7 +6,7 @@ import (
 	"strings"
 
 	"github.com/gtkit/encry/ed"
-	encryrsa "github.com/gtkit/encry/rsa"
+	"github.com/gtkit/encry/mlkem"
 	json "github.com/gtkit/json/v2"
 )
 
@@ -75,8 +75,10 @@ func LoadEd25519KeyPairRecords(dir string) (map[string]Record[Ed25519KeyPair], e
 	return keys, nil
 }
 
-// LoadRSAKeyPairRecords 从 <dir>/<kid>/{private,public,metadata}.pem/json 加载 RSA 密钥对.
-func LoadRSAKeyPairRecords(dir string) (map[string]Record[RSAKeyPair], error) {
+// LoadRSAKeyPairRecords 从 <dir>/<kid>/{private,public,metadata}.pem/json 加载 RSA 密钥对，
+// 可用 WithRSAKeyPolicy 强制密钥策略.
+func LoadRSAKeyPairRecords(dir string, opts ...RSALoadOption) (map[string]Record[RSAKeyPair], error) {
+	o := newRSALoadOptions(opts)
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return nil, err
@@ -88,20 +90,50 @@ func LoadRSAKeyPairRecords(dir string) (map[string]Record[RSAKeyPair], error) {
 			continue
 		}
 		kid := entry.Name()
-		privateKey, err := encryrsa.ReadPrivateKey(filepath.Join(dir, kid, "private.pem"))
+		pair, err := readRSAKeyPair(dir, kid, o)
 		if err != nil {
 			return nil, err
 		}
-		publicKey, err := encryrsa.ReadPublicKey(filepath.Join(dir, kid, "public.pem"))
+		metadata, err := loadMetadata(filepath.Join(dir, kid, "metadata.json"), kid, "PS512", "sig")
 		if err != nil {
 			return nil, err
 		}
-		metadata, err := loadMetadata(filepath.Join(dir, kid, "metadata.json"), kid, "PS512", "sig")
+		keys[kid] = Record[RSAKeyPair]{
+			Key:      pair,
+			Metadata: metadata,
+		}
+	}
+	return keys, nil
+}
+
+// LoadMLKEMKeyPairRecords 从 <dir>/<kid>/{private,public,metadata}.pem/json 加载 ML-KEM 密钥对，
+// 默认 metadata 的算法为参数集名称（如 ML-KEM-768），用途为 enc.
+func LoadMLKEMKeyPairRecords(dir string) (map[string]Record[MLKEMKeyPair], error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]Record[MLKEMKeyPair], len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		kid := entry.Name()
+		privateKey, err := mlkem.ReadPrivateKey(filepath.Join(dir, kid, "private.pem"))
 		if err != nil {
 			return nil, err
 		}
-		keys[kid] = Record[RSAKeyPair]{
-			Key: RSAKeyPair{
+		publicKey, err := mlkem.ReadPublicKey(filepath.Join(dir, kid, "public.pem"))
+		if err != nil {
+			return nil, err
+		}
+		metadata, err := loadMetadata(filepath.Join(dir, kid, "metadata.json"), kid, privateKey.Parameters().String(), "enc")
+		if err != nil {
+			return nil, err
+		}
+		keys[kid] = Record[MLKEMKeyPair]{
+			Key: MLKEMKeyPair{
 				Private: privateKey,
 				Public:  publicKey,
 			},
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gtkit/encry/mlkem"
+	encryrsa "github.com/gtkit/encry/rsa"
 	"github.com/stretchr/testify/require"
 )
 
@@ -150,6 +152,59 @@ func TestLoadRSAKeyPairRecords(t *testing.T) {
 		_, err := LoadRSAKeyPairRecords(filepath.Join(t.TempDir(), "nope"))
 		require.Error(t, err)
 	})
+
+	t.Run("policy rejects weak key", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeRSAPairBits(t, dir, "rsa-weak", 1024)
+
+		_, err := LoadRSAKeyPairRecords(dir, WithRSAKeyPolicy(encryrsa.KeyPolicy{MinBits: 2048}))
+		var policyErr *encryrsa.PolicyError
+		require.ErrorAs(t, err, &policyErr)
+		require.True(t, policyErr.Has(encryrsa.RuleMinBits))
+
+		records, err := LoadRSAKeyPairRecords(dir, WithRSAKeyPolicy(encryrsa.KeyPolicy{MinBits: 1024}))
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+	})
+}
+
+func TestLoadMLKEMKeyPairRecords(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default metadata uses parameter set", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeMLKEMPair(t, dir, "pq-1", mlkem.MLKEM1024)
+		meta := []byte(`{"status":"retiring"}`)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "pq-1", "metadata.json"), meta, 0o600))
+
+		records, err := LoadMLKEMKeyPairRecords(dir)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		require.Equal(t, "ML-KEM-1024", records["pq-1"].Metadata.Algorithm)
+		require.Equal(t, "enc", records["pq-1"].Metadata.Use)
+		require.Equal(t, StatusRetiring, records["pq-1"].Metadata.Status)
+		require.NotNil(t, records["pq-1"].Key.Public)
+	})
+
+	t.Run("missing private pem errors", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "bad"), 0o700))
+		_, err := LoadMLKEMKeyPairRecords(dir)
+		require.Error(t, err)
+	})
+
+	t.Run("missing dir errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := LoadMLKEMKeyPairRecords(filepath.Join(t.TempDir(), "nope"))
+		require.Error(t, err)
+	})
 }
 
 func TestLoadMetadataReadError(t *testing.T) {
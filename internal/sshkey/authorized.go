@@ -0,0 +1,30 @@
+package sshkey
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// ErrInvalidComment 表示注释含换行符（CR/LF），写入 authorized_keys 会注入额外的行.
+var ErrInvalidComment = errors.New("authorized_keys comment must not contain CR or LF")
+
+// MarshalAuthorizedKey 将公钥编码为一行 authorized_keys（"<type> AAAA... comment\n"），
+// comment 为空时省略.
+//
+// 注释原样拼接在行尾，含 CR/LF 时可借此追加受信任的公钥或选项，因此直接拒绝.
+func MarshalAuthorizedKey(key ssh.PublicKey, comment string) ([]byte, error) {
+	if strings.ContainsAny(comment, "\r\n") {
+		return nil, ErrInvalidComment
+	}
+	line := ssh.MarshalAuthorizedKey(key)
+	if comment == "" {
+		return line, nil
+	}
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = append(line, ' ')
+	line = append(line, comment...)
+	return append(line, '\n'), nil
+}
@@ -0,0 +1,38 @@
+package sshkey
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestMarshalAuthorizedKey(t *testing.T) {
+	t.Parallel()
+
+	pub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	key, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	bare := ssh.MarshalAuthorizedKey(key)
+
+	line, err := MarshalAuthorizedKey(key, "")
+	require.NoError(t, err)
+	require.Equal(t, bare, line)
+
+	line, err = MarshalAuthorizedKey(key, "deploy@ci host")
+	require.NoError(t, err)
+	parsed, comment, _, rest, err := ssh.ParseAuthorizedKey(line)
+	require.NoError(t, err)
+	require.Equal(t, key.Marshal(), parsed.Marshal())
+	require.Equal(t, "deploy@ci host", comment)
+	require.Empty(t, rest)
+
+	// A newline in the comment would start a second, attacker-chosen line.
+	injected := "x\n" + string(bare)
+	for _, comment := range []string{injected, "x\r" + string(bare), "trailing\n", "\r"} {
+		_, err := MarshalAuthorizedKey(key, comment)
+		require.ErrorIs(t, err, ErrInvalidComment)
+	}
+}
@@ -0,0 +1,63 @@
+package kdf_test
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/binary"
+	"log"
+	"os"
+
+	"github.com/gtkit/encry/kdf"
+)
+
+func ExampleOneStep() {
+	out := log.New(os.Stdout, "", 0)
+	alice, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	bob, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	// 双方各自计算共享密钥 Z，再用相同的 FixedInfo 派生 AES-256 密钥。
+	info := kdf.FixedInfo([]byte("A256GCM"), []byte("alice"), []byte("bob"), binary.BigEndian.AppendUint32(nil, 256))
+	zA, err := alice.ECDH(bob.PublicKey())
+	if err != nil {
+		panic(err)
+	}
+	zB, err := bob.ECDH(alice.PublicKey())
+	if err != nil {
+		panic(err)
+	}
+	keyA, err := kdf.OneStep(zA, info, 32)
+	if err != nil {
+		panic(err)
+	}
+	keyB, err := kdf.OneStep(zB, info, 32)
+	if err != nil {
+		panic(err)
+	}
+	out.Println(len(keyA), bytes.Equal(keyA, keyB))
+	// Output:
+	// 32 true
+}
+
+func ExampleCounterMode() {
+	out := log.New(os.Stdout, "", 0)
+	master := bytes.Repeat([]byte{0x42}, 32)
+
+	encKey, err := kdf.CounterMode(master, []byte("encryption"), []byte("session-1"), 32)
+	if err != nil {
+		panic(err)
+	}
+	macKey, err := kdf.CounterMode(master, []byte("authentication"), []byte("session-1"), 32)
+	if err != nil {
+		panic(err)
+	}
+	out.Println(bytes.Equal(encKey, macKey))
+	// Output:
+	// false
+}
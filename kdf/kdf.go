@@ -0,0 +1,60 @@
+// Package kdf 提供 NIST 命名的密钥派生函数，供要求使用 NIST 算法的审计场景使用：
+//
+//   - SP 800-108r1 KDF in Counter Mode（CounterMode），PRF 为 HMAC-SHA256（默认）/224/384/512；
+//   - SP 800-56C r2 单步 KDF（OneStep 基于摘要，OneStepHMAC 基于 HMAC），
+//     用于从 ECDH 等密钥协商得到的共享密钥 Z 派生对称密钥；
+//   - ANSI X9.63 KDF（X963），ECIES 常用。
+//
+// 没有合规要求时，优先使用 hkdf（SP 800-56C 两步 KDF 的一种实例化）。
+package kdf
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+)
+
+var (
+	// ErrInvalidKeyLength 表示请求的派生长度非法（<=0 或超过算法上限）。
+	ErrInvalidKeyLength = errors.New("kdf: invalid key length")
+	// ErrUnsupportedHash 表示 WithHash 指定的摘要算法不受支持。
+	ErrUnsupportedHash = errors.New("kdf: unsupported hash")
+)
+
+// Option 用于定制 KDF 使用的摘要算法（Functional Options）。
+type Option func(*options)
+
+type options struct {
+	hash    crypto.Hash
+	newHash func() hash.Hash
+}
+
+// WithHash 指定摘要算法（CounterMode/OneStepHMAC 中为 HMAC 的摘要），
+// 支持 SHA-256（默认）、SHA-224、SHA-384、SHA-512。
+func WithHash(h crypto.Hash) Option {
+	return func(o *options) { o.hash = h }
+}
+
+func newOptions(opts []Option) (*options, error) {
+	o := &options{hash: crypto.SHA256}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	switch o.hash {
+	case crypto.SHA224:
+		o.newHash = sha256.New224
+	case crypto.SHA256:
+		o.newHash = sha256.New
+	case crypto.SHA384:
+		o.newHash = sha512.New384
+	case crypto.SHA512:
+		o.newHash = sha512.New
+	default:
+		return nil, ErrUnsupportedHash
+	}
+	return o, nil
+}
@@ -0,0 +1,299 @@
+package kdf_test
+
+import (
+	"bufio"
+	"crypto"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/kdf"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+// NIST 向量见 TestCounterModeCAVP 与 TestOneStepACVP；以下补充用例的期望值由 OpenSSL 3.0
+// （FIPS 140-3 验证过的 KBKDF/SSKDF 实现）独立计算，覆盖 CAVP/ACVP 文件之外的摘要与默认格式，
+// 命令见各用例注释。
+const (
+	testKey     = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+	testLabel   = "encry test"
+	testContext = "alice|bob"
+	testZ       = "c0ffee00112233445566778899aabbccddeeff00112233445566778899aabbcc"
+	testInfo    = "A256GCM"
+)
+
+// NIST CAVP SP 800-108 KDFCTR_gen.rsp（CAVS 14.4），裁剪为 HMAC_SHA256/384、
+// CTRLOCATION=BEFORE_FIXED、RLEN=32_BITS，每个 L 取前两组。
+func TestCounterModeCAVP(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.Open("testdata/KDFCTR_gen.rsp")
+	require.NoError(t, err)
+	defer f.Close()
+
+	prfs := map[string]crypto.Hash{"HMAC_SHA256": crypto.SHA256, "HMAC_SHA384": crypto.SHA384}
+	var (
+		hash   crypto.Hash
+		fields = map[string]string{}
+		n      int
+	)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<16)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "[PRF="):
+			var ok bool
+			hash, ok = prfs[strings.TrimSuffix(strings.TrimPrefix(line, "[PRF="), "]")]
+			require.True(t, ok, line)
+		case strings.HasPrefix(line, "["):
+			require.Contains(t, []string{"[CTRLOCATION=BEFORE_FIXED]", "[RLEN=32_BITS]"}, line)
+		case strings.HasPrefix(line, "COUNT="):
+			fields = map[string]string{"COUNT": strings.TrimPrefix(line, "COUNT=")}
+		case strings.Contains(line, " = "):
+			k, v, _ := strings.Cut(line, " = ")
+			fields[k] = v
+			if k != "KO" {
+				continue
+			}
+			bits, err := strconv.Atoi(fields["L"])
+			require.NoError(t, err)
+			want := mustHex(t, fields["KO"])
+			require.Len(t, want, bits/8)
+			got, err := kdf.CounterModeFixedInput(mustHex(t, fields["KI"]), mustHex(t, fields["FixedInputData"]), bits/8, kdf.WithHash(hash))
+			require.NoError(t, err)
+			require.Equal(t, want, got, "%v COUNT=%s", hash, fields["COUNT"])
+			n++
+		}
+	}
+	require.NoError(t, scanner.Err())
+	require.Equal(t, 16, n)
+}
+
+// openssl kdf -keylen N -kdfopt mac:HMAC -kdfopt digest:D -kdfopt hexkey:KEY
+// -kdfopt hexsalt:LABEL -kdfopt hexinfo:CONTEXT KBKDF
+func TestCounterModeVectors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		hash crypto.Hash
+		want string
+	}{
+		{"HMAC-SHA256", crypto.SHA256, "2772f91b5e15832f96935252c14d79083ae24b46685b52d64d099b6faf1291d325cbfd5d9ec6945b128b"},
+		{"HMAC-SHA384", crypto.SHA384, "e221695047bd96c16f5505f3df73f12bb75073f5926d491fb2fdcc338c049c0bf4f8a0ffe7345fc725c2041d7d0a3f9bc53280c2d86d9212377595b38464404e"},
+		{"HMAC-SHA512", crypto.SHA512, "de7ccd224299720825a333caeb486dde54c3d0c6d015cfcf93900ebef85d824bbfd4e61c9a08ead1ac46646d842736c69c77a74ab504a621765df784fcfb4ba94c6917791f1fa9d17314f99a37f6e7c69be2865a1790ea83e5955f32178f77bf828f22fb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			want := mustHex(t, tt.want)
+			got, err := kdf.CounterMode(mustHex(t, testKey), []byte(testLabel), []byte(testContext), len(want), kdf.WithHash(tt.hash))
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+// openssl ... -kdfopt hexinfo:LABEL||CONTEXT -kdfopt use-l:0 -kdfopt use-separator:0 KBKDF
+func TestCounterModeFixedInput(t *testing.T) {
+	t.Parallel()
+
+	want := mustHex(t, "c5dcf8f09d3bc2ad32dfdf0f1450756599be3ed73f838d5d920551a8f03147d31413f2c56671ef48")
+	got, err := kdf.CounterModeFixedInput(mustHex(t, testKey), []byte(testLabel+testContext), len(want))
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	// CounterMode 等价于按 Label || 0x00 || Context || [L]_32 编码的固定输入。
+	fixed := append([]byte(testLabel+"\x00"+testContext), 0, 0, 0x01, 0x00)
+	a, err := kdf.CounterModeFixedInput(mustHex(t, testKey), fixed, 32)
+	require.NoError(t, err)
+	b, err := kdf.CounterMode(mustHex(t, testKey), []byte(testLabel), []byte(testContext), 32)
+	require.NoError(t, err)
+	require.Equal(t, a, b)
+}
+
+func TestCounterModeBindsLength(t *testing.T) {
+	t.Parallel()
+
+	key := mustHex(t, testKey)
+	short, err := kdf.CounterMode(key, []byte(testLabel), nil, 16)
+	require.NoError(t, err)
+	long, err := kdf.CounterMode(key, []byte(testLabel), nil, 32)
+	require.NoError(t, err)
+	require.NotEqual(t, short, long[:16])
+}
+
+// RFC 7518 附录 C：ECDH-ES 的 Concat KDF 即 SP 800-56C 单步 KDF（SHA-256）。
+func TestOneStepRFC7518(t *testing.T) {
+	t.Parallel()
+
+	z := []byte{
+		158, 86, 217, 29, 129, 113, 53, 211, 114, 131, 66, 131, 191, 132,
+		38, 156, 251, 49, 110, 163, 218, 128, 106, 72, 246, 218, 167, 121,
+		140, 254, 144, 196,
+	}
+	info := kdf.FixedInfo([]byte("A128GCM"), []byte("Alice"), []byte("Bob"), binary.BigEndian.AppendUint32(nil, 128))
+	got, err := kdf.OneStep(z, info, 16)
+	require.NoError(t, err)
+	require.Equal(t, "VqqN6vgjbSBcIijNcacQGg", base64.RawURLEncoding.EncodeToString(got))
+}
+
+// NIST ACVP KDA-OneStep-Sp800-56Cr2 向量（经 OpenSSL evpkdf_ss.txt 的 "ACVP Server Tests" 节转录）：
+// 摘要版 SHA2-224，HMAC 版 HMAC-SHA224（salt 为 64 字节全零，即缺省 salt）。
+// fixedInfo 由 ACVP 的各字段拼接而成，末尾为 L = 1024 比特。
+func TestOneStepACVP(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		hmac bool
+		z    string
+		info string
+		want string
+	}{
+		{
+			name: "SHA2-224",
+			z:    "B88A5DBAB00483107C1839742A0E0EEE128EE83F715AE23E15C7CED18133754B095917F99C2EE421FE9EEE3B3E0F8D74F791B6EA930E2CD083F9E9952581AE6B537784B7820680C9797C4E9E2B6638FE5CF452309FC9C28D109AFF1CF75E9D4D3C1AA276",
+			info: "0EEA684AC156B3569C3C6B8316E0F3C339BE2C9458FFFAC5A5261082744805D24E12FC795D54D8109EE1101F313F56F5BF1AD8B58E103FC30269CAFBF1B830BBBBFF898DCF9DD81BCA9F01CE8D3B99848DF2FF1EA0AFDBD89FCB17366FA3AFA0B09E5BCAC4E3E8BF39796469E8DE8F1A9F3A9FA158E05A16CA4D70B75D12952F09EAAD1C421511F18FCA3830B9910047EEE4F3DB00000400",
+			want: "4F0F153EF1DC7F9B832A9403FD68BCEB4F32B608003EA429FF28D46235166C2D4E28DF8776DCDB34A984AA643A8D8E112CAD6674705B0D7B24CB15039F210DCEE13A8EDD52135B253CB56C3EA5B314651C1C40EDAFEBF7ED017CC8A24E232811ADD28592D26A07CC331807618316E6D21B860BC35F418F67AC17534F45FE8A3B",
+		},
+		{
+			name: "HMAC-SHA224",
+			hmac: true,
+			z:    "40B6E03711EBEBA14011ACE96CB056DEBAEB6E5E706F99435257C6A068E78C1369C5AD7FC42D3FCCA2EC9EAA",
+			info: "5D437C2F1035A4F1F751E59CF10650171EF5769FCFBE438DFBC5BD8EA724100076447AB804F91DFA680E592FE2621A45DAB4C6A77B678059FC29E572DE4424EB5459F53523002ED38AAB1D9DD96C3523D1907C5EFBAE93DFFE680F716498720110D2A3B9CE9B66DB2884C83E9BEB546754874C0CA1967AF000000400",
+			want: "428979EA52175DC833C04215AC6B4BA89BA4FCAA0E0FA3B4E2C0E264C5746F0A5C788F2907A2C2B90719E396B35A14C4B583C51B9911125D34100FADDC4D94C0D936263CC1EF0B0D526E3891FE1F67BCB94DEA2525B84A8E7949A4CA34F36AEEC55099BF0EC5DE24B86428F4E6E6E23FE9AA443E2BDCF25A77ECD22BF758D554",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			want := mustHex(t, tt.want)
+			require.Len(t, want, 1024/8)
+			var (
+				got []byte
+				err error
+			)
+			if tt.hmac {
+				got, err = kdf.OneStepHMAC(mustHex(t, tt.z), nil, mustHex(t, tt.info), len(want), kdf.WithHash(crypto.SHA224))
+				require.NoError(t, err)
+				require.Equal(t, want, got)
+				got, err = kdf.OneStepHMAC(mustHex(t, tt.z), make([]byte, 64), mustHex(t, tt.info), len(want), kdf.WithHash(crypto.SHA224))
+			} else {
+				got, err = kdf.OneStep(mustHex(t, tt.z), mustHex(t, tt.info), len(want), kdf.WithHash(crypto.SHA224))
+			}
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+// openssl kdf -keylen N -kdfopt digest:D [-kdfopt mac:HMAC [-kdfopt hexsalt:S]]
+// -kdfopt hexsecret:Z -kdfopt hexinfo:INFO SSKDF
+func TestOneStepVectors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		hmac bool
+		salt []byte
+		hash crypto.Hash
+		want string
+	}{
+		{"SHA-384", false, nil, crypto.SHA384, "fdf8e01a6e36c2b41569ea8b05e556ed898d86afadc0a666b4483b4d64d8b32ac04065ab58b04ebf8c574cd810775d51"},
+		{"SHA-512", false, nil, crypto.SHA512, "9270c871e31e3cd2bdfa7fe99e7d445cbed8719ce694fbe64a79e1079d6a0dda069b2563703370eebad78ab2427585af513a1eafa3262a597088e473acbee39a269ff1cfeaa4a9c2806385465eebcef5"},
+		{"HMAC-SHA256 salt", true, []byte(testLabel), crypto.SHA256, "6a268553d3aa8c1b3059cd9301ee04c531efe44cb529ff2b64328f7bcd261b5d50d405314cfc4ede4c351c28"},
+		{"HMAC-SHA256 默认 salt", true, nil, crypto.SHA256, "cf3b67c9158ff01ca540c2e766b28e3ea3f7eaffb7bb4921d4c273099b04ea0ee1e001a72eae2091b4967f74"},
+		{"HMAC-SHA384 默认 salt", true, nil, crypto.SHA384, "3f46038abbb8d1c9dea59b908d44c9f3cf8fa386142e3d722678cb815fce34af8284ced1f26adff7fcf9b3d55c6cb5d98762ff7536ef597767d1e273"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			want := mustHex(t, tt.want)
+			var (
+				got []byte
+				err error
+			)
+			if tt.hmac {
+				got, err = kdf.OneStepHMAC(mustHex(t, testZ), tt.salt, []byte(testInfo), len(want), kdf.WithHash(tt.hash))
+			} else {
+				got, err = kdf.OneStep(mustHex(t, testZ), []byte(testInfo), len(want), kdf.WithHash(tt.hash))
+			}
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+// 首个用例为 NIST CAVS ansx963_2001.rsp [SHA-256] COUNT = 0（SharedInfo 为空）；其余由
+// openssl kdf -keylen N -kdfopt digest:D -kdfopt hexsecret:Z -kdfopt hexinfo:INFO X963KDF 计算。
+func TestX963Vectors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		z          string
+		sharedInfo []byte
+		hash       crypto.Hash
+		want       string
+	}{
+		{"CAVS SHA-256", "96c05619d56c328ab95fe84b18264b08725b85e33fd34f08", nil, crypto.SHA256, "443024c3dae66b95e6f5670601558f71"},
+		{"SHA-256", testZ, []byte(testLabel), crypto.SHA256, "e7bdea3a0c5d6d290de10bc9f9e511413cb782ab52abbbfd1a6ad24547269dd0956119d3f7d11da2c7c6f9d1dd58a0db"},
+		{"SHA-384", testZ, []byte(testLabel), crypto.SHA384, "9062c96ef9d8f26e14b3f174d84d02853af248a46aa7af67ee2d7f8d3407aa1d302bdf44e2382de6bd3e8a8816ccace32ade354260aaa54adf5158e5796dd7bb"},
+		{"SHA-512", testZ, []byte(testLabel), crypto.SHA512, "4d543498a3fda63dd48a92bcb8a1558565bb28c9623ea244e1f0d76f20fd24a1ba29ceb00ee5f1da8e5aa659d999a562202c3481cd93fa9a0a625251c3dd42f214fd1b583a1741197e5421cc4b435d567b2fa83fc34f739338434d325e44c28645d6970e"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			want := mustHex(t, tt.want)
+			got, err := kdf.X963(mustHex(t, tt.z), tt.sharedInfo, len(want), kdf.WithHash(tt.hash))
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	key := mustHex(t, testKey)
+	for _, n := range []int{0, -1} {
+		_, err := kdf.CounterMode(key, nil, nil, n)
+		require.ErrorIs(t, err, kdf.ErrInvalidKeyLength)
+		_, err = kdf.CounterModeFixedInput(key, nil, n)
+		require.ErrorIs(t, err, kdf.ErrInvalidKeyLength)
+		_, err = kdf.OneStep(key, nil, n)
+		require.ErrorIs(t, err, kdf.ErrInvalidKeyLength)
+		_, err = kdf.OneStepHMAC(key, nil, nil, n)
+		require.ErrorIs(t, err, kdf.ErrInvalidKeyLength)
+		_, err = kdf.X963(key, nil, n)
+		require.ErrorIs(t, err, kdf.ErrInvalidKeyLength)
+	}
+
+	for _, h := range []crypto.Hash{crypto.MD5, crypto.SHA1, crypto.SHA3_256, 0} {
+		_, err := kdf.CounterMode(key, nil, nil, 32, kdf.WithHash(h))
+		require.ErrorIs(t, err, kdf.ErrUnsupportedHash)
+		_, err = kdf.OneStep(key, nil, 32, kdf.WithHash(h))
+		require.ErrorIs(t, err, kdf.ErrUnsupportedHash)
+		_, err = kdf.OneStepHMAC(key, nil, nil, 32, kdf.WithHash(h))
+		require.ErrorIs(t, err, kdf.ErrUnsupportedHash)
+		_, err = kdf.X963(key, nil, 32, kdf.WithHash(h))
+		require.ErrorIs(t, err, kdf.ErrUnsupportedHash)
+	}
+
+	// nil Option 被忽略。
+	_, err := kdf.OneStep(key, nil, 32, nil)
+	require.NoError(t, err)
+}
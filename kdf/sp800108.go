@@ -0,0 +1,50 @@
+package kdf
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"math"
+)
+
+// CounterMode 按 SP 800-108r1 §4.1 的 Counter Mode 派生 keyLen 字节密钥，
+// 使用标准推荐的固定输入格式：
+//
+//	K(i) = HMAC(key, [i]_32 || Label || 0x00 || Context || [L]_32)
+//
+// 其中 i 从 1 开始，L 为输出比特数。label 标识用途，context 放入通信双方身份、nonce 等。
+// 与 OpenSSL KBKDF（mode=COUNTER，默认 use-l/use-separator）输出一致。
+func CounterMode(key, label, context []byte, keyLen int, opts ...Option) ([]byte, error) {
+	if keyLen <= 0 || keyLen > math.MaxUint32/8 {
+		return nil, ErrInvalidKeyLength
+	}
+	fixed := make([]byte, 0, len(label)+1+len(context)+4)
+	fixed = append(fixed, label...)
+	fixed = append(fixed, 0x00)
+	fixed = append(fixed, context...)
+	fixed = binary.BigEndian.AppendUint32(fixed, uint32(keyLen*8)) // #nosec G115 -- keyLen is checked above.
+	return CounterModeFixedInput(key, fixed, keyLen, opts...)
+}
+
+// CounterModeFixedInput 与 CounterMode 相同，但固定输入数据由调用方自行编码：
+// K(i) = HMAC(key, [i]_32 || fixedInput)。用于对接已规定固定输入格式的协议或 CAVP 向量
+// （计数器 32 位、位于固定输入之前）。
+func CounterModeFixedInput(key, fixedInput []byte, keyLen int, opts ...Option) ([]byte, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if keyLen <= 0 || keyLen > math.MaxUint32/8 {
+		return nil, ErrInvalidKeyLength
+	}
+	mac := hmac.New(o.newHash, key)
+	out := make([]byte, 0, keyLen+mac.Size())
+	var counter [4]byte
+	for i := uint32(1); len(out) < keyLen; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		mac.Reset()
+		mac.Write(counter[:])
+		mac.Write(fixedInput)
+		out = mac.Sum(out)
+	}
+	return out[:keyLen], nil
+}
@@ -0,0 +1,65 @@
+package kdf
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"hash"
+)
+
+// OneStep 按 SP 800-56C r2 §4.1 选项 1（H = 摘要函数）派生 keyLen 字节密钥：
+//
+//	K(i) = H([i]_32 || Z || FixedInfo)
+//
+// z 为密钥协商得到的共享密钥（如 ECDH 输出），fixedInfo 绑定算法与双方身份，
+// 可用 FixedInfo 按 SP 800-56A 的拼接格式构造。默认 SHA-256，与 JWA ECDH-ES 的
+// Concat KDF（RFC 7518 §4.6.2）相同。
+func OneStep(z, fixedInfo []byte, keyLen int, opts ...Option) ([]byte, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return oneStep(o.newHash(), z, fixedInfo, keyLen)
+}
+
+// OneStepHMAC 按 SP 800-56C r2 §4.1 选项 2（H = HMAC(salt, ·)）派生 keyLen 字节密钥。
+// salt 为 nil 时按标准使用与摘要分组等长的全零串。
+func OneStepHMAC(z, salt, fixedInfo []byte, keyLen int, opts ...Option) ([]byte, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	if salt == nil {
+		salt = make([]byte, o.newHash().BlockSize())
+	}
+	return oneStep(hmac.New(o.newHash, salt), z, fixedInfo, keyLen)
+}
+
+func oneStep(h hash.Hash, z, fixedInfo []byte, keyLen int) ([]byte, error) {
+	// 计数器为 32 位：输出不超过 (2^32-1) 个摘要块。
+	if keyLen <= 0 || uint64(keyLen) > uint64(h.Size())*(1<<32-1) {
+		return nil, ErrInvalidKeyLength
+	}
+	out := make([]byte, 0, keyLen+h.Size())
+	var counter [4]byte
+	for i := uint32(1); len(out) < keyLen; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		h.Reset()
+		h.Write(counter[:])
+		h.Write(z)
+		h.Write(fixedInfo)
+		out = h.Sum(out)
+	}
+	return out[:keyLen], nil
+}
+
+// FixedInfo 按 SP 800-56A r3 §5.8.2.1.1 的拼接格式构造 FixedInfo：
+// 每个字段编码为 [len]_32 || data，依次为 AlgorithmID、PartyUInfo、PartyVInfo，
+// 最后附加 suppPubInfo（原样追加，通常为 [keyLen*8]_32）。
+func FixedInfo(algorithmID, partyUInfo, partyVInfo, suppPubInfo []byte) []byte {
+	out := make([]byte, 0, 12+len(algorithmID)+len(partyUInfo)+len(partyVInfo)+len(suppPubInfo))
+	for _, field := range [][]byte{algorithmID, partyUInfo, partyVInfo} {
+		out = binary.BigEndian.AppendUint32(out, uint32(len(field))) // #nosec G115 -- field lengths are far below 4 GiB in practice.
+		out = append(out, field...)
+	}
+	return append(out, suppPubInfo...)
+}
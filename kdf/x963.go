@@ -0,0 +1,33 @@
+package kdf
+
+import (
+	"encoding/binary"
+)
+
+// X963 按 ANSI X9.63（SEC 1 v2 §3.6.1）KDF 派生 keyLen 字节密钥：
+//
+//	K(i) = H(Z || [i]_32 || SharedInfo)
+//
+// 与 OneStep 的区别仅在于计数器位于 Z 之后。Apple Security 框架的
+// eciesEncryption*X963SHA256AESGCM 与多数 ECIES 实现使用该 KDF。
+func X963(z, sharedInfo []byte, keyLen int, opts ...Option) ([]byte, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	h := o.newHash()
+	if keyLen <= 0 || uint64(keyLen) > uint64(h.Size())*(1<<32-1) {
+		return nil, ErrInvalidKeyLength
+	}
+	out := make([]byte, 0, keyLen+h.Size())
+	var counter [4]byte
+	for i := uint32(1); len(out) < keyLen; i++ {
+		binary.BigEndian.PutUint32(counter[:], i)
+		h.Reset()
+		h.Write(z)
+		h.Write(counter[:])
+		h.Write(sharedInfo)
+		out = h.Sum(out)
+	}
+	return out[:keyLen], nil
+}
@@ -0,0 +1,153 @@
+package mldsa
+
+import (
+	"crypto/ed25519"
+	"crypto/mldsa"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/subtle"
+)
+
+// 复合签名参照 draft-ietf-lamps-pq-composite-sigs 的 id-MLDSA65-Ed25519-SHA512：
+//
+//	M'   = Prefix || Label || len(ctx) || ctx || SHA-512(M)
+//	签名 = ML-DSA-65.Sign(M', ctx=Label) || Ed25519.Sign(M')
+//
+// Label 同时作为 ML-DSA 的上下文，复合签名中拆出的 ML-DSA 签名无法当作普通 ML-DSA 签名使用；
+// Ed25519 签的是 M' 而非原消息，拆出的 Ed25519 签名同样无法单独复用。
+const (
+	compositePrefix = "CompositeAlgorithmSignatures2025"
+	compositeLabel  = "COMPSIG-MLDSA65-Ed25519-SHA512"
+)
+
+// 复合密钥与签名的字节长度：均为 ML-DSA-65 部分在前、Ed25519 部分在后的直接拼接。
+const (
+	CompositeSeedSize      = SeedSize + ed25519.SeedSize
+	CompositePublicKeySize = mldsa.MLDSA65PublicKeySize + ed25519.PublicKeySize
+	CompositeSignatureSize = mldsa.MLDSA65SignatureSize + ed25519.SignatureSize
+)
+
+// CompositePrivateKey 是 ML-DSA-65 + Ed25519 复合私钥，序列化为 64 字节
+// (ML-DSA 种子 || Ed25519 种子)。
+type CompositePrivateKey struct {
+	ml  *mldsa.PrivateKey
+	ed  ed25519.PrivateKey
+	pub *CompositePublicKey
+}
+
+// CompositePublicKey 是 ML-DSA-65 + Ed25519 复合公钥。
+type CompositePublicKey struct {
+	ml *mldsa.PublicKey
+	ed ed25519.PublicKey
+}
+
+// GenerateCompositeKey 随机生成一把复合私钥。
+func GenerateCompositeKey() (*CompositePrivateKey, error) {
+	seed := make([]byte, CompositeSeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	defer clear(seed)
+	return NewCompositePrivateKey(seed)
+}
+
+// NewCompositePrivateKey 由 64 字节种子 (ML-DSA 种子 || Ed25519 种子) 还原复合私钥。
+func NewCompositePrivateKey(seed []byte) (*CompositePrivateKey, error) {
+	if len(seed) != CompositeSeedSize {
+		return nil, ErrInvalidSeed
+	}
+	ml, err := mldsa.NewPrivateKey(mldsa.MLDSA65(), seed[:SeedSize])
+	if err != nil {
+		return nil, err
+	}
+	ed := ed25519.NewKeyFromSeed(seed[SeedSize:])
+	return &CompositePrivateKey{
+		ml:  ml,
+		ed:  ed,
+		pub: &CompositePublicKey{ml: ml.PublicKey(), ed: ed.Public().(ed25519.PublicKey)},
+	}, nil
+}
+
+// Bytes 返回 64 字节复合私钥种子，须保密。
+func (k *CompositePrivateKey) Bytes() []byte {
+	return append(k.ml.Bytes(), k.ed.Seed()...)
+}
+
+// PublicKey 返回对应的复合公钥。
+func (k *CompositePrivateKey) PublicKey() *CompositePublicKey {
+	return k.pub
+}
+
+// Equal 以常量时间比较两把复合私钥是否相同。
+func (k *CompositePrivateKey) Equal(other *CompositePrivateKey) bool {
+	return other != nil && subtle.ConstantTimeCompare(k.Bytes(), other.Bytes()) == 1
+}
+
+// NewCompositePublicKey 解析 CompositePublicKeySize 字节的复合公钥。
+func NewCompositePublicKey(b []byte) (*CompositePublicKey, error) {
+	if len(b) != CompositePublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	ml, err := mldsa.NewPublicKey(mldsa.MLDSA65(), b[:mldsa.MLDSA65PublicKeySize])
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	ed := append(ed25519.PublicKey(nil), b[mldsa.MLDSA65PublicKeySize:]...)
+	return &CompositePublicKey{ml: ml, ed: ed}, nil
+}
+
+// Bytes 返回复合公钥编码 (ML-DSA-65 公钥 || Ed25519 公钥)。
+func (k *CompositePublicKey) Bytes() []byte {
+	return append(k.ml.Bytes(), k.ed...)
+}
+
+// Equal 比较两把复合公钥是否相同。
+func (k *CompositePublicKey) Equal(other *CompositePublicKey) bool {
+	return other != nil && subtle.ConstantTimeCompare(k.Bytes(), other.Bytes()) == 1
+}
+
+// SignComposite 用复合私钥签名，context 不超过 255 字节（可为空）。
+// 签名为 CompositeSignatureSize 字节 (ML-DSA-65 签名 || Ed25519 签名)。
+func SignComposite(privateKey *CompositePrivateKey, msg []byte, context string) ([]byte, error) {
+	if privateKey == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	if len(context) > maxContextLen {
+		return nil, ErrInvalidContext
+	}
+	m := compositeMessage(msg, context)
+	mlSig, err := privateKey.ml.Sign(nil, m, &mldsa.Options{Context: compositeLabel})
+	if err != nil {
+		return nil, err
+	}
+	return append(mlSig, ed25519.Sign(privateKey.ed, m)...), nil
+}
+
+// VerifyComposite 验证 SignComposite 生成的签名，ML-DSA 与 Ed25519 两部分都有效才返回 true。
+func VerifyComposite(publicKey *CompositePublicKey, msg, signature []byte, context string) (bool, error) {
+	if publicKey == nil {
+		return false, ErrInvalidPublicKey
+	}
+	if len(context) > maxContextLen {
+		return false, ErrInvalidContext
+	}
+	if len(signature) != CompositeSignatureSize {
+		return false, nil
+	}
+	m := compositeMessage(msg, context)
+	mlSig, edSig := signature[:mldsa.MLDSA65SignatureSize], signature[mldsa.MLDSA65SignatureSize:]
+	mlOK := mldsa.Verify(publicKey.ml, m, mlSig, &mldsa.Options{Context: compositeLabel}) == nil
+	edOK := ed25519.Verify(publicKey.ed, m, edSig)
+	return mlOK && edOK, nil
+}
+
+// compositeMessage 构造两种算法共同签名的 M'。
+func compositeMessage(msg []byte, context string) []byte {
+	digest := sha512.Sum512(msg)
+	m := make([]byte, 0, len(compositePrefix)+len(compositeLabel)+1+len(context)+len(digest))
+	m = append(m, compositePrefix...)
+	m = append(m, compositeLabel...)
+	m = append(m, byte(len(context)))
+	m = append(m, context...)
+	return append(m, digest[:]...)
+}
@@ -0,0 +1,143 @@
+package mldsa_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	stdmldsa "crypto/mldsa"
+	"crypto/sha512"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/mldsa"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompositeSignVerify(t *testing.T) {
+	t.Parallel()
+	priv, err := mldsa.GenerateCompositeKey()
+	require.NoError(t, err)
+	pub := priv.PublicKey()
+	msg := []byte("bootloader.bin")
+
+	for _, context := range []string{"", "firmware", strings.Repeat("c", 255)} {
+		sig, err := mldsa.SignComposite(priv, msg, context)
+		require.NoError(t, err)
+		require.Len(t, sig, mldsa.CompositeSignatureSize)
+
+		ok, err := mldsa.VerifyComposite(pub, msg, sig, context)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = mldsa.VerifyComposite(pub, msg, sig, context+"x")
+		if len(context) == 255 {
+			require.ErrorIs(t, err, mldsa.ErrInvalidContext)
+		} else {
+			require.NoError(t, err)
+		}
+		require.False(t, ok)
+	}
+
+	_, err = mldsa.SignComposite(priv, msg, strings.Repeat("c", 256))
+	require.ErrorIs(t, err, mldsa.ErrInvalidContext)
+}
+
+// 复合签名的两部分分别按 draft-ietf-lamps-pq-composite-sigs 的 M' 独立验证。
+func TestCompositeConstruction(t *testing.T) {
+	t.Parallel()
+	seed := make([]byte, mldsa.CompositeSeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	priv, err := mldsa.NewCompositePrivateKey(seed)
+	require.NoError(t, err)
+	require.Equal(t, seed, priv.Bytes())
+
+	pubBytes := priv.PublicKey().Bytes()
+	require.Len(t, pubBytes, mldsa.CompositePublicKeySize)
+	mlPub, _, err := mldsa.NewKeyFromSeed(mldsa.MLDSA65(), seed[:mldsa.SeedSize])
+	require.NoError(t, err)
+	edPub := ed25519.NewKeyFromSeed(seed[mldsa.SeedSize:]).Public().(ed25519.PublicKey)
+	require.Equal(t, append(mlPub.Bytes(), edPub...), pubBytes)
+
+	msg, context := []byte("hello"), "ctx"
+	sig, err := mldsa.SignComposite(priv, msg, context)
+	require.NoError(t, err)
+
+	digest := sha512.Sum512(msg)
+	var m bytes.Buffer
+	m.WriteString("CompositeAlgorithmSignatures2025")
+	m.WriteString("COMPSIG-MLDSA65-Ed25519-SHA512")
+	m.WriteByte(byte(len(context)))
+	m.WriteString(context)
+	m.Write(digest[:])
+
+	mlSig, edSig := sig[:stdmldsa.MLDSA65SignatureSize], sig[stdmldsa.MLDSA65SignatureSize:]
+	require.NoError(t, stdmldsa.Verify(mlPub, m.Bytes(), mlSig, &stdmldsa.Options{Context: "COMPSIG-MLDSA65-Ed25519-SHA512"}))
+	require.True(t, ed25519.Verify(edPub, m.Bytes(), edSig))
+
+	// 拆出的分量签名不能当作对原消息的普通签名。
+	ok, err := mldsa.VerifyCtx(mlPub, msg, mlSig, context)
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.False(t, ed25519.Verify(edPub, msg, edSig))
+}
+
+func TestCompositeRejectsPartialForgery(t *testing.T) {
+	t.Parallel()
+	priv, err := mldsa.GenerateCompositeKey()
+	require.NoError(t, err)
+	other, err := mldsa.GenerateCompositeKey()
+	require.NoError(t, err)
+	msg := []byte("m")
+	sig, err := mldsa.SignComposite(priv, msg, "")
+	require.NoError(t, err)
+	otherSig, err := mldsa.SignComposite(other, msg, "")
+	require.NoError(t, err)
+
+	split := stdmldsa.MLDSA65SignatureSize
+	tests := map[string][]byte{
+		"ml-dsa part from other key":  append(append([]byte(nil), otherSig[:split]...), sig[split:]...),
+		"ed25519 part from other key": append(append([]byte(nil), sig[:split]...), otherSig[split:]...),
+		"flipped ml-dsa byte":         flipByte(sig, 10),
+		"flipped ed25519 byte":        flipByte(sig, len(sig)-1),
+		"short":                       sig[:len(sig)-1],
+		"empty":                       nil,
+	}
+	for name, bad := range tests {
+		ok, err := mldsa.VerifyComposite(priv.PublicKey(), msg, bad, "")
+		require.NoError(t, err, name)
+		require.False(t, ok, name)
+	}
+}
+
+func flipByte(b []byte, i int) []byte {
+	out := append([]byte(nil), b...)
+	out[i] ^= 0x01
+	return out
+}
+
+func TestCompositeKeys(t *testing.T) {
+	t.Parallel()
+	priv, err := mldsa.GenerateCompositeKey()
+	require.NoError(t, err)
+	again, err := mldsa.NewCompositePrivateKey(priv.Bytes())
+	require.NoError(t, err)
+	require.True(t, priv.Equal(again))
+	require.True(t, priv.PublicKey().Equal(again.PublicKey()))
+	require.False(t, priv.Equal(nil))
+
+	pub, err := mldsa.NewCompositePublicKey(priv.PublicKey().Bytes())
+	require.NoError(t, err)
+	require.True(t, pub.Equal(priv.PublicKey()))
+	require.False(t, pub.Equal(nil))
+
+	_, err = mldsa.NewCompositePrivateKey(make([]byte, mldsa.SeedSize))
+	require.ErrorIs(t, err, mldsa.ErrInvalidSeed)
+	_, err = mldsa.NewCompositePublicKey(make([]byte, 10))
+	require.ErrorIs(t, err, mldsa.ErrInvalidPublicKey)
+
+	_, err = mldsa.SignComposite(nil, []byte("m"), "")
+	require.ErrorIs(t, err, mldsa.ErrInvalidPrivateKey)
+	_, err = mldsa.VerifyComposite(nil, []byte("m"), nil, "")
+	require.ErrorIs(t, err, mldsa.ErrInvalidPublicKey)
+}
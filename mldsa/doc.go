@@ -0,0 +1,11 @@
+// Package mldsa 提供后量子签名 ML-DSA（NIST FIPS 204，基于 crypto/mldsa），API 形状与 ed 包一致：
+// GenerateKeyPair → SignBytes/VerifyBytes，密钥可编码为 PKCS#8/PKIX PEM 并读写文件。
+//
+// 默认参数集为 ML-DSA-65（约 AES-192 强度，公钥 1952 字节、签名 3309 字节），
+// 也可用 GenerateKeyPairWithParameters 选择 ML-DSA-44 或 ML-DSA-87。私钥序列化形式为 32 字节种子。
+// SignCtx/VerifyCtx 使用 FIPS 204 的上下文字符串做域分离，同一密钥用于多种用途（如固件与配置）时，
+// 一种用途的签名无法被另一种用途接受。签名默认带随机化（hedged），同一消息每次签名结果不同。
+//
+// 过渡期可使用复合签名 CompositePrivateKey（ML-DSA-65 + Ed25519）：两种签名都通过才算有效，
+// 任一算法被攻破时仍受另一算法保护，见 SignComposite。
+package mldsa
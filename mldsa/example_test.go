@@ -0,0 +1,58 @@
+package mldsa_test
+
+import (
+	"fmt"
+
+	"github.com/gtkit/encry/mldsa"
+)
+
+func ExampleGenerateKeyPair() {
+	publicKey, privateKey, err := mldsa.GenerateKeyPair()
+	if err != nil {
+		panic(err)
+	}
+	signature, err := mldsa.SignBase64(privateKey, []byte("hello-mldsa"))
+	if err != nil {
+		panic(err)
+	}
+	ok, err := mldsa.VerifyBase64(publicKey, []byte("hello-mldsa"), signature)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(ok)
+	// Output: true
+}
+
+func ExampleSignCtx() {
+	publicKey, privateKey, err := mldsa.GenerateKeyPair()
+	if err != nil {
+		panic(err)
+	}
+	image := []byte("firmware image")
+	signature, err := mldsa.SignCtx(privateKey, image, "firmware")
+	if err != nil {
+		panic(err)
+	}
+	// 同一签名在其他用途（上下文）下无效。
+	asFirmware, _ := mldsa.VerifyCtx(publicKey, image, signature, "firmware")
+	asConfig, _ := mldsa.VerifyCtx(publicKey, image, signature, "config")
+	fmt.Println(asFirmware, asConfig)
+	// Output: true false
+}
+
+func ExampleSignComposite() {
+	privateKey, err := mldsa.GenerateCompositeKey()
+	if err != nil {
+		panic(err)
+	}
+	signature, err := mldsa.SignComposite(privateKey, []byte("firmware image"), "firmware")
+	if err != nil {
+		panic(err)
+	}
+	ok, err := mldsa.VerifyComposite(privateKey.PublicKey(), []byte("firmware image"), signature, "firmware")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(ok, len(signature))
+	// Output: true 3373
+}
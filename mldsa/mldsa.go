@@ -0,0 +1,164 @@
+package mldsa
+
+import (
+	"crypto/mldsa"
+	"encoding/base64"
+	"errors"
+)
+
+type (
+	// Parameters 是 FIPS 204 参数集，即 crypto/mldsa.Parameters。
+	Parameters = mldsa.Parameters
+	// PrivateKey 是 ML-DSA 私钥，即 *crypto/mldsa.PrivateKey，实现 crypto.Signer。
+	PrivateKey = mldsa.PrivateKey
+	// PublicKey 是 ML-DSA 公钥，即 *crypto/mldsa.PublicKey。
+	PublicKey = mldsa.PublicKey
+)
+
+// SeedSize 是私钥种子的字节长度，三种参数集相同。
+const SeedSize = mldsa.PrivateKeySize
+
+// maxContextLen 为 FIPS 204 规定的上下文字符串最大长度。
+const maxContextLen = 255
+
+var (
+	// ErrInvalidPrivateKey 表示私钥为空或 PKCS#8 私钥非法。
+	ErrInvalidPrivateKey = errors.New("mldsa: invalid private key")
+	// ErrInvalidPublicKey 表示公钥为空、长度非法或 PKIX 公钥非法。
+	ErrInvalidPublicKey = errors.New("mldsa: invalid public key")
+	// ErrInvalidSeed 表示私钥种子长度不是 SeedSize。
+	ErrInvalidSeed = errors.New("mldsa: invalid seed")
+	// ErrInvalidContext 表示上下文字符串超过 255 字节。
+	ErrInvalidContext = errors.New("mldsa: invalid context")
+)
+
+// MLDSA44 返回 ML-DSA-44 参数集（约 AES-128 强度）。
+func MLDSA44() Parameters { return mldsa.MLDSA44() }
+
+// MLDSA65 返回 ML-DSA-65 参数集（约 AES-192 强度），为本包默认参数集。
+func MLDSA65() Parameters { return mldsa.MLDSA65() }
+
+// MLDSA87 返回 ML-DSA-87 参数集（约 AES-256 强度，满足 CNSA 2.0）。
+func MLDSA87() Parameters { return mldsa.MLDSA87() }
+
+// GenerateKeyPair 生成一对 ML-DSA-65 密钥。
+func GenerateKeyPair() (*PublicKey, *PrivateKey, error) {
+	return GenerateKeyPairWithParameters(MLDSA65())
+}
+
+// GenerateKeyPairWithParameters 按参数集生成一对密钥。
+func GenerateKeyPairWithParameters(params Parameters) (*PublicKey, *PrivateKey, error) {
+	privateKey, err := mldsa.GenerateKey(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey.PublicKey(), privateKey, nil
+}
+
+// NewKeyFromSeed 由 32 字节种子确定性派生密钥对（FIPS 204 ML-DSA.KeyGen_internal）。
+func NewKeyFromSeed(params Parameters, seed []byte) (*PublicKey, *PrivateKey, error) {
+	if len(seed) != SeedSize {
+		return nil, nil, ErrInvalidSeed
+	}
+	privateKey, err := mldsa.NewPrivateKey(params, seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	return privateKey.PublicKey(), privateKey, nil
+}
+
+// NewPublicKey 解析原始公钥编码（如固件中内嵌的公钥），长度须与参数集一致。
+func NewPublicKey(params Parameters, b []byte) (*PublicKey, error) {
+	publicKey, err := mldsa.NewPublicKey(params, b)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return publicKey, nil
+}
+
+// SignBytes 使用 ML-DSA 私钥签名字节数据（空上下文）。
+func SignBytes(privateKey *PrivateKey, msg []byte) ([]byte, error) {
+	return SignCtx(privateKey, msg, "")
+}
+
+// SignBase64 使用 ML-DSA 私钥签名并返回 Base64。
+func SignBase64(privateKey *PrivateKey, msg []byte) (string, error) {
+	signature, err := SignBytes(privateKey, msg)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// VerifyBytes 使用 ML-DSA 公钥验证签名（空上下文），返回 (是否有效, 操作性错误)。
+// 公钥为空时返回 (false, ErrInvalidPublicKey)；签名无效（含长度非法）只返回 false。
+func VerifyBytes(publicKey *PublicKey, msg, signature []byte) (bool, error) {
+	return VerifyCtx(publicKey, msg, signature, "")
+}
+
+// VerifyBase64 使用 ML-DSA 公钥验证 Base64 编码签名，返回 (是否有效, 操作性错误)。
+func VerifyBase64(publicKey *PublicKey, msg []byte, signature string) (bool, error) {
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, err
+	}
+	return VerifyBytes(publicKey, msg, raw)
+}
+
+// SignCtx 使用上下文字符串签名，context 不超过 255 字节；空 context 与 SignBytes 相同。
+func SignCtx(privateKey *PrivateKey, msg []byte, context string) ([]byte, error) {
+	if privateKey == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	if len(context) > maxContextLen {
+		return nil, ErrInvalidContext
+	}
+	return privateKey.Sign(nil, msg, &mldsa.Options{Context: context})
+}
+
+// VerifyCtx 验证 SignCtx 生成的签名，context 必须与签名时一致，返回 (是否有效, 操作性错误)。
+func VerifyCtx(publicKey *PublicKey, msg, signature []byte, context string) (bool, error) {
+	if publicKey == nil {
+		return false, ErrInvalidPublicKey
+	}
+	if len(context) > maxContextLen {
+		return false, ErrInvalidContext
+	}
+	return mldsa.Verify(publicKey, msg, signature, &mldsa.Options{Context: context}) == nil, nil
+}
+
+// SignFile 使用 PEM 私钥文件签名。
+func SignFile(msg []byte, privatePath string) ([]byte, error) {
+	privateKey, err := ReadPrivateKey(privatePath)
+	if err != nil {
+		return nil, err
+	}
+	return SignBytes(privateKey, msg)
+}
+
+// SignFileBase64 使用 PEM 私钥文件签名并返回 Base64。
+func SignFileBase64(msg []byte, privatePath string) (string, error) {
+	privateKey, err := ReadPrivateKey(privatePath)
+	if err != nil {
+		return "", err
+	}
+	return SignBase64(privateKey, msg)
+}
+
+// VerifyFile 使用 PEM 公钥文件验证签名。
+func VerifyFile(msg []byte, publicPath string, signature []byte) (bool, error) {
+	publicKey, err := ReadPublicKey(publicPath)
+	if err != nil {
+		return false, err
+	}
+	return VerifyBytes(publicKey, msg, signature)
+}
+
+// VerifyFileBase64 使用 PEM 公钥文件验证 Base64 编码签名。
+func VerifyFileBase64(msg []byte, publicPath, signature string) (bool, error) {
+	publicKey, err := ReadPublicKey(publicPath)
+	if err != nil {
+		return false, err
+	}
+	return VerifyBase64(publicKey, msg, signature)
+}
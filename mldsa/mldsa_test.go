@@ -0,0 +1,183 @@
+package mldsa_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/mldsa"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignVerifyParameters(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		params  mldsa.Parameters
+		pubSize int
+		sigSize int
+	}{
+		{mldsa.MLDSA44(), 1312, 2420},
+		{mldsa.MLDSA65(), 1952, 3309},
+		{mldsa.MLDSA87(), 2592, 4627},
+	}
+	for _, tt := range tests {
+		t.Run(tt.params.String(), func(t *testing.T) {
+			t.Parallel()
+			pub, priv, err := mldsa.GenerateKeyPairWithParameters(tt.params)
+			require.NoError(t, err)
+			require.Len(t, pub.Bytes(), tt.pubSize)
+			require.Len(t, priv.Bytes(), mldsa.SeedSize)
+
+			msg := []byte("firmware image v1.4.2")
+			sig, err := mldsa.SignBytes(priv, msg)
+			require.NoError(t, err)
+			require.Len(t, sig, tt.sigSize)
+
+			ok, err := mldsa.VerifyBytes(pub, msg, sig)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			ok, err = mldsa.VerifyBytes(pub, []byte("firmware image v1.4.3"), sig)
+			require.NoError(t, err)
+			require.False(t, ok)
+
+			// 签名带随机化：同一消息两次签名不同，但都有效。
+			again, err := mldsa.SignBytes(priv, msg)
+			require.NoError(t, err)
+			require.NotEqual(t, sig, again)
+		})
+	}
+}
+
+func TestGenerateKeyPairDefault(t *testing.T) {
+	t.Parallel()
+	pub, priv, err := mldsa.GenerateKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, mldsa.MLDSA65(), pub.Parameters())
+	require.True(t, pub.Equal(priv.Public()))
+}
+
+func TestNewKeyFromSeed(t *testing.T) {
+	t.Parallel()
+	seed := bytes.Repeat([]byte{7}, mldsa.SeedSize)
+	pub1, priv1, err := mldsa.NewKeyFromSeed(mldsa.MLDSA65(), seed)
+	require.NoError(t, err)
+	pub2, priv2, err := mldsa.NewKeyFromSeed(mldsa.MLDSA65(), seed)
+	require.NoError(t, err)
+	require.True(t, pub1.Equal(pub2))
+	require.True(t, priv1.Equal(priv2))
+
+	pub3, _, err := mldsa.NewKeyFromSeed(mldsa.MLDSA87(), seed)
+	require.NoError(t, err)
+	require.False(t, pub1.Equal(pub3))
+
+	for _, n := range []int{0, mldsa.SeedSize - 1, mldsa.SeedSize + 1} {
+		_, _, err := mldsa.NewKeyFromSeed(mldsa.MLDSA65(), make([]byte, n))
+		require.ErrorIs(t, err, mldsa.ErrInvalidSeed)
+	}
+}
+
+func TestNewPublicKey(t *testing.T) {
+	t.Parallel()
+	pub, _, err := mldsa.GenerateKeyPair()
+	require.NoError(t, err)
+	got, err := mldsa.NewPublicKey(mldsa.MLDSA65(), pub.Bytes())
+	require.NoError(t, err)
+	require.True(t, pub.Equal(got))
+
+	_, err = mldsa.NewPublicKey(mldsa.MLDSA44(), pub.Bytes())
+	require.ErrorIs(t, err, mldsa.ErrInvalidPublicKey)
+	_, err = mldsa.NewPublicKey(mldsa.MLDSA65(), pub.Bytes()[1:])
+	require.ErrorIs(t, err, mldsa.ErrInvalidPublicKey)
+}
+
+func TestSignCtx(t *testing.T) {
+	t.Parallel()
+	pub, priv, err := mldsa.GenerateKeyPair()
+	require.NoError(t, err)
+	msg := []byte("payload")
+
+	sig, err := mldsa.SignCtx(priv, msg, "firmware")
+	require.NoError(t, err)
+	ok, err := mldsa.VerifyCtx(pub, msg, sig, "firmware")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// 不同用途的签名互不接受。
+	for _, context := range []string{"config", ""} {
+		ok, err = mldsa.VerifyCtx(pub, msg, sig, context)
+		require.NoError(t, err)
+		require.False(t, ok, context)
+	}
+	ok, err = mldsa.VerifyBytes(pub, msg, sig)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	// 空上下文等同于 SignBytes/VerifyBytes。
+	sig, err = mldsa.SignCtx(priv, msg, "")
+	require.NoError(t, err)
+	ok, err = mldsa.VerifyBytes(pub, msg, sig)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	longest := strings.Repeat("c", 255)
+	sig, err = mldsa.SignCtx(priv, msg, longest)
+	require.NoError(t, err)
+	ok, err = mldsa.VerifyCtx(pub, msg, sig, longest)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = mldsa.SignCtx(priv, msg, longest+"c")
+	require.ErrorIs(t, err, mldsa.ErrInvalidContext)
+	_, err = mldsa.VerifyCtx(pub, msg, sig, longest+"c")
+	require.ErrorIs(t, err, mldsa.ErrInvalidContext)
+}
+
+func TestInvalidInputs(t *testing.T) {
+	t.Parallel()
+	pub, priv, err := mldsa.GenerateKeyPair()
+	require.NoError(t, err)
+
+	_, err = mldsa.SignBytes(nil, []byte("x"))
+	require.ErrorIs(t, err, mldsa.ErrInvalidPrivateKey)
+	_, err = mldsa.VerifyBytes(nil, []byte("x"), nil)
+	require.ErrorIs(t, err, mldsa.ErrInvalidPublicKey)
+
+	sig, err := mldsa.SignBytes(priv, []byte("x"))
+	require.NoError(t, err)
+	for _, bad := range [][]byte{nil, sig[:len(sig)-1], append(sig, 0)} {
+		ok, err := mldsa.VerifyBytes(pub, []byte("x"), bad)
+		require.NoError(t, err)
+		require.False(t, ok)
+	}
+
+	_, err = mldsa.VerifyBase64(pub, []byte("x"), "%%%")
+	require.Error(t, err)
+}
+
+func TestBase64AndFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "keys", "private.pem")
+	publicPath := filepath.Join(dir, "keys", "public.pem")
+	require.NoError(t, mldsa.WriteKeyPair(privatePath, publicPath))
+
+	msg := []byte("release.tar.gz")
+	sig, err := mldsa.SignFile(msg, privatePath)
+	require.NoError(t, err)
+	ok, err := mldsa.VerifyFile(msg, publicPath, sig)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	sigB64, err := mldsa.SignFileBase64(msg, privatePath)
+	require.NoError(t, err)
+	ok, err = mldsa.VerifyFileBase64(msg, publicPath, sigB64)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = mldsa.SignFile(msg, filepath.Join(dir, "missing.pem"))
+	require.Error(t, err)
+	_, err = mldsa.VerifyFile(msg, filepath.Join(dir, "missing.pem"), sig)
+	require.Error(t, err)
+}
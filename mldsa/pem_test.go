@@ -0,0 +1,115 @@
+package mldsa_test
+
+import (
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gtkit/encry/ed"
+	"github.com/gtkit/encry/mldsa"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPEMRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, params := range []mldsa.Parameters{mldsa.MLDSA44(), mldsa.MLDSA65(), mldsa.MLDSA87()} {
+		t.Run(params.String(), func(t *testing.T) {
+			t.Parallel()
+			pub, priv, err := mldsa.GenerateKeyPairWithParameters(params)
+			require.NoError(t, err)
+
+			privatePEM, err := mldsa.MarshalPrivateKeyPEM(priv)
+			require.NoError(t, err)
+			gotPriv, err := mldsa.ParsePrivateKeyPEM(privatePEM)
+			require.NoError(t, err)
+			require.True(t, priv.Equal(gotPriv))
+
+			publicPEM, err := mldsa.MarshalPublicKeyPEM(pub)
+			require.NoError(t, err)
+			gotPub, err := mldsa.ParsePublicKeyPEM(publicPEM)
+			require.NoError(t, err)
+			require.True(t, pub.Equal(gotPub))
+			require.Equal(t, params, gotPub.Parameters())
+		})
+	}
+}
+
+// RFC 9881：PKCS#8 私钥为 seed 形式 [0] IMPLICIT OCTET STRING，AlgorithmIdentifier 不带参数。
+func TestPEMEncoding(t *testing.T) {
+	t.Parallel()
+	_, priv, err := mldsa.NewKeyFromSeed(mldsa.MLDSA65(), make([]byte, mldsa.SeedSize))
+	require.NoError(t, err)
+	privatePEM, err := mldsa.MarshalPrivateKeyPEM(priv)
+	require.NoError(t, err)
+	block, _ := pem.Decode(privatePEM)
+	require.Equal(t, "PRIVATE KEY", block.Type)
+	// SEQUENCE { INTEGER 0, SEQUENCE { OID 2.16.840.1.101.3.4.3.18 }, OCTET STRING { [0] 32 字节 } }
+	prefix := []byte{
+		0x30, 0x34, 0x02, 0x01, 0x00, 0x30, 0x0b, 0x06, 0x09,
+		0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x03, 0x12,
+		0x04, 0x22, 0x80, 0x20,
+	}
+	require.Equal(t, prefix, block.Bytes[:len(prefix)])
+	require.Len(t, block.Bytes, len(prefix)+mldsa.SeedSize)
+}
+
+func TestPEMErrors(t *testing.T) {
+	t.Parallel()
+	_, err := mldsa.MarshalPrivateKeyPEM(nil)
+	require.ErrorIs(t, err, mldsa.ErrInvalidPrivateKey)
+	_, err = mldsa.MarshalPublicKeyPEM(nil)
+	require.ErrorIs(t, err, mldsa.ErrInvalidPublicKey)
+
+	_, err = mldsa.ParsePrivateKeyPEM([]byte("not pem"))
+	require.ErrorIs(t, err, mldsa.ErrInvalidPrivateKey)
+	_, err = mldsa.ParsePublicKeyPEM([]byte("not pem"))
+	require.ErrorIs(t, err, mldsa.ErrInvalidPublicKey)
+
+	pub, priv, err := mldsa.GenerateKeyPair()
+	require.NoError(t, err)
+	publicPEM, err := mldsa.MarshalPublicKeyPEM(pub)
+	require.NoError(t, err)
+	_, err = mldsa.ParsePrivateKeyPEM(publicPEM)
+	require.ErrorIs(t, err, mldsa.ErrInvalidPrivateKey)
+	privatePEM, err := mldsa.MarshalPrivateKeyPEM(priv)
+	require.NoError(t, err)
+	_, err = mldsa.ParsePublicKeyPEM(privatePEM)
+	require.ErrorIs(t, err, mldsa.ErrInvalidPublicKey)
+
+	// 其他算法的 PKCS#8/PKIX 密钥。
+	edPrivPEM, edPubPEM, err := ed.GenerateKeyPEM()
+	require.NoError(t, err)
+	_, err = mldsa.ParsePrivateKeyPEM(edPrivPEM)
+	require.ErrorIs(t, err, mldsa.ErrInvalidPrivateKey)
+	_, err = mldsa.ParsePublicKeyPEM(edPubPEM)
+	require.ErrorIs(t, err, mldsa.ErrInvalidPublicKey)
+
+	// 截断的 DER。
+	block, _ := pem.Decode(privatePEM)
+	truncated := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: block.Bytes[:len(block.Bytes)-1]})
+	_, err = mldsa.ParsePrivateKeyPEM(truncated)
+	require.Error(t, err)
+}
+
+func TestReadKeys(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	privatePEM, publicPEM, err := mldsa.GenerateKeyPEM()
+	require.NoError(t, err)
+	privatePath := filepath.Join(dir, "private.pem")
+	publicPath := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(privatePath, privatePEM, 0o600))
+	require.NoError(t, os.WriteFile(publicPath, publicPEM, 0o644))
+
+	priv, err := mldsa.ReadPrivateKey(privatePath)
+	require.NoError(t, err)
+	pub, err := mldsa.ReadPublicKey(publicPath)
+	require.NoError(t, err)
+	require.True(t, pub.Equal(priv.Public()))
+
+	_, err = mldsa.ReadPrivateKey(filepath.Join(dir, "missing.pem"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+	_, err = mldsa.ReadPublicKey(filepath.Join(dir, "missing.pem"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
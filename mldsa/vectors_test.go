@@ -0,0 +1,217 @@
+package mldsa_test
+
+import (
+	stdmldsa "crypto/mldsa"
+	"crypto/sha3"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/gtkit/encry/mldsa"
+	"github.com/stretchr/testify/require"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func loadJSON(t *testing.T, path string, v any) {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, v))
+}
+
+func paramsByName(t *testing.T, name string) mldsa.Parameters {
+	t.Helper()
+	for _, p := range []mldsa.Parameters{mldsa.MLDSA44(), mldsa.MLDSA65(), mldsa.MLDSA87()} {
+		if p.String() == name {
+			return p
+		}
+	}
+	t.Fatalf("unknown parameter set %s", name)
+	return mldsa.Parameters{}
+}
+
+// NIST ACVP ML-DSA-keyGen-FIPS204 向量（每个参数集取前 3 组）：种子 → 公钥。
+func TestKeyGenACVP(t *testing.T) {
+	t.Parallel()
+	var vectors []struct {
+		TCID         int    `json:"tcId"`
+		ParameterSet string `json:"parameterSet"`
+		Seed         string `json:"seed"`
+		PK           string `json:"pk"`
+	}
+	loadJSON(t, "testdata/acvp_keygen.json", &vectors)
+	require.Len(t, vectors, 9)
+	for _, v := range vectors {
+		params := paramsByName(t, v.ParameterSet)
+		pub, priv, err := mldsa.NewKeyFromSeed(params, mustHex(t, v.Seed))
+		require.NoError(t, err, "tcId %d", v.TCID)
+		require.Equal(t, mustHex(t, v.PK), pub.Bytes(), "tcId %d", v.TCID)
+		require.Equal(t, mustHex(t, v.Seed), priv.Bytes())
+		require.Equal(t, params, pub.Parameters())
+	}
+}
+
+// NIST ACVP ML-DSA-sigGen-FIPS204 向量（ML-DSA-65，确定性，外部接口，带上下文），
+// 取自 OpenSSL 由 ACVP-Server internalProjection.json 转换的 evppkey_ml_dsa_siggen.txt。
+// ACVP 给出的是展开形式私钥，而 crypto/mldsa 只接受 32 字节种子，无法用它直接签名；
+// pk 由展开私钥导出，测试先核对 sk 中的 ρ 与 tr = SHAKE256(pk, 64) 与 pk 一致，
+// 再要求期望签名经 VerifyCtx 通过、换上下文或消息后失败。由种子出发的确定性签名见 TestSignWycheproof。
+func TestSigGenACVP(t *testing.T) {
+	t.Parallel()
+	var vectors []struct {
+		ID           string `json:"id"`
+		ParameterSet string `json:"parameterSet"`
+		SK           string `json:"sk"`
+		PK           string `json:"pk"`
+		Message      string `json:"message"`
+		Context      string `json:"context"`
+		Signature    string `json:"signature"`
+	}
+	loadJSON(t, "testdata/acvp_siggen.json", &vectors)
+	require.Len(t, vectors, 5)
+	for _, v := range vectors {
+		sk, pkBytes := mustHex(t, v.SK), mustHex(t, v.PK)
+		require.Equal(t, pkBytes[:32], sk[:32], "%s: rho", v.ID)
+		require.Equal(t, sha3.SumSHAKE256(pkBytes, 64), sk[64:128], "%s: tr", v.ID)
+
+		pub, err := mldsa.NewPublicKey(paramsByName(t, v.ParameterSet), pkBytes)
+		require.NoError(t, err, v.ID)
+		msg, context, sig := mustHex(t, v.Message), string(mustHex(t, v.Context)), mustHex(t, v.Signature)
+		ok, err := mldsa.VerifyCtx(pub, msg, sig, context)
+		require.NoError(t, err, v.ID)
+		require.True(t, ok, v.ID)
+
+		ok, err = mldsa.VerifyCtx(pub, append(msg, 0), sig, context)
+		require.NoError(t, err)
+		require.False(t, ok, v.ID)
+		if context != "" {
+			ok, err = mldsa.VerifyBytes(pub, msg, sig)
+			require.NoError(t, err)
+			require.False(t, ok, v.ID)
+		}
+	}
+}
+
+// NIST ACVP ML-DSA-sigVer-FIPS204 向量（ML-DSA-65，外部接口，带上下文），
+// 取自 OpenSSL 的 evppkey_ml_dsa_sigver.txt：有效签名与篡改 z、hint、承诺及消息的签名。
+func TestSigVerACVP(t *testing.T) {
+	t.Parallel()
+	var vectors []struct {
+		ID           string `json:"id"`
+		ParameterSet string `json:"parameterSet"`
+		PK           string `json:"pk"`
+		Message      string `json:"message"`
+		Context      string `json:"context"`
+		Signature    string `json:"signature"`
+		TestPassed   bool   `json:"testPassed"`
+		Reason       string `json:"reason"`
+	}
+	loadJSON(t, "testdata/acvp_sigver.json", &vectors)
+	require.Len(t, vectors, 7)
+	for _, v := range vectors {
+		pub, err := mldsa.NewPublicKey(paramsByName(t, v.ParameterSet), mustHex(t, v.PK))
+		require.NoError(t, err, v.ID)
+		ok, err := mldsa.VerifyCtx(pub, mustHex(t, v.Message), mustHex(t, v.Signature), string(mustHex(t, v.Context)))
+		require.NoError(t, err, v.ID)
+		require.Equal(t, v.TestPassed, ok, "%s: %s", v.ID, v.Reason)
+	}
+}
+
+type wycheproofTest struct {
+	TCID    int     `json:"tcId"`
+	Comment string  `json:"comment"`
+	Msg     string  `json:"msg"`
+	Ctx     *string `json:"ctx"`
+	Sig     string  `json:"sig"`
+	Result  string  `json:"result"`
+}
+
+func (w wycheproofTest) context(t *testing.T) string {
+	if w.Ctx == nil {
+		return ""
+	}
+	return string(mustHex(t, *w.Ctx))
+}
+
+// Wycheproof ML-DSA-65 签名向量（外部接口，带上下文）：确定性签名须逐字节一致，
+// 超长上下文须被拒绝。
+func TestSignWycheproof(t *testing.T) {
+	t.Parallel()
+	var file struct {
+		TestGroups []struct {
+			PrivateSeed string           `json:"privateSeed"`
+			Tests       []wycheproofTest `json:"tests"`
+		} `json:"testGroups"`
+	}
+	loadJSON(t, "testdata/wycheproof_mldsa65_sign.json", &file)
+	for _, g := range file.TestGroups {
+		pub, priv, err := mldsa.NewKeyFromSeed(mldsa.MLDSA65(), mustHex(t, g.PrivateSeed))
+		require.NoError(t, err)
+		for _, tt := range g.Tests {
+			msg, context := mustHex(t, tt.Msg), tt.context(t)
+			if tt.Result == "invalid" {
+				_, err := mldsa.SignCtx(priv, msg, context)
+				require.ErrorIs(t, err, mldsa.ErrInvalidContext, "tcId %d", tt.TCID)
+				continue
+			}
+			want := mustHex(t, tt.Sig)
+			sig, err := priv.SignDeterministic(msg, &stdmldsa.Options{Context: context})
+			require.NoError(t, err, "tcId %d", tt.TCID)
+			require.Equal(t, want, sig, "tcId %d: %s", tt.TCID, tt.Comment)
+
+			ok, err := mldsa.VerifyCtx(pub, msg, want, context)
+			require.NoError(t, err)
+			require.True(t, ok, "tcId %d", tt.TCID)
+
+			// SignCtx 带随机化，结果不同但同样有效。
+			hedged, err := mldsa.SignCtx(priv, msg, context)
+			require.NoError(t, err)
+			ok, err = mldsa.VerifyCtx(pub, msg, hedged, context)
+			require.NoError(t, err)
+			require.True(t, ok, "tcId %d", tt.TCID)
+		}
+	}
+}
+
+// Wycheproof ML-DSA-65 验签向量：篡改签名、非法 hint 编码、长度错误的签名与公钥。
+func TestVerifyWycheproof(t *testing.T) {
+	t.Parallel()
+	var file struct {
+		TestGroups []struct {
+			PublicKey string           `json:"publicKey"`
+			Tests     []wycheproofTest `json:"tests"`
+		} `json:"testGroups"`
+	}
+	loadJSON(t, "testdata/wycheproof_mldsa65_verify.json", &file)
+	for _, g := range file.TestGroups {
+		pub, err := mldsa.NewPublicKey(mldsa.MLDSA65(), mustHex(t, g.PublicKey))
+		if err != nil {
+			require.ErrorIs(t, err, mldsa.ErrInvalidPublicKey)
+			for _, tt := range g.Tests {
+				require.Equal(t, "invalid", tt.Result, "tcId %d", tt.TCID)
+			}
+			continue
+		}
+		for _, tt := range g.Tests {
+			ok, err := mldsa.VerifyCtx(pub, mustHex(t, tt.Msg), mustHex(t, tt.Sig), tt.context(t))
+			if tt.Result == "valid" {
+				require.NoError(t, err, "tcId %d", tt.TCID)
+				require.True(t, ok, "tcId %d: %s", tt.TCID, tt.Comment)
+				continue
+			}
+			require.False(t, ok, "tcId %d: %s", tt.TCID, tt.Comment)
+			if len(tt.context(t)) > 255 {
+				require.ErrorIs(t, err, mldsa.ErrInvalidContext)
+			} else {
+				require.NoError(t, err, "tcId %d", tt.TCID)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package mlkem
+
+import (
+	"crypto/mlkem"
+	"crypto/mlkem/mlkemtest"
+)
+
+// SealDerand 用固定的封装随机数 m 执行 Seal（ML-KEM-768），仅供已知答案测试。
+func SealDerand(encapKey, aad, plainText, m []byte) ([]byte, error) {
+	ek, err := mlkem.NewEncapsulationKey768(encapKey)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, kemCT, err := mlkemtest.Encapsulate768(ek, m)
+	if err != nil {
+		return nil, err
+	}
+	return seal(MLKEM768, sharedSecret, kemCT, aad, plainText)
+}
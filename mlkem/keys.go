@@ -0,0 +1,177 @@
+package mlkem
+
+import (
+	"crypto"
+	"crypto/mlkem"
+	"errors"
+	"fmt"
+)
+
+// ParameterSet 是 FIPS 203 的参数集。
+type ParameterSet int
+
+// 支持的参数集：ML-KEM-768 适合绝大多数场景（约 AES-192 强度），
+// ML-KEM-1024 用于要求 CNSA 2.0 或最高安全级别（约 AES-256 强度）的场景。
+const (
+	MLKEM768  ParameterSet = 768
+	MLKEM1024 ParameterSet = 1024
+)
+
+var (
+	// ErrUnsupportedParameterSet 表示参数集不是 MLKEM768 或 MLKEM1024。
+	ErrUnsupportedParameterSet = errors.New("mlkem: unsupported parameter set")
+	// ErrInvalidPrivateKey 表示解封装种子或 PKCS#8 私钥非法。
+	ErrInvalidPrivateKey = errors.New("mlkem: invalid private key")
+	// ErrInvalidPublicKey 表示封装公钥或 PKIX 公钥非法。
+	ErrInvalidPublicKey = errors.New("mlkem: invalid public key")
+)
+
+// String 返回参数集名称，如 "ML-KEM-768"。
+func (p ParameterSet) String() string {
+	switch p {
+	case MLKEM768, MLKEM1024:
+		return fmt.Sprintf("ML-KEM-%d", int(p))
+	default:
+		return fmt.Sprintf("ParameterSet(%d)", int(p))
+	}
+}
+
+// EncapsulationKeySize 返回封装公钥长度（768 为 1184 字节，1024 为 1568 字节）。
+func (p ParameterSet) EncapsulationKeySize() int {
+	switch p {
+	case MLKEM768:
+		return mlkem.EncapsulationKeySize768
+	case MLKEM1024:
+		return mlkem.EncapsulationKeySize1024
+	default:
+		return 0
+	}
+}
+
+// CiphertextSize 返回密文长度（768 为 1088 字节，1024 为 1568 字节）。
+func (p ParameterSet) CiphertextSize() int {
+	switch p {
+	case MLKEM768:
+		return mlkem.CiphertextSize768
+	case MLKEM1024:
+		return mlkem.CiphertextSize1024
+	default:
+		return 0
+	}
+}
+
+// SeedSize 是解封装种子 (d || z) 的字节长度，两种参数集相同。
+const SeedSize = mlkem.SeedSize
+
+// decapsulator 为标准库两种解封装密钥的共同方法集。
+type decapsulator interface {
+	crypto.Decapsulator
+	Bytes() []byte
+}
+
+// DecapsulationKey 是带参数集的 ML-KEM 私钥，序列化形式为 64 字节种子。
+type DecapsulationKey struct {
+	params ParameterSet
+	dk     decapsulator
+}
+
+// EncapsulationKey 是带参数集的 ML-KEM 公钥。
+type EncapsulationKey struct {
+	params ParameterSet
+	ek     crypto.Encapsulator
+}
+
+// GenerateKey 按参数集随机生成解封装密钥。
+func GenerateKey(params ParameterSet) (*DecapsulationKey, error) {
+	var (
+		dk  decapsulator
+		err error
+	)
+	switch params {
+	case MLKEM768:
+		dk, err = mlkem.GenerateKey768()
+	case MLKEM1024:
+		dk, err = mlkem.GenerateKey1024()
+	default:
+		return nil, ErrUnsupportedParameterSet
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &DecapsulationKey{params: params, dk: dk}, nil
+}
+
+// NewDecapsulationKey 由 64 字节种子 (d || z) 还原解封装密钥。
+func NewDecapsulationKey(params ParameterSet, seed []byte) (*DecapsulationKey, error) {
+	var (
+		dk  decapsulator
+		err error
+	)
+	switch params {
+	case MLKEM768:
+		dk, err = mlkem.NewDecapsulationKey768(seed)
+	case MLKEM1024:
+		dk, err = mlkem.NewDecapsulationKey1024(seed)
+	default:
+		return nil, ErrUnsupportedParameterSet
+	}
+	if err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	return &DecapsulationKey{params: params, dk: dk}, nil
+}
+
+// NewEncapsulationKey 解析封装公钥，并做 FIPS 203 要求的模数检查。
+func NewEncapsulationKey(params ParameterSet, b []byte) (*EncapsulationKey, error) {
+	var (
+		ek  crypto.Encapsulator
+		err error
+	)
+	switch params {
+	case MLKEM768:
+		ek, err = mlkem.NewEncapsulationKey768(b)
+	case MLKEM1024:
+		ek, err = mlkem.NewEncapsulationKey1024(b)
+	default:
+		return nil, ErrUnsupportedParameterSet
+	}
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return &EncapsulationKey{params: params, ek: ek}, nil
+}
+
+// Parameters 返回参数集。
+func (k *DecapsulationKey) Parameters() ParameterSet {
+	return k.params
+}
+
+// Bytes 返回 64 字节解封装种子，须保密。
+func (k *DecapsulationKey) Bytes() []byte {
+	return k.dk.Bytes()
+}
+
+// EncapsulationKey 返回对应的封装公钥。
+func (k *DecapsulationKey) EncapsulationKey() *EncapsulationKey {
+	return &EncapsulationKey{params: k.params, ek: k.dk.Encapsulator()}
+}
+
+// Decapsulate 从密文还原共享密钥；与 Decapsulate 函数一样具有隐式拒绝语义。
+func (k *DecapsulationKey) Decapsulate(ciphertext []byte) ([]byte, error) {
+	return k.dk.Decapsulate(ciphertext)
+}
+
+// Parameters 返回参数集。
+func (k *EncapsulationKey) Parameters() ParameterSet {
+	return k.params
+}
+
+// Bytes 返回封装公钥编码。
+func (k *EncapsulationKey) Bytes() []byte {
+	return k.ek.Bytes()
+}
+
+// Encapsulate 产生一个共享密钥及其密文。
+func (k *EncapsulationKey) Encapsulate() (sharedSecret, ciphertext []byte) {
+	return k.ek.Encapsulate()
+}
@@ -0,0 +1,128 @@
+package mlkem_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/gtkit/encry/mlkem"
+	"github.com/stretchr/testify/require"
+)
+
+type acvpKeyGen struct {
+	TCID         int    `json:"tcId"`
+	ParameterSet string `json:"parameterSet"`
+	D            string `json:"d"`
+	Z            string `json:"z"`
+	EK           string `json:"ek"`
+	DK           string `json:"dk"`
+}
+
+// loadKeyGenVectors 读取 NIST ACVP ML-KEM-keyGen-FIPS203 向量（每个参数集取前 3 组）。
+func loadKeyGenVectors(t *testing.T) []acvpKeyGen {
+	t.Helper()
+	raw, err := os.ReadFile("testdata/acvp_keygen.json")
+	require.NoError(t, err)
+	var vectors []acvpKeyGen
+	require.NoError(t, json.Unmarshal(raw, &vectors))
+	return vectors
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func paramsByName(t *testing.T, name string) mlkem.ParameterSet {
+	t.Helper()
+	for _, p := range []mlkem.ParameterSet{mlkem.MLKEM768, mlkem.MLKEM1024} {
+		if p.String() == name {
+			return p
+		}
+	}
+	t.Fatalf("unknown parameter set %s", name)
+	return 0
+}
+
+func TestKeyGenACVP(t *testing.T) {
+	t.Parallel()
+	vectors := loadKeyGenVectors(t)
+	require.Len(t, vectors, 6)
+	for _, v := range vectors {
+		params := paramsByName(t, v.ParameterSet)
+		seed := append(mustHex(t, v.D), mustHex(t, v.Z)...)
+		dk, err := mlkem.NewDecapsulationKey(params, seed)
+		require.NoError(t, err, "tcId %d", v.TCID)
+		require.Equal(t, params, dk.Parameters())
+		require.Equal(t, seed, dk.Bytes())
+		require.Equal(t, mustHex(t, v.EK), dk.EncapsulationKey().Bytes(), "tcId %d", v.TCID)
+	}
+}
+
+func TestParameterSetRoundTrip(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		params mlkem.ParameterSet
+		name   string
+		ekSize int
+		ctSize int
+	}{
+		{mlkem.MLKEM768, "ML-KEM-768", 1184, 1088},
+		{mlkem.MLKEM1024, "ML-KEM-1024", 1568, 1568},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.name, tt.params.String())
+			require.Equal(t, tt.ekSize, tt.params.EncapsulationKeySize())
+			require.Equal(t, tt.ctSize, tt.params.CiphertextSize())
+
+			dk, err := mlkem.GenerateKey(tt.params)
+			require.NoError(t, err)
+			require.Len(t, dk.Bytes(), mlkem.SeedSize)
+			ek, err := mlkem.NewEncapsulationKey(tt.params, dk.EncapsulationKey().Bytes())
+			require.NoError(t, err)
+			require.Len(t, ek.Bytes(), tt.ekSize)
+			require.Equal(t, tt.params, ek.Parameters())
+
+			ss1, ct := ek.Encapsulate()
+			require.Len(t, ct, tt.ctSize)
+			ss2, err := dk.Decapsulate(ct)
+			require.NoError(t, err)
+			require.Equal(t, ss1, ss2)
+		})
+	}
+}
+
+func TestByteAPIMatchesTypedKeys(t *testing.T) {
+	t.Parallel()
+	decapSeed, encapKey, err := mlkem.GenerateKeyPair()
+	require.NoError(t, err)
+	dk, err := mlkem.NewDecapsulationKey(mlkem.MLKEM768, decapSeed)
+	require.NoError(t, err)
+	require.Equal(t, encapKey, dk.EncapsulationKey().Bytes())
+}
+
+func TestKeyErrors(t *testing.T) {
+	t.Parallel()
+	_, err := mlkem.GenerateKey(512)
+	require.ErrorIs(t, err, mlkem.ErrUnsupportedParameterSet)
+	_, err = mlkem.NewDecapsulationKey(512, make([]byte, mlkem.SeedSize))
+	require.ErrorIs(t, err, mlkem.ErrUnsupportedParameterSet)
+	_, err = mlkem.NewEncapsulationKey(512, nil)
+	require.ErrorIs(t, err, mlkem.ErrUnsupportedParameterSet)
+	require.Equal(t, "ParameterSet(512)", mlkem.ParameterSet(512).String())
+	require.Zero(t, mlkem.ParameterSet(512).CiphertextSize())
+	require.Zero(t, mlkem.ParameterSet(512).EncapsulationKeySize())
+
+	_, err = mlkem.NewDecapsulationKey(mlkem.MLKEM1024, make([]byte, 32))
+	require.ErrorIs(t, err, mlkem.ErrInvalidPrivateKey)
+	dk, err := mlkem.GenerateKey(mlkem.MLKEM768)
+	require.NoError(t, err)
+	// 768 的公钥不能当作 1024 解析。
+	_, err = mlkem.NewEncapsulationKey(mlkem.MLKEM1024, dk.EncapsulationKey().Bytes())
+	require.ErrorIs(t, err, mlkem.ErrInvalidPublicKey)
+}
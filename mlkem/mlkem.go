@@ -1,9 +1,16 @@
-// Package mlkem 提供后量子密钥封装机制 ML-KEM-768（NIST FIPS 203，基于 crypto/mlkem）。
+// Package mlkem 提供后量子密钥封装机制 ML-KEM（NIST FIPS 203，基于 crypto/mlkem）。
 //
 // 典型用法：接收方 GenerateKeyPair 得到 (decapSeed 私有, encapKey 公开)；发送方用
 // encapKey Encapsulate 得到 (sharedSecret, ciphertext)，把 ciphertext 发给接收方；
 // 接收方用 decapSeed 对 ciphertext Decapsulate 还原出相同的 sharedSecret。
 // 得到的 sharedSecret（32 字节）通常再经 hkdf 派生为对称密钥。
+//
+// 上述字节切片函数固定使用 ML-KEM-768；GenerateKey/NewDecapsulationKey 返回带参数集
+// （MLKEM768、MLKEM1024）的类型化密钥，并可用 MarshalPrivateKeyPEM/MarshalPublicKeyPEM
+// 编码为 PKCS#8/PKIX PEM（IETF LAMPS OID），与 Ed25519、RSA 密钥放在同一密钥目录中。
+//
+// 只需"用公钥加密一段数据"时直接用 Seal/Open：内部完成封装、HKDF-SHA256 派生与
+// ChaCha20-Poly1305 加密，输出带版本号与参数集的自描述密文，无需自行拼装 KEM-DEM。
 package mlkem
 
 import "crypto/mlkem"
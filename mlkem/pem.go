@@ -0,0 +1,204 @@
+package mlkem
+
+import (
+	"bytes"
+	"crypto/sha3"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyteasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// IETF LAMPS（draft-ietf-lamps-kyber-certificates）分配的算法 OID，
+// 同时用于 PKCS#8 私钥与 PKIX 公钥，AlgorithmIdentifier 不带参数。
+var (
+	oidMLKEM768  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 2}
+	oidMLKEM1024 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 3}
+)
+
+// ML-KEM-PrivateKey 的 CHOICE 标签：seed [0] IMPLICIT OCTET STRING、
+// expandedKey OCTET STRING、both SEQUENCE { seed, expandedKey }。
+var tagSeed = cryptobyteasn1.Tag(0).ContextSpecific()
+
+func (p ParameterSet) oid() asn1.ObjectIdentifier {
+	switch p {
+	case MLKEM768:
+		return oidMLKEM768
+	case MLKEM1024:
+		return oidMLKEM1024
+	default:
+		return nil
+	}
+}
+
+// parameterSetForOID 由 OID 识别参数集；ML-KEM-512（安全余量不足，标准库也不提供）
+// 与非 ML-KEM 的 OID 都返回 ErrUnsupportedParameterSet。
+func parameterSetForOID(oid asn1.ObjectIdentifier) (ParameterSet, error) {
+	switch {
+	case oid.Equal(oidMLKEM768):
+		return MLKEM768, nil
+	case oid.Equal(oidMLKEM1024):
+		return MLKEM1024, nil
+	default:
+		return 0, fmt.Errorf("%w: algorithm %s", ErrUnsupportedParameterSet, oid)
+	}
+}
+
+// MarshalPrivateKeyPEM 将私钥编码为 PKCS#8 PEM（"PRIVATE KEY"），私钥字段采用 seed 形式（64 字节种子）。
+func MarshalPrivateKeyPEM(dk *DecapsulationKey) ([]byte, error) {
+	if dk == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyteasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(0)
+		b.AddASN1(cryptobyteasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier(dk.params.oid())
+		})
+		b.AddASN1(cryptobyteasn1.OCTET_STRING, func(b *cryptobyte.Builder) {
+			b.AddASN1(tagSeed, func(b *cryptobyte.Builder) {
+				b.AddBytes(dk.Bytes())
+			})
+		})
+	})
+	der, err := b.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalPublicKeyPEM 将公钥编码为 PKIX PEM（"PUBLIC KEY"）。
+func MarshalPublicKeyPEM(ek *EncapsulationKey) ([]byte, error) {
+	if ek == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyteasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1(cryptobyteasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier(ek.params.oid())
+		})
+		b.AddASN1BitString(ek.Bytes())
+	})
+	der, err := b.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParsePrivateKeyPEM 从 PKCS#8 PEM 解析私钥，参数集由 OID 自动识别。
+// 接受 seed 与 both 两种私钥形式（both 会校验 expandedKey 与种子一致）；
+// 仅含 expandedKey 的私钥无法还原种子，返回 ErrInvalidPrivateKey。
+func ParsePrivateKeyPEM(data []byte) (*DecapsulationKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, ErrInvalidPrivateKey
+	}
+	input := cryptobyte.String(block.Bytes)
+	var (
+		pkcs8, algo, privateKey cryptobyte.String
+		version                 int64
+		oid                     asn1.ObjectIdentifier
+	)
+	if !input.ReadASN1(&pkcs8, cryptobyteasn1.SEQUENCE) || !input.Empty() ||
+		!pkcs8.ReadASN1Integer(&version) || (version != 0 && version != 1) ||
+		!pkcs8.ReadASN1(&algo, cryptobyteasn1.SEQUENCE) ||
+		!algo.ReadASN1ObjectIdentifier(&oid) || !algo.Empty() ||
+		!pkcs8.ReadASN1(&privateKey, cryptobyteasn1.OCTET_STRING) {
+		return nil, ErrInvalidPrivateKey
+	}
+	params, err := parameterSetForOID(oid)
+	if err != nil {
+		return nil, err
+	}
+
+	var seed, expanded cryptobyte.String
+	switch {
+	case privateKey.PeekASN1Tag(tagSeed):
+		if !privateKey.ReadASN1(&seed, tagSeed) {
+			return nil, ErrInvalidPrivateKey
+		}
+	case privateKey.PeekASN1Tag(cryptobyteasn1.SEQUENCE):
+		var both cryptobyte.String
+		if !privateKey.ReadASN1(&both, cryptobyteasn1.SEQUENCE) ||
+			!both.ReadASN1(&seed, cryptobyteasn1.OCTET_STRING) ||
+			!both.ReadASN1(&expanded, cryptobyteasn1.OCTET_STRING) || !both.Empty() {
+			return nil, ErrInvalidPrivateKey
+		}
+	default:
+		return nil, fmt.Errorf("%w: expandedKey-only private keys are not supported", ErrInvalidPrivateKey)
+	}
+	if !privateKey.Empty() {
+		return nil, ErrInvalidPrivateKey
+	}
+	dk, err := NewDecapsulationKey(params, seed)
+	if err != nil {
+		return nil, err
+	}
+	if expanded != nil && !matchesExpanded(dk, expanded) {
+		return nil, fmt.Errorf("%w: seed and expandedKey mismatch", ErrInvalidPrivateKey)
+	}
+	return dk, nil
+}
+
+// matchesExpanded 校验 FIPS 203 展开私钥 dkPKE || ek || H(ek) || z 中可由种子复算的部分。
+func matchesExpanded(dk *DecapsulationKey, expanded []byte) bool {
+	ek := dk.EncapsulationKey().Bytes()
+	// dkPKE 与 ek 的多项式部分等长（ek 另含 32 字节 rho）。
+	dkPKESize := len(ek) - 32
+	if len(expanded) != dkPKESize+len(ek)+64 {
+		return false
+	}
+	h := sha3.Sum256(ek)
+	rest := expanded[dkPKESize:]
+	return bytes.Equal(rest[:len(ek)], ek) &&
+		bytes.Equal(rest[len(ek):len(ek)+32], h[:]) &&
+		bytes.Equal(rest[len(ek)+32:], dk.Bytes()[32:])
+}
+
+// ParsePublicKeyPEM 从 PKIX PEM 解析公钥，参数集由 OID 自动识别。
+func ParsePublicKeyPEM(data []byte) (*EncapsulationKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, ErrInvalidPublicKey
+	}
+	input := cryptobyte.String(block.Bytes)
+	var (
+		spki, algo cryptobyte.String
+		oid        asn1.ObjectIdentifier
+		bits       asn1.BitString
+	)
+	if !input.ReadASN1(&spki, cryptobyteasn1.SEQUENCE) || !input.Empty() ||
+		!spki.ReadASN1(&algo, cryptobyteasn1.SEQUENCE) ||
+		!algo.ReadASN1ObjectIdentifier(&oid) || !algo.Empty() ||
+		!spki.ReadASN1BitString(&bits) || !spki.Empty() || bits.BitLength%8 != 0 {
+		return nil, ErrInvalidPublicKey
+	}
+	params, err := parameterSetForOID(oid)
+	if err != nil {
+		return nil, err
+	}
+	return NewEncapsulationKey(params, bits.Bytes)
+}
+
+// ReadPrivateKey 从 PEM 文件读取 ML-KEM 私钥。
+func ReadPrivateKey(path string) (*DecapsulationKey, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- this helper intentionally reads a caller-provided key path.
+	if err != nil {
+		return nil, fmt.Errorf("read private key %s: %w", path, err)
+	}
+	return ParsePrivateKeyPEM(data)
+}
+
+// ReadPublicKey 从 PEM 文件读取 ML-KEM 公钥。
+func ReadPublicKey(path string) (*EncapsulationKey, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- this helper intentionally reads a caller-provided key path.
+	if err != nil {
+		return nil, fmt.Errorf("read public key %s: %w", path, err)
+	}
+	return ParsePublicKeyPEM(data)
+}
@@ -0,0 +1,179 @@
+package mlkem_test
+
+import (
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gtkit/encry/mlkem"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyteasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+func TestPEMRoundTrip(t *testing.T) {
+	t.Parallel()
+	// 固定前缀取自 draft-ietf-lamps-kyber-certificates：seed 形式的 PKCS#8 与 SPKI 头部。
+	tests := []struct {
+		params     mlkem.ParameterSet
+		privPrefix string
+		pubPrefix  string
+	}{
+		{mlkem.MLKEM768, "3054020100300b060960864801650304040204428040", "308204b2300b0609608648016503040402038204a100"},
+		{mlkem.MLKEM1024, "3054020100300b060960864801650304040304428040", "30820632300b06096086480165030404030382062100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.params.String(), func(t *testing.T) {
+			t.Parallel()
+			dk, err := mlkem.GenerateKey(tt.params)
+			require.NoError(t, err)
+
+			privPEM, err := mlkem.MarshalPrivateKeyPEM(dk)
+			require.NoError(t, err)
+			block, _ := pem.Decode(privPEM)
+			require.Equal(t, "PRIVATE KEY", block.Type)
+			require.Equal(t, tt.privPrefix+hex.EncodeToString(dk.Bytes()), hex.EncodeToString(block.Bytes))
+
+			pubPEM, err := mlkem.MarshalPublicKeyPEM(dk.EncapsulationKey())
+			require.NoError(t, err)
+			block, _ = pem.Decode(pubPEM)
+			require.Equal(t, "PUBLIC KEY", block.Type)
+			require.Equal(t, tt.pubPrefix+hex.EncodeToString(dk.EncapsulationKey().Bytes()), hex.EncodeToString(block.Bytes))
+
+			parsed, err := mlkem.ParsePrivateKeyPEM(privPEM)
+			require.NoError(t, err)
+			require.Equal(t, tt.params, parsed.Parameters())
+			require.Equal(t, dk.Bytes(), parsed.Bytes())
+			pub, err := mlkem.ParsePublicKeyPEM(pubPEM)
+			require.NoError(t, err)
+			require.Equal(t, tt.params, pub.Parameters())
+			require.Equal(t, dk.EncapsulationKey().Bytes(), pub.Bytes())
+		})
+	}
+}
+
+// marshalPKCS8 按 ML-KEM-PrivateKey 的指定形式手工构造 PKCS#8。
+func marshalPKCS8(t *testing.T, oidLast int, privateKey func(b *cryptobyte.Builder)) []byte {
+	t.Helper()
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyteasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(0)
+		b.AddASN1(cryptobyteasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier([]int{2, 16, 840, 1, 101, 3, 4, 4, oidLast})
+		})
+		b.AddASN1(cryptobyteasn1.OCTET_STRING, privateKey)
+	})
+	der, err := b.Bytes()
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestParsePrivateKeyForms(t *testing.T) {
+	t.Parallel()
+	for _, v := range loadKeyGenVectors(t) {
+		params := paramsByName(t, v.ParameterSet)
+		oidLast := 2
+		if params == mlkem.MLKEM1024 {
+			oidLast = 3
+		}
+		seed := append(mustHex(t, v.D), mustHex(t, v.Z)...)
+		expanded := mustHex(t, v.DK)
+		both := func(seed, expanded []byte) func(b *cryptobyte.Builder) {
+			return func(b *cryptobyte.Builder) {
+				b.AddASN1(cryptobyteasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					b.AddASN1OctetString(seed)
+					b.AddASN1OctetString(expanded)
+				})
+			}
+		}
+
+		dk, err := mlkem.ParsePrivateKeyPEM(marshalPKCS8(t, oidLast, both(seed, expanded)))
+		require.NoError(t, err, "tcId %d", v.TCID)
+		require.Equal(t, seed, dk.Bytes())
+		require.Equal(t, mustHex(t, v.EK), dk.EncapsulationKey().Bytes())
+
+		// both 形式中 expandedKey 与种子不一致。
+		bad := append([]byte(nil), expanded...)
+		bad[len(bad)-1] ^= 1
+		_, err = mlkem.ParsePrivateKeyPEM(marshalPKCS8(t, oidLast, both(seed, bad)))
+		require.ErrorIs(t, err, mlkem.ErrInvalidPrivateKey)
+
+		// 仅 expandedKey：无法还原种子。
+		_, err = mlkem.ParsePrivateKeyPEM(marshalPKCS8(t, oidLast, func(b *cryptobyte.Builder) {
+			b.AddASN1OctetString(expanded)
+		}))
+		require.ErrorIs(t, err, mlkem.ErrInvalidPrivateKey)
+	}
+}
+
+func TestParsePEMErrors(t *testing.T) {
+	t.Parallel()
+	seedForm := func(b *cryptobyte.Builder) {
+		b.AddASN1(cryptobyteasn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+			b.AddBytes(make([]byte, mlkem.SeedSize))
+		})
+	}
+	// ML-KEM-512 不受支持。
+	_, err := mlkem.ParsePrivateKeyPEM(marshalPKCS8(t, 1, seedForm))
+	require.ErrorIs(t, err, mlkem.ErrUnsupportedParameterSet)
+	_, err = mlkem.ParsePrivateKeyPEM(marshalPKCS8(t, 2, func(b *cryptobyte.Builder) {
+		b.AddASN1(cryptobyteasn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+			b.AddBytes(make([]byte, 32))
+		})
+	}))
+	require.ErrorIs(t, err, mlkem.ErrInvalidPrivateKey)
+
+	_, err = mlkem.ParsePrivateKeyPEM([]byte("not pem"))
+	require.ErrorIs(t, err, mlkem.ErrInvalidPrivateKey)
+	_, err = mlkem.ParsePublicKeyPEM([]byte("not pem"))
+	require.ErrorIs(t, err, mlkem.ErrInvalidPublicKey)
+
+	// 其他算法的 PKCS#8（Ed25519）。
+	ed := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: mustHex(t,
+		"302e020100300506032b657004220420"+"d4ee72dbf913584ad5b6d8f1f769f8ad3afe7c28cbf1d4fbe097a88f44755842")})
+	_, err = mlkem.ParsePrivateKeyPEM(ed)
+	require.Error(t, err)
+
+	_, err = mlkem.MarshalPrivateKeyPEM(nil)
+	require.ErrorIs(t, err, mlkem.ErrInvalidPrivateKey)
+	_, err = mlkem.MarshalPublicKeyPEM(nil)
+	require.ErrorIs(t, err, mlkem.ErrInvalidPublicKey)
+}
+
+func TestReadKeyFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	dk, err := mlkem.GenerateKey(mlkem.MLKEM1024)
+	require.NoError(t, err)
+	privPEM, err := mlkem.MarshalPrivateKeyPEM(dk)
+	require.NoError(t, err)
+	pubPEM, err := mlkem.MarshalPublicKeyPEM(dk.EncapsulationKey())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "private.pem"), privPEM, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "public.pem"), pubPEM, 0o600))
+
+	priv, err := mlkem.ReadPrivateKey(filepath.Join(dir, "private.pem"))
+	require.NoError(t, err)
+	require.Equal(t, dk.Bytes(), priv.Bytes())
+	pub, err := mlkem.ReadPublicKey(filepath.Join(dir, "public.pem"))
+	require.NoError(t, err)
+	require.Equal(t, mlkem.MLKEM1024, pub.Parameters())
+
+	_, err = mlkem.ReadPrivateKey(filepath.Join(dir, "missing.pem"))
+	require.Error(t, err)
+	_, err = mlkem.ReadPublicKey(filepath.Join(dir, "missing.pem"))
+	require.Error(t, err)
+}
+
+func ExampleMarshalPrivateKeyPEM() {
+	dk, _ := mlkem.GenerateKey(mlkem.MLKEM1024)
+	privPEM, _ := mlkem.MarshalPrivateKeyPEM(dk)
+
+	// 参数集由 PEM 中的 OID 自动识别。
+	parsed, _ := mlkem.ParsePrivateKeyPEM(privPEM)
+	fmt.Println(parsed.Parameters())
+	// Output: ML-KEM-1024
+}
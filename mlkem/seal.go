@@ -0,0 +1,142 @@
+package mlkem
+
+import (
+	"crypto/cipher"
+	"errors"
+
+	"github.com/gtkit/encry/hkdf"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// sealFormatVersion 为 Seal 输出的格式版本。
+//
+// 密文格式：版本号(1) || 参数集(1) || ML-KEM 密文 || ChaCha20-Poly1305 密文。
+// 参数集：1 = ML-KEM-768，2 = ML-KEM-1024。
+const (
+	sealFormatVersion byte = 1
+	sealHeaderSize         = 2
+	sealInfo               = "encry/mlkem seal v1"
+)
+
+// ErrInvalidCiphertext 表示 Open 的密文格式非法或认证失败。ML-KEM 对被篡改的 KEM 密文
+// 不报错（隐式拒绝），但得到的共享密钥不同，Open 会把它转换为这里的认证失败。
+var ErrInvalidCiphertext = errors.New("mlkem: invalid ciphertext")
+
+func (p ParameterSet) sealID() byte {
+	if p == MLKEM1024 {
+		return 2
+	}
+	return 1
+}
+
+func parameterSetForSealID(id byte) (ParameterSet, bool) {
+	switch id {
+	case 1:
+		return MLKEM768, true
+	case 2:
+		return MLKEM1024, true
+	default:
+		return 0, false
+	}
+}
+
+// Seal 用封装公钥加密明文并绑定 aad（KEM-DEM：ML-KEM 封装 → HKDF-SHA256 → ChaCha20-Poly1305）。
+// 参数集由 encapKey 长度决定（1184 字节为 ML-KEM-768，1568 字节为 ML-KEM-1024）。
+// 每次调用都做一次新的封装，相同明文的密文互不相同。
+func Seal(encapKey, aad, plainText []byte) ([]byte, error) {
+	params := MLKEM768
+	if len(encapKey) == MLKEM1024.EncapsulationKeySize() {
+		params = MLKEM1024
+	}
+	ek, err := NewEncapsulationKey(params, encapKey)
+	if err != nil {
+		return nil, err
+	}
+	return SealWithKey(ek, aad, plainText)
+}
+
+// SealWithKey 与 Seal 相同，但使用类型化的封装公钥。
+func SealWithKey(ek *EncapsulationKey, aad, plainText []byte) ([]byte, error) {
+	if ek == nil {
+		return nil, ErrInvalidPublicKey
+	}
+	sharedSecret, kemCT := ek.Encapsulate()
+	return seal(ek.params, sharedSecret, kemCT, aad, plainText)
+}
+
+// seal 为 SealWithKey 的内核，封装结果由调用方提供（测试借此固定随机数）。
+func seal(params ParameterSet, sharedSecret, kemCT, aad, plainText []byte) ([]byte, error) {
+	header := []byte{sealFormatVersion, params.sealID()}
+	aead, err := newSealAEAD(sharedSecret, header, kemCT)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, sealHeaderSize+len(kemCT)+len(plainText)+aead.Overhead())
+	out = append(out, header...)
+	out = append(out, kemCT...)
+	nonce := make([]byte, aead.NonceSize())
+	return aead.Seal(out, nonce, plainText, sealAAD(header, aad)), nil
+}
+
+// Open 用解封装种子解密 Seal 的输出并校验 aad；参数集由密文头部识别。
+// 密钥不符、aad 不符或密文被篡改时统一返回 ErrInvalidCiphertext。
+func Open(decapSeed, aad, cipherText []byte) ([]byte, error) {
+	if len(cipherText) < sealHeaderSize {
+		return nil, ErrInvalidCiphertext
+	}
+	params, ok := parameterSetForSealID(cipherText[1])
+	if !ok {
+		return nil, ErrInvalidCiphertext
+	}
+	dk, err := NewDecapsulationKey(params, decapSeed)
+	if err != nil {
+		return nil, err
+	}
+	return OpenWithKey(dk, aad, cipherText)
+}
+
+// OpenWithKey 与 Open 相同，但使用类型化的解封装密钥；密文参数集与密钥不符时返回 ErrInvalidCiphertext。
+func OpenWithKey(dk *DecapsulationKey, aad, cipherText []byte) ([]byte, error) {
+	if dk == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	ctSize := dk.params.CiphertextSize()
+	if len(cipherText) < sealHeaderSize+ctSize+chacha20poly1305.Overhead ||
+		cipherText[0] != sealFormatVersion || cipherText[1] != dk.params.sealID() {
+		return nil, ErrInvalidCiphertext
+	}
+	header := cipherText[:sealHeaderSize]
+	kemCT := cipherText[sealHeaderSize : sealHeaderSize+ctSize]
+	sharedSecret, err := dk.Decapsulate(kemCT)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	aead, err := newSealAEAD(sharedSecret, header, kemCT)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	plain, err := aead.Open(nil, nonce, cipherText[sealHeaderSize+ctSize:], sealAAD(header, aad))
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plain, nil
+}
+
+// newSealAEAD 由共享密钥派生一次性 AEAD 密钥：salt 绑定头部与 KEM 密文。
+// 密钥每条消息唯一，因此 nonce 固定为全零。
+func newSealAEAD(sharedSecret, header, kemCT []byte) (cipher.AEAD, error) {
+	salt := append(append([]byte(nil), header...), kemCT...)
+	key, err := hkdf.Derive(sharedSecret, salt, sealInfo, chacha20poly1305.KeySize)
+	clear(sharedSecret)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	return chacha20poly1305.New(key)
+}
+
+// sealAAD 把头部并入 AEAD 的附加数据，防止改写版本号或参数集。
+func sealAAD(header, aad []byte) []byte {
+	return append(append([]byte(nil), header...), aad...)
+}
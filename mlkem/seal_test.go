@@ -0,0 +1,170 @@
+package mlkem_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/gtkit/encry/hkdf"
+	"github.com/gtkit/encry/mlkem"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// sealVector 以 NIST ACVP ML-KEM-encapDecap-FIPS203 的封装用例（ek, m → c, k）为基础，
+// sealed 为固定 m 时 Seal 的完整输出。
+type sealVector struct {
+	TCID      int    `json:"tcId"`
+	EK        string `json:"ek"`
+	M         string `json:"m"`
+	C         string `json:"c"`
+	K         string `json:"k"`
+	AAD       string `json:"aad"`
+	PlainText string `json:"plainText"`
+	Sealed    string `json:"sealed"`
+}
+
+func TestSealKnownAnswer(t *testing.T) {
+	t.Parallel()
+	raw, err := os.ReadFile("testdata/seal_v1.json")
+	require.NoError(t, err)
+	var v sealVector
+	require.NoError(t, json.Unmarshal(raw, &v))
+
+	ek, aad, pt := mustHex(t, v.EK), mustHex(t, v.AAD), mustHex(t, v.PlainText)
+	sealed, err := mlkem.SealDerand(ek, aad, pt, mustHex(t, v.M))
+	require.NoError(t, err)
+	require.Equal(t, mustHex(t, v.Sealed), sealed)
+
+	// 头部 || ACVP 密文 c || AEAD 密文；AEAD 密钥由 ACVP 共享密钥 k 独立复算。
+	header, kemCT := sealed[:2], sealed[2:2+mlkem.MLKEM768.CiphertextSize()]
+	require.Equal(t, []byte{1, 1}, header)
+	require.Equal(t, mustHex(t, v.C), kemCT)
+	key, err := hkdf.Derive(mustHex(t, v.K), append(append([]byte(nil), header...), kemCT...), "encry/mlkem seal v1", chacha20poly1305.KeySize)
+	require.NoError(t, err)
+	aead, err := chacha20poly1305.New(key)
+	require.NoError(t, err)
+	got, err := aead.Open(nil, make([]byte, aead.NonceSize()), sealed[len(header)+len(kemCT):], append(append([]byte(nil), header...), aad...))
+	require.NoError(t, err)
+	require.Equal(t, pt, got)
+}
+
+func TestSealOpen(t *testing.T) {
+	t.Parallel()
+	for _, params := range []mlkem.ParameterSet{mlkem.MLKEM768, mlkem.MLKEM1024} {
+		t.Run(params.String(), func(t *testing.T) {
+			t.Parallel()
+			dk, err := mlkem.GenerateKey(params)
+			require.NoError(t, err)
+			ek := dk.EncapsulationKey()
+			aad := []byte("order:42")
+
+			for _, pt := range [][]byte{nil, []byte("secret message"), make([]byte, 4096)} {
+				ct, err := mlkem.Seal(ek.Bytes(), aad, pt)
+				require.NoError(t, err)
+				require.Len(t, ct, 2+params.CiphertextSize()+len(pt)+chacha20poly1305.Overhead)
+
+				got, err := mlkem.Open(dk.Bytes(), aad, ct)
+				require.NoError(t, err)
+				require.Equal(t, string(pt), string(got))
+
+				got, err = mlkem.OpenWithKey(dk, aad, ct)
+				require.NoError(t, err)
+				require.Equal(t, string(pt), string(got))
+			}
+
+			ct, err := mlkem.SealWithKey(ek, nil, []byte("typed"))
+			require.NoError(t, err)
+			got, err := mlkem.Open(dk.Bytes(), nil, ct)
+			require.NoError(t, err)
+			require.Equal(t, []byte("typed"), got)
+
+			other, err := mlkem.Seal(ek.Bytes(), nil, []byte("typed"))
+			require.NoError(t, err)
+			require.NotEqual(t, ct, other)
+		})
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	t.Parallel()
+	dk, err := mlkem.GenerateKey(mlkem.MLKEM768)
+	require.NoError(t, err)
+	otherDK, err := mlkem.GenerateKey(mlkem.MLKEM768)
+	require.NoError(t, err)
+	dk1024, err := mlkem.GenerateKey(mlkem.MLKEM1024)
+	require.NoError(t, err)
+	aad := []byte("aad")
+	ct, err := mlkem.Seal(dk.EncapsulationKey().Bytes(), aad, []byte("payload"))
+	require.NoError(t, err)
+
+	flip := func(i int) []byte {
+		out := append([]byte(nil), ct...)
+		out[i] ^= 0x01
+		return out
+	}
+	tests := []struct {
+		name string
+		dk   *mlkem.DecapsulationKey
+		aad  []byte
+		ct   []byte
+	}{
+		{name: "aad mismatch", dk: dk, aad: []byte("other"), ct: ct},
+		{name: "wrong key", dk: otherDK, aad: aad, ct: ct},
+		{name: "parameter set mismatch", dk: dk1024, aad: aad, ct: ct},
+		{name: "version", dk: dk, aad: aad, ct: flip(0)},
+		{name: "unknown parameter set", dk: dk, aad: aad, ct: append([]byte{1, 9}, ct[2:]...)},
+		// ML-KEM 隐式拒绝：篡改 KEM 密文只会得到错误的共享密钥，由 AEAD 认证拦截。
+		{name: "tampered kem ciphertext", dk: dk, aad: aad, ct: flip(10)},
+		{name: "tampered payload", dk: dk, aad: aad, ct: flip(len(ct) - 1)},
+		{name: "truncated", dk: dk, aad: aad, ct: ct[:2+mlkem.MLKEM768.CiphertextSize()+chacha20poly1305.Overhead-1]},
+		{name: "header only", dk: dk, aad: aad, ct: ct[:2]},
+		{name: "empty", dk: dk, aad: aad, ct: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := mlkem.OpenWithKey(tt.dk, tt.aad, tt.ct)
+			require.ErrorIs(t, err, mlkem.ErrInvalidCiphertext)
+			_, err = mlkem.Open(tt.dk.Bytes(), tt.aad, tt.ct)
+			require.ErrorIs(t, err, mlkem.ErrInvalidCiphertext)
+		})
+	}
+}
+
+func TestSealOpenInvalidKeys(t *testing.T) {
+	t.Parallel()
+	_, err := mlkem.Seal(make([]byte, 10), nil, []byte("x"))
+	require.ErrorIs(t, err, mlkem.ErrInvalidPublicKey)
+	_, err = mlkem.SealWithKey(nil, nil, []byte("x"))
+	require.ErrorIs(t, err, mlkem.ErrInvalidPublicKey)
+
+	dk, err := mlkem.GenerateKey(mlkem.MLKEM768)
+	require.NoError(t, err)
+	ct, err := mlkem.Seal(dk.EncapsulationKey().Bytes(), nil, []byte("x"))
+	require.NoError(t, err)
+	_, err = mlkem.Open(make([]byte, 10), nil, ct)
+	require.ErrorIs(t, err, mlkem.ErrInvalidPrivateKey)
+	_, err = mlkem.OpenWithKey(nil, nil, ct)
+	require.ErrorIs(t, err, mlkem.ErrInvalidPrivateKey)
+}
+
+func ExampleSeal() {
+	dk, err := mlkem.GenerateKey(mlkem.MLKEM768)
+	if err != nil {
+		panic(err)
+	}
+	// 发送方只需要封装公钥。
+	ct, err := mlkem.Seal(dk.EncapsulationKey().Bytes(), []byte("user:1001"), []byte("hello"))
+	if err != nil {
+		panic(err)
+	}
+	// 接收方用 64 字节解封装种子解密，aad 必须一致。
+	plain, err := mlkem.Open(dk.Bytes(), []byte("user:1001"), ct)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(plain))
+	// Output: hello
+}
@@ -0,0 +1,225 @@
+package noise
+
+import (
+	"crypto/ecdh"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// maxPlaintextSize 为单帧传输消息可承载的最大明文长度。
+const maxPlaintextSize = MaxMessageSize - TagSize
+
+// Conn 是基于 Noise 的加密连接，实现 net.Conn。
+//
+// 每条 Noise 消息以 2 字节大端长度前缀分帧（Noise 规范第 13 节建议的 TCP 承载方式），
+// 握手消息负载为空。首次 Read/Write 时自动握手，也可显式调用 Handshake。
+// Read 与 Write 可分别在不同 goroutine 中并发调用。
+type Conn struct {
+	conn net.Conn
+
+	handshakeMu  sync.Mutex
+	hs           *HandshakeState
+	handshakeErr error
+
+	readMu  sync.Mutex
+	recv    *CipherState
+	readBuf []byte
+	rawBuf  []byte
+
+	writeMu sync.Mutex
+	send    *CipherState
+	outBuf  []byte
+}
+
+// Client 以发起方身份包装 conn。
+func Client(conn net.Conn, pattern Pattern, opts ...Option) (*Conn, error) {
+	return newConn(conn, pattern, true, opts)
+}
+
+// Server 以响应方身份包装 conn。
+func Server(conn net.Conn, pattern Pattern, opts ...Option) (*Conn, error) {
+	return newConn(conn, pattern, false, opts)
+}
+
+func newConn(conn net.Conn, pattern Pattern, initiator bool, opts []Option) (*Conn, error) {
+	hs, err := NewHandshake(pattern, initiator, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn: conn, hs: hs}, nil
+}
+
+// Handshake 执行握手（已完成时直接返回）。失败后连接不可再用，应关闭。
+//
+// 对端静态公钥在握手中传输的模式（XX、IK 响应方）下，握手后应通过 PeerStatic
+// 核对对端身份后再收发业务数据。
+func (c *Conn) Handshake() error {
+	c.handshakeMu.Lock()
+	defer c.handshakeMu.Unlock()
+	if c.handshakeErr != nil || c.hs.Complete() {
+		return c.handshakeErr
+	}
+	c.handshakeErr = c.handshake()
+	return c.handshakeErr
+}
+
+func (c *Conn) handshake() error {
+	for !c.hs.Complete() {
+		if c.hs.WriteTurn() {
+			msg, err := c.hs.WriteMessage(nil)
+			if err != nil {
+				return err
+			}
+			if err := c.writeFrame(msg); err != nil {
+				return err
+			}
+			continue
+		}
+		msg, err := c.readFrame()
+		if err != nil {
+			return err
+		}
+		if _, err := c.hs.ReadMessage(msg); err != nil {
+			return err
+		}
+	}
+	send, recv, err := c.hs.CipherStates()
+	if err != nil {
+		return err
+	}
+	c.writeMu.Lock()
+	c.send = send
+	c.writeMu.Unlock()
+	c.readMu.Lock()
+	c.recv = recv
+	c.readMu.Unlock()
+	return nil
+}
+
+// PeerStatic 返回握手中得到的对端静态公钥，握手未完成时为 nil。
+func (c *Conn) PeerStatic() *ecdh.PublicKey {
+	c.handshakeMu.Lock()
+	defer c.handshakeMu.Unlock()
+	if !c.hs.Complete() {
+		return nil
+	}
+	return c.hs.PeerStatic()
+}
+
+// HandshakeHash 返回握手哈希，握手未完成时为 nil。
+func (c *Conn) HandshakeHash() []byte {
+	c.handshakeMu.Lock()
+	defer c.handshakeMu.Unlock()
+	if !c.hs.Complete() {
+		return nil
+	}
+	return c.hs.HandshakeHash()
+}
+
+// Read 读取并解密数据；一帧解密后未读完的部分留给下次 Read。
+func (c *Conn) Read(b []byte) (int, error) {
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+	c.readMu.Lock()
+	defer c.readMu.Unlock()
+	for len(c.readBuf) == 0 {
+		msg, err := c.readFrame()
+		if err != nil {
+			return 0, err
+		}
+		if c.readBuf, err = c.recv.decrypt(c.readBuf[:0], nil, msg); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(b, c.readBuf)
+	c.readBuf = c.readBuf[n:]
+	return n, nil
+}
+
+// Write 加密并发送数据，超过单帧容量时自动拆分为多帧。
+func (c *Conn) Write(b []byte) (int, error) {
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	var written int
+	for len(b) > 0 {
+		chunk := b[:min(len(b), maxPlaintextSize)]
+		// 直接加密到帧缓冲区的长度前缀之后，避免再复制一次。
+		frame, err := c.send.encrypt(append(c.outBuf[:0], 0, 0), nil, chunk)
+		if err != nil {
+			return written, err
+		}
+		c.outBuf = frame
+		binary.BigEndian.PutUint16(frame, uint16(len(frame)-2)) // #nosec G115 -- 帧长度不超过 MaxMessageSize.
+		if _, err := c.conn.Write(frame); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+		b = b[len(chunk):]
+	}
+	return written, nil
+}
+
+// Close 关闭底层连接。
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// LocalAddr 返回底层连接的本地地址。
+func (c *Conn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr 返回底层连接的对端地址。
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// SetDeadline 设置底层连接的读写截止时间（同样作用于握手）。
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// SetReadDeadline 设置底层连接的读截止时间。
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline 设置底层连接的写截止时间。
+func (c *Conn) SetWriteDeadline(t time.Time) error {
+	return c.conn.SetWriteDeadline(t)
+}
+
+func (c *Conn) writeFrame(msg []byte) error {
+	frame := make([]byte, 2+len(msg))
+	binary.BigEndian.PutUint16(frame, uint16(len(msg))) // #nosec G115 -- len(msg) <= MaxMessageSize.
+	copy(frame[2:], msg)
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// readFrame 读取一帧；返回的切片在下一次 readFrame 前有效。
+func (c *Conn) readFrame() ([]byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.conn, header[:]); err != nil {
+		return nil, err
+	}
+	n := int(binary.BigEndian.Uint16(header[:]))
+	if cap(c.rawBuf) < n {
+		c.rawBuf = make([]byte, n)
+	}
+	buf := c.rawBuf[:n]
+	if _, err := io.ReadFull(c.conn, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf, nil
+}
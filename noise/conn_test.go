@@ -0,0 +1,180 @@
+package noise_test
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/gtkit/encry/noise"
+	"github.com/stretchr/testify/require"
+)
+
+// connPair 在 net.Pipe 两端建立 Noise 连接并并发完成握手。
+func connPair(t *testing.T, pattern noise.Pattern, clientOpts, serverOpts []noise.Option) (*noise.Conn, *noise.Conn) {
+	t.Helper()
+	c1, c2 := net.Pipe()
+	t.Cleanup(func() {
+		c1.Close()
+		c2.Close()
+	})
+	client, err := noise.Client(c1, pattern, clientOpts...)
+	require.NoError(t, err)
+	server, err := noise.Server(c2, pattern, serverOpts...)
+	require.NoError(t, err)
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.Handshake() }()
+	require.NoError(t, client.Handshake())
+	require.NoError(t, <-errc)
+	return client, server
+}
+
+func TestConnPatterns(t *testing.T) {
+	t.Parallel()
+	serverKey := mustGenerate(t)
+	clientKey := mustGenerate(t)
+
+	tests := []struct {
+		name       string
+		pattern    noise.Pattern
+		clientOpts []noise.Option
+		serverOpts []noise.Option
+		// clientIdentity 表示服务端在握手后能否得到客户端静态公钥。
+		clientIdentity bool
+	}{
+		{"XX", noise.XX, []noise.Option{noise.WithStaticKey(clientKey)}, []noise.Option{noise.WithStaticKey(serverKey)}, true},
+		{"IK", noise.IK, []noise.Option{noise.WithStaticKey(clientKey), noise.WithPeerStatic(serverKey.PublicKey())}, []noise.Option{noise.WithStaticKey(serverKey)}, true},
+		{"NK", noise.NK, []noise.Option{noise.WithPeerStatic(serverKey.PublicKey())}, []noise.Option{noise.WithStaticKey(serverKey)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			client, server := connPair(t, tt.pattern, tt.clientOpts, tt.serverOpts)
+			require.True(t, serverKey.PublicKey().Equal(client.PeerStatic()))
+			if tt.clientIdentity {
+				require.True(t, clientKey.PublicKey().Equal(server.PeerStatic()))
+			} else {
+				require.Nil(t, server.PeerStatic())
+			}
+			require.Equal(t, client.HandshakeHash(), server.HandshakeHash())
+
+			// 双向收发，且超过单帧容量的数据被拆分后完整到达。
+			large := bytes.Repeat([]byte("0123456789abcdef"), 10000)
+			errc := make(chan error, 1)
+			go func() {
+				_, err := client.Write(large)
+				errc <- err
+			}()
+			got := make([]byte, len(large))
+			_, err := io.ReadFull(server, got)
+			require.NoError(t, err)
+			require.NoError(t, <-errc)
+			require.Equal(t, large, got)
+
+			go func() {
+				_, err := server.Write([]byte("pong"))
+				errc <- err
+			}()
+			buf := make([]byte, 2)
+			n, err := client.Read(buf)
+			require.NoError(t, err)
+			require.Equal(t, "po", string(buf[:n]))
+			n, err = client.Read(buf)
+			require.NoError(t, err)
+			require.Equal(t, "ng", string(buf[:n]))
+			require.NoError(t, <-errc)
+		})
+	}
+}
+
+func TestConnImplicitHandshake(t *testing.T) {
+	t.Parallel()
+	serverKey := mustGenerate(t)
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	var _ net.Conn = (*noise.Conn)(nil)
+	client, err := noise.Client(c1, noise.NK, noise.WithPeerStatic(serverKey.PublicKey()))
+	require.NoError(t, err)
+	server, err := noise.Server(c2, noise.NK, noise.WithStaticKey(serverKey))
+	require.NoError(t, err)
+	require.Nil(t, client.PeerStatic())
+	require.Nil(t, client.HandshakeHash())
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := client.Write([]byte("hello"))
+		errc <- err
+	}()
+	buf := make([]byte, 16)
+	n, err := server.Read(buf)
+	require.NoError(t, err)
+	require.NoError(t, <-errc)
+	require.Equal(t, "hello", string(buf[:n]))
+}
+
+func TestConnWrongServerKey(t *testing.T) {
+	t.Parallel()
+	serverKey := mustGenerate(t)
+	impostor := mustGenerate(t)
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+
+	client, err := noise.Client(c1, noise.NK, noise.WithPeerStatic(serverKey.PublicKey()))
+	require.NoError(t, err)
+	server, err := noise.Server(c2, noise.NK, noise.WithStaticKey(impostor))
+	require.NoError(t, err)
+
+	go func() { _ = client.Handshake() }()
+	err = server.Handshake()
+	require.ErrorIs(t, err, noise.ErrInvalidMessage)
+	// 失败后不可再用。
+	_, err = server.Write([]byte("x"))
+	require.ErrorIs(t, err, noise.ErrInvalidMessage)
+}
+
+func TestConnTamperedFrame(t *testing.T) {
+	t.Parallel()
+	serverKey := mustGenerate(t)
+	clientRaw, mitm := net.Pipe()
+	serverRaw, mitmServer := net.Pipe()
+	defer clientRaw.Close()
+	defer mitm.Close()
+	defer serverRaw.Close()
+	defer mitmServer.Close()
+
+	// 中间人原样转发握手，翻转传输消息中的一个比特。
+	go func() {
+		_, _ = io.CopyN(mitmServer, mitm, 2+48)
+		_, _ = io.CopyN(mitm, mitmServer, 2+48)
+		frame := make([]byte, 2+5+16)
+		if _, err := io.ReadFull(mitm, frame); err != nil {
+			return
+		}
+		frame[3] ^= 1
+		_, _ = mitmServer.Write(frame)
+	}()
+
+	client, err := noise.Client(clientRaw, noise.NK, noise.WithPeerStatic(serverKey.PublicKey()))
+	require.NoError(t, err)
+	server, err := noise.Server(serverRaw, noise.NK, noise.WithStaticKey(serverKey))
+	require.NoError(t, err)
+
+	go func() { _, _ = client.Write([]byte("hello")) }()
+	_, err = server.Read(make([]byte, 16))
+	require.ErrorIs(t, err, noise.ErrInvalidMessage)
+}
+
+func TestConnNewErrors(t *testing.T) {
+	t.Parallel()
+	c1, c2 := net.Pipe()
+	defer c1.Close()
+	defer c2.Close()
+	_, err := noise.Client(c1, noise.IK)
+	require.ErrorIs(t, err, noise.ErrMissingStaticKey)
+	_, err = noise.Server(c2, noise.Pattern(42))
+	require.ErrorIs(t, err, noise.ErrInvalidPattern)
+}
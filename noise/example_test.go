@@ -0,0 +1,62 @@
+package noise_test
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/gtkit/encry/noise"
+)
+
+func ExampleClient() {
+	// 服务端长期密钥；客户端预先配置服务端公钥（IK 模式）。
+	serverKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	clientKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+
+	// 实际使用时为 net.Dial / listener.Accept 得到的 TCP 连接。
+	clientRaw, serverRaw := net.Pipe()
+	defer clientRaw.Close()
+	defer serverRaw.Close()
+
+	go func() {
+		server, err := noise.Server(serverRaw, noise.IK, noise.WithStaticKey(serverKey))
+		if err != nil {
+			panic(err)
+		}
+		buf := make([]byte, 5)
+		if _, err := io.ReadFull(server, buf); err != nil {
+			panic(err)
+		}
+		// 握手后按白名单核对客户端身份。
+		if !server.PeerStatic().Equal(clientKey.PublicKey()) {
+			panic("unknown client")
+		}
+		if _, err := server.Write(buf); err != nil {
+			panic(err)
+		}
+	}()
+
+	client, err := noise.Client(clientRaw, noise.IK,
+		noise.WithStaticKey(clientKey), noise.WithPeerStatic(serverKey.PublicKey()))
+	if err != nil {
+		panic(err)
+	}
+	if _, err := client.Write([]byte("hello")); err != nil {
+		panic(err)
+	}
+	echo := make([]byte, 5)
+	if _, err := io.ReadFull(client, echo); err != nil {
+		panic(err)
+	}
+	fmt.Println(string(echo))
+	// Output:
+	// hello
+}
@@ -0,0 +1,157 @@
+// Package noise 实现 Noise Protocol Framework（revision 34）的握手状态机，固定使用
+// 25519_ChaChaPoly_SHA256 套件，支持 XX、IK、NK 三种握手模式。
+//
+// 适用于不便使用 TLS 的服务间通道（如裸 TCP 上的 IoT 设备），替代每次手写的
+// ECDH + HKDF + AEAD 组合。Client/Server 直接把 net.Conn 包装为加密连接：
+//
+//	conn, _ := noise.Client(tcpConn, noise.IK,
+//		noise.WithStaticKey(clientKey), noise.WithPeerStatic(serverPub))
+//	conn.Write([]byte("hello"))
+//
+// 模式选择：
+//   - NK：只认证响应方，发起方预先知道响应方静态公钥，类似单向 TLS。
+//   - IK：双向认证，发起方预先知道响应方静态公钥，一个往返完成握手。
+//   - XX：双向认证，双方事先互不知道公钥，静态公钥在握手中加密传输；握手后须用
+//     PeerStatic 核对对端身份（或用 WithPeerStatic 预先指定期望的公钥）。
+//
+// 需要自行承载握手消息（如放进已有的 RPC 报文）时，使用 NewHandshake 得到的
+// HandshakeState 与握手后的一对 CipherState。
+//
+// 密钥协商、密钥派生分别基于 ecdh（X25519）与 hkdf（HKDF-SHA256，与 Noise 规范中的
+// HKDF 等价），AEAD 为 ChaCha20-Poly1305（IETF，96 位计数器 nonce）。
+package noise
+
+import (
+	"crypto/ecdh"
+	"errors"
+	"io"
+)
+
+const (
+	// MaxMessageSize 为 Noise 单条消息（握手或传输）的最大长度。
+	MaxMessageSize = 65535
+	// TagSize 为每条加密消息附加的认证标签长度。
+	TagSize = 16
+
+	dhLen   = 32
+	hashLen = 32
+	suite   = "25519_ChaChaPoly_SHA256"
+)
+
+var (
+	// ErrInvalidPattern 表示握手模式不是 XX、IK、NK 之一。
+	ErrInvalidPattern = errors.New("noise: invalid handshake pattern")
+	// ErrInvalidKey 表示密钥为 nil 或不是 X25519 密钥。
+	ErrInvalidKey = errors.New("noise: key must be X25519")
+	// ErrMissingStaticKey 表示握手模式需要本方静态私钥但未通过 WithStaticKey 提供。
+	ErrMissingStaticKey = errors.New("noise: pattern requires a local static key")
+	// ErrMissingPeerStatic 表示握手模式需要预知对端静态公钥但未通过 WithPeerStatic 提供。
+	ErrMissingPeerStatic = errors.New("noise: pattern requires the peer static key")
+	// ErrPeerStaticMismatch 表示握手中收到的对端静态公钥与 WithPeerStatic 指定的不一致。
+	ErrPeerStaticMismatch = errors.New("noise: peer static key mismatch")
+	// ErrUnexpectedMessage 表示不是本方发送/接收的轮次，或握手已结束。
+	ErrUnexpectedMessage = errors.New("noise: unexpected handshake message")
+	// ErrHandshakeIncomplete 表示握手尚未完成。
+	ErrHandshakeIncomplete = errors.New("noise: handshake not complete")
+	// ErrHandshakeFailed 表示此前的握手步骤已失败，状态不可再用。
+	ErrHandshakeFailed = errors.New("noise: handshake failed")
+	// ErrInvalidMessage 表示消息长度非法或认证失败（被篡改、密钥不符或顺序错乱）。
+	ErrInvalidMessage = errors.New("noise: invalid message")
+	// ErrMessageTooLarge 表示消息超过 MaxMessageSize。
+	ErrMessageTooLarge = errors.New("noise: message too large")
+	// ErrNonceExhausted 表示 CipherState 的 nonce 已用尽，必须重新握手。
+	ErrNonceExhausted = errors.New("noise: nonce exhausted")
+)
+
+// Pattern 是握手模式。
+type Pattern int
+
+// 支持的握手模式，详见包文档。
+const (
+	XX Pattern = iota + 1
+	IK
+	NK
+)
+
+type token uint8
+
+const (
+	tokenE token = iota
+	tokenS
+	tokenEE
+	tokenES
+	tokenSE
+	tokenSS
+)
+
+type patternDef struct {
+	name string
+	// responderPre 表示响应方静态公钥是预消息（<- s），发起方必须预先知道。
+	responderPre bool
+	// messages 按顺序交替由发起方、响应方发送。
+	messages [][]token
+}
+
+var patterns = map[Pattern]patternDef{
+	XX: {name: "XX", messages: [][]token{
+		{tokenE},
+		{tokenE, tokenEE, tokenS, tokenES},
+		{tokenS, tokenSE},
+	}},
+	IK: {name: "IK", responderPre: true, messages: [][]token{
+		{tokenE, tokenES, tokenS, tokenSS},
+		{tokenE, tokenEE, tokenSE},
+	}},
+	NK: {name: "NK", responderPre: true, messages: [][]token{
+		{tokenE, tokenES},
+		{tokenE, tokenEE},
+	}},
+}
+
+// String 返回模式名，如 "XX"。
+func (p Pattern) String() string {
+	if def, ok := patterns[p]; ok {
+		return def.name
+	}
+	return "invalid"
+}
+
+// ProtocolName 返回完整协议名，如 "Noise_XX_25519_ChaChaPoly_SHA256"。
+func (p Pattern) ProtocolName() string {
+	return "Noise_" + p.String() + "_" + suite
+}
+
+// Option 用于定制握手参数（Functional Options）。
+type Option func(*options)
+
+type options struct {
+	staticKey  *ecdh.PrivateKey
+	peerStatic *ecdh.PublicKey
+	prologue   []byte
+	rand       io.Reader
+}
+
+// WithStaticKey 指定本方 X25519 静态私钥（长期身份密钥）。
+func WithStaticKey(priv *ecdh.PrivateKey) Option {
+	return func(o *options) { o.staticKey = priv }
+}
+
+// WithPeerStatic 指定对端 X25519 静态公钥。
+//
+// IK/NK 的发起方必须提供；对端静态公钥在握手中传输时（XX 双方、IK 响应方），
+// 提供后会校验收到的公钥，不一致返回 ErrPeerStaticMismatch。
+func WithPeerStatic(pub *ecdh.PublicKey) Option {
+	return func(o *options) { o.peerStatic = pub }
+}
+
+// WithPrologue 指定双方预先共享的上下文数据（如协议版本、协商结果），
+// 两端不一致时握手失败，可防止降级攻击。
+func WithPrologue(prologue []byte) Option {
+	return func(o *options) { o.prologue = append([]byte(nil), prologue...) }
+}
+
+// WithRand 指定生成临时密钥的随机源（每个临时私钥读取 32 字节），默认 crypto/rand。
+// 仅用于测试向量等需要可复现握手的场景。
+func WithRand(r io.Reader) Option {
+	return func(o *options) { o.rand = r }
+}
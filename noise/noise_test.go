@@ -0,0 +1,402 @@
+package noise_test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/noise"
+	"github.com/stretchr/testify/require"
+)
+
+type vectorMessage struct {
+	payload, ciphertext []byte
+}
+
+type vector struct {
+	name                     string
+	initStatic, respStatic   []byte
+	initEphemeral, respEphem []byte
+	prologue                 []byte
+	handshakeHash            []byte
+	messages                 []vectorMessage
+	// alternate 为 true 时握手后的传输消息继续按序号奇偶交替收发（cacophony 的约定），
+	// 否则第一条由发起方发出、第二条由响应方发出（flynn/noise 的约定）。
+	alternate bool
+}
+
+// loadCacophony 解析 testdata/cacophony.txt：cacophony（Haskell Noise 实现）发布的
+// vectors/cacophony.txt 中 Noise_{XX,IK,NK}_25519_ChaChaPoly_SHA256 三条向量，格式保持原样（JSON），
+// 取自 github.com/katzenpost/nyquist 随附的副本。
+func loadCacophony(t *testing.T) []vector {
+	t.Helper()
+	data, err := os.ReadFile("testdata/cacophony.txt")
+	require.NoError(t, err)
+
+	var file struct {
+		Vectors []struct {
+			ProtocolName  string `json:"protocol_name"`
+			InitPrologue  string `json:"init_prologue"`
+			InitStatic    string `json:"init_static"`
+			InitEphemeral string `json:"init_ephemeral"`
+			RespPrologue  string `json:"resp_prologue"`
+			RespStatic    string `json:"resp_static"`
+			RespEphemeral string `json:"resp_ephemeral"`
+			HandshakeHash string `json:"handshake_hash"`
+			Messages      []struct {
+				Payload    string `json:"payload"`
+				Ciphertext string `json:"ciphertext"`
+			} `json:"messages"`
+		} `json:"vectors"`
+	}
+	require.NoError(t, json.Unmarshal(data, &file))
+
+	decode := func(s string) []byte {
+		if s == "" {
+			return nil
+		}
+		raw, err := hex.DecodeString(s)
+		require.NoError(t, err)
+		return raw
+	}
+	vectors := make([]vector, 0, len(file.Vectors))
+	for _, fv := range file.Vectors {
+		require.Equal(t, fv.InitPrologue, fv.RespPrologue)
+		v := vector{
+			name:          fv.ProtocolName,
+			initStatic:    decode(fv.InitStatic),
+			respStatic:    decode(fv.RespStatic),
+			initEphemeral: decode(fv.InitEphemeral),
+			respEphem:     decode(fv.RespEphemeral),
+			prologue:      decode(fv.InitPrologue),
+			handshakeHash: decode(fv.HandshakeHash),
+			alternate:     true,
+		}
+		for _, m := range fv.Messages {
+			v.messages = append(v.messages, vectorMessage{payload: decode(m.Payload), ciphertext: decode(m.Ciphertext)})
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors
+}
+
+// loadVectors 解析 testdata/vectors.txt（key=value 行，空行分隔）。
+func loadVectors(t *testing.T) []vector {
+	t.Helper()
+	f, err := os.Open("testdata/vectors.txt")
+	require.NoError(t, err)
+	defer f.Close()
+
+	var vectors []vector
+	var cur *vector
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		require.True(t, ok, line)
+		if key == "handshake" {
+			vectors = append(vectors, vector{name: value})
+			cur = &vectors[len(vectors)-1]
+			continue
+		}
+		raw, err := hex.DecodeString(value)
+		require.NoError(t, err)
+		switch key {
+		case "init_static":
+			cur.initStatic = raw
+		case "resp_static":
+			cur.respStatic = raw
+		case "gen_init_ephemeral":
+			cur.initEphemeral = raw
+		case "gen_resp_ephemeral":
+			cur.respEphem = raw
+		case "prologue":
+			cur.prologue = raw
+		default:
+			idx, field, ok := strings.Cut(strings.TrimPrefix(key, "msg_"), "_")
+			require.True(t, ok, key)
+			i, err := strconv.Atoi(idx)
+			require.NoError(t, err)
+			for len(cur.messages) <= i {
+				cur.messages = append(cur.messages, vectorMessage{})
+			}
+			if field == "payload" {
+				cur.messages[i].payload = raw
+			} else {
+				cur.messages[i].ciphertext = raw
+			}
+		}
+	}
+	require.NoError(t, sc.Err())
+	return vectors
+}
+
+func TestCacophonyVectors(t *testing.T) {
+	t.Parallel()
+	vectors := loadCacophony(t)
+	require.Len(t, vectors, 3)
+
+	for _, v := range vectors {
+		t.Run(v.name, func(t *testing.T) {
+			t.Parallel()
+			runVector(t, v)
+		})
+	}
+}
+
+// flynn/noise 的向量作为第二个独立实现的交叉校验。
+func TestVectors(t *testing.T) {
+	t.Parallel()
+	vectors := loadVectors(t)
+	require.Len(t, vectors, 12)
+
+	for i, v := range vectors {
+		t.Run(v.name+"/"+strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+			runVector(t, v)
+		})
+	}
+}
+
+func runVector(t *testing.T, v vector) {
+	t.Helper()
+	patterns := map[string]noise.Pattern{"XX": noise.XX, "IK": noise.IK, "NK": noise.NK}
+	pattern := patterns[strings.Split(v.name, "_")[1]]
+	require.Equal(t, v.name, pattern.ProtocolName())
+
+	initOpts := []noise.Option{noise.WithPrologue(v.prologue), noise.WithRand(bytes.NewReader(v.initEphemeral))}
+	respOpts := []noise.Option{noise.WithPrologue(v.prologue), noise.WithRand(bytes.NewReader(v.respEphem))}
+	var respStatic *ecdh.PrivateKey
+	if v.initStatic != nil {
+		initOpts = append(initOpts, noise.WithStaticKey(mustX25519(t, v.initStatic)))
+	}
+	if v.respStatic != nil {
+		respStatic = mustX25519(t, v.respStatic)
+		respOpts = append(respOpts, noise.WithStaticKey(respStatic))
+	}
+	if pattern != noise.XX {
+		initOpts = append(initOpts, noise.WithPeerStatic(respStatic.PublicKey()))
+	}
+
+	initiator, err := noise.NewHandshake(pattern, true, initOpts...)
+	require.NoError(t, err)
+	responder, err := noise.NewHandshake(pattern, false, respOpts...)
+	require.NoError(t, err)
+
+	var n int
+	for ; !initiator.Complete(); n++ {
+		writer, reader := initiator, responder
+		if n%2 == 1 {
+			writer, reader = responder, initiator
+		}
+		msg, err := writer.WriteMessage(v.messages[n].payload)
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(v.messages[n].ciphertext), hex.EncodeToString(msg), "message %d", n)
+		payload, err := reader.ReadMessage(msg)
+		require.NoError(t, err)
+		require.Equal(t, hex.EncodeToString(v.messages[n].payload), hex.EncodeToString(payload))
+	}
+	require.True(t, responder.Complete())
+	require.Equal(t, initiator.HandshakeHash(), responder.HandshakeHash())
+	if v.handshakeHash != nil {
+		require.Equal(t, v.handshakeHash, initiator.HandshakeHash())
+	}
+
+	iSend, iRecv, err := initiator.CipherStates()
+	require.NoError(t, err)
+	rSend, rRecv, err := responder.CipherStates()
+	require.NoError(t, err)
+
+	pairs := [][2]*noise.CipherState{{iSend, rRecv}, {rSend, iRecv}}
+	for j, m := range v.messages[n:] {
+		dir := j % 2
+		if v.alternate {
+			dir = (n + j) % 2
+		}
+		ct, err := pairs[dir][0].Encrypt(nil, m.payload)
+		require.NoError(t, err)
+		require.Equal(t, m.ciphertext, ct, "message %d", n+j)
+		pt, err := pairs[dir][1].Decrypt(nil, ct)
+		require.NoError(t, err)
+		require.Equal(t, m.payload, pt)
+	}
+}
+
+func TestHandshakeOptions(t *testing.T) {
+	t.Parallel()
+	server := mustGenerate(t)
+	client := mustGenerate(t)
+	p256, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		pattern   noise.Pattern
+		initiator bool
+		opts      []noise.Option
+		wantErr   error
+	}{
+		{"invalid pattern", noise.Pattern(0), true, nil, noise.ErrInvalidPattern},
+		{"XX initiator without static", noise.XX, true, nil, noise.ErrMissingStaticKey},
+		{"XX responder without static", noise.XX, false, nil, noise.ErrMissingStaticKey},
+		{"IK initiator without peer", noise.IK, true, []noise.Option{noise.WithStaticKey(client)}, noise.ErrMissingPeerStatic},
+		{"NK initiator without peer", noise.NK, true, nil, noise.ErrMissingPeerStatic},
+		{"NK responder without static", noise.NK, false, nil, noise.ErrMissingStaticKey},
+		{"NK initiator", noise.NK, true, []noise.Option{nil, noise.WithPeerStatic(server.PublicKey())}, nil},
+		{"IK responder", noise.IK, false, []noise.Option{noise.WithStaticKey(server)}, nil},
+		{"non-X25519 static", noise.XX, true, []noise.Option{noise.WithStaticKey(p256)}, noise.ErrInvalidKey},
+		{"non-X25519 peer", noise.NK, true, []noise.Option{noise.WithPeerStatic(p256.PublicKey())}, noise.ErrInvalidKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := noise.NewHandshake(tt.pattern, tt.initiator, tt.opts...)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHandshakeMisuse(t *testing.T) {
+	t.Parallel()
+	server := mustGenerate(t)
+	initiator, err := noise.NewHandshake(noise.NK, true, noise.WithPeerStatic(server.PublicKey()))
+	require.NoError(t, err)
+	responder, err := noise.NewHandshake(noise.NK, false, noise.WithStaticKey(server))
+	require.NoError(t, err)
+
+	// 轮次错误。
+	_, err = responder.WriteMessage(nil)
+	require.ErrorIs(t, err, noise.ErrUnexpectedMessage)
+	_, err = initiator.ReadMessage(nil)
+	require.ErrorIs(t, err, noise.ErrUnexpectedMessage)
+	_, _, err = initiator.CipherStates()
+	require.ErrorIs(t, err, noise.ErrHandshakeIncomplete)
+	_, err = initiator.WriteMessage(make([]byte, noise.MaxMessageSize+1))
+	require.ErrorIs(t, err, noise.ErrMessageTooLarge)
+
+	// 篡改后的消息使握手失败，状态作废。
+	msg, err := initiator.WriteMessage([]byte("early data"))
+	require.NoError(t, err)
+	msg[len(msg)-1] ^= 1
+	_, err = responder.ReadMessage(msg)
+	require.ErrorIs(t, err, noise.ErrInvalidMessage)
+	_, err = responder.WriteMessage(nil)
+	require.ErrorIs(t, err, noise.ErrHandshakeFailed)
+
+	// 截断的消息。
+	responder, err = noise.NewHandshake(noise.NK, false, noise.WithStaticKey(server))
+	require.NoError(t, err)
+	_, err = responder.ReadMessage(msg[:31])
+	require.ErrorIs(t, err, noise.ErrInvalidMessage)
+}
+
+func TestHandshakePrologueMismatch(t *testing.T) {
+	t.Parallel()
+	server := mustGenerate(t)
+	initiator, err := noise.NewHandshake(noise.NK, true, noise.WithPeerStatic(server.PublicKey()), noise.WithPrologue([]byte("v1")))
+	require.NoError(t, err)
+	responder, err := noise.NewHandshake(noise.NK, false, noise.WithStaticKey(server), noise.WithPrologue([]byte("v2")))
+	require.NoError(t, err)
+
+	msg, err := initiator.WriteMessage(nil)
+	require.NoError(t, err)
+	_, err = responder.ReadMessage(msg)
+	require.ErrorIs(t, err, noise.ErrInvalidMessage)
+}
+
+func TestHandshakePeerStaticPinning(t *testing.T) {
+	t.Parallel()
+	server := mustGenerate(t)
+	client := mustGenerate(t)
+	other := mustGenerate(t)
+
+	run := func(expected *ecdh.PublicKey) error {
+		initiator, err := noise.NewHandshake(noise.XX, true, noise.WithStaticKey(client), noise.WithPeerStatic(expected))
+		require.NoError(t, err)
+		responder, err := noise.NewHandshake(noise.XX, false, noise.WithStaticKey(server))
+		require.NoError(t, err)
+		msg, err := initiator.WriteMessage(nil)
+		require.NoError(t, err)
+		_, err = responder.ReadMessage(msg)
+		require.NoError(t, err)
+		msg, err = responder.WriteMessage(nil)
+		require.NoError(t, err)
+		_, err = initiator.ReadMessage(msg)
+		return err
+	}
+	require.NoError(t, run(server.PublicKey()))
+	require.ErrorIs(t, run(other.PublicKey()), noise.ErrPeerStaticMismatch)
+}
+
+func TestCipherStateRejectsReplay(t *testing.T) {
+	t.Parallel()
+	server := mustGenerate(t)
+	initiator, err := noise.NewHandshake(noise.NK, true, noise.WithPeerStatic(server.PublicKey()))
+	require.NoError(t, err)
+	responder, err := noise.NewHandshake(noise.NK, false, noise.WithStaticKey(server))
+	require.NoError(t, err)
+	msg, err := initiator.WriteMessage(nil)
+	require.NoError(t, err)
+	_, err = responder.ReadMessage(msg)
+	require.NoError(t, err)
+	msg, err = responder.WriteMessage(nil)
+	require.NoError(t, err)
+	_, err = initiator.ReadMessage(msg)
+	require.NoError(t, err)
+	require.Nil(t, responder.PeerStatic())
+	require.True(t, server.PublicKey().Equal(initiator.PeerStatic()))
+
+	send, _, err := initiator.CipherStates()
+	require.NoError(t, err)
+	_, recv, err := responder.CipherStates()
+	require.NoError(t, err)
+
+	ct1, err := send.Encrypt([]byte("ad"), []byte("one"))
+	require.NoError(t, err)
+	ct2, err := send.Encrypt([]byte("ad"), []byte("two"))
+	require.NoError(t, err)
+
+	// 乱序或错误 ad 均失败，且不推进 nonce。
+	_, err = recv.Decrypt([]byte("ad"), ct2)
+	require.ErrorIs(t, err, noise.ErrInvalidMessage)
+	_, err = recv.Decrypt(nil, ct1)
+	require.ErrorIs(t, err, noise.ErrInvalidMessage)
+	require.Zero(t, recv.Nonce())
+
+	pt, err := recv.Decrypt([]byte("ad"), ct1)
+	require.NoError(t, err)
+	require.Equal(t, []byte("one"), pt)
+	_, err = recv.Decrypt([]byte("ad"), ct1)
+	require.ErrorIs(t, err, noise.ErrInvalidMessage)
+	pt, err = recv.Decrypt([]byte("ad"), ct2)
+	require.NoError(t, err)
+	require.Equal(t, []byte("two"), pt)
+}
+
+func mustX25519(t *testing.T, b []byte) *ecdh.PrivateKey {
+	t.Helper()
+	priv, err := ecdh.X25519().NewPrivateKey(b)
+	require.NoError(t, err)
+	return priv
+}
+
+func mustGenerate(t *testing.T) *ecdh.PrivateKey {
+	t.Helper()
+	priv, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return priv
+}
@@ -0,0 +1,448 @@
+package noise
+
+import (
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/chacha20poly1305"
+
+	encryecdh "github.com/gtkit/encry/ecdh"
+	"github.com/gtkit/encry/hkdf"
+)
+
+// CipherState 是握手完成后的单向传输加密状态（一个密钥 + 递增 nonce）。
+//
+// 每条消息使用下一个 nonce，收发双方必须按相同顺序处理消息；CipherState 不能被多个
+// goroutine 并发使用。
+type CipherState struct {
+	aead cipher.AEAD
+	n    uint64
+}
+
+func newCipherState(key []byte) *CipherState {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		// key 恒为 HKDF 输出的 32 字节。
+		panic(err)
+	}
+	return &CipherState{aead: aead}
+}
+
+// Encrypt 加密 plaintext 并绑定 ad，返回密文（比明文长 TagSize 字节）。
+func (c *CipherState) Encrypt(ad, plaintext []byte) ([]byte, error) {
+	return c.encrypt(nil, ad, plaintext)
+}
+
+// Decrypt 解密并校验 ad；认证失败返回 ErrInvalidMessage，此时 nonce 不前进。
+func (c *CipherState) Decrypt(ad, ciphertext []byte) ([]byte, error) {
+	return c.decrypt(nil, ad, ciphertext)
+}
+
+// Nonce 返回下一条消息将使用的 nonce，即已处理的消息数。
+func (c *CipherState) Nonce() uint64 {
+	return c.n
+}
+
+func (c *CipherState) encrypt(dst, ad, plaintext []byte) ([]byte, error) {
+	// 2^64-1 为规范保留值，不能使用。
+	if c.n == math.MaxUint64 {
+		return nil, ErrNonceExhausted
+	}
+	out := c.aead.Seal(dst, c.nonce(), plaintext, ad)
+	c.n++
+	return out, nil
+}
+
+func (c *CipherState) decrypt(dst, ad, ciphertext []byte) ([]byte, error) {
+	if c.n == math.MaxUint64 {
+		return nil, ErrNonceExhausted
+	}
+	out, err := c.aead.Open(dst, c.nonce(), ciphertext, ad)
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	c.n++
+	return out, nil
+}
+
+// nonce 按规范编码为 4 字节 0 || 8 字节小端计数器。
+func (c *CipherState) nonce() []byte {
+	var nonce [chacha20poly1305.NonceSize]byte
+	binary.LittleEndian.PutUint64(nonce[4:], c.n)
+	return nonce[:]
+}
+
+// symmetricState 对应规范中的 SymmetricState：链式密钥 ck、握手哈希 h 与可选的 CipherState。
+type symmetricState struct {
+	ck []byte
+	h  []byte
+	cs *CipherState
+}
+
+func newSymmetricState(protocolName string) symmetricState {
+	var h []byte
+	if len(protocolName) <= hashLen {
+		h = make([]byte, hashLen)
+		copy(h, protocolName)
+	} else {
+		sum := sha256.Sum256([]byte(protocolName))
+		h = sum[:]
+	}
+	return symmetricState{ck: append([]byte(nil), h...), h: h}
+}
+
+func (s *symmetricState) mixHash(data []byte) {
+	hh := sha256.New()
+	hh.Write(s.h)
+	hh.Write(data)
+	s.h = hh.Sum(s.h[:0])
+}
+
+// mixKey 即规范的 HKDF(ck, ikm, 2)：HKDF-Extract(salt=ck) 后 Expand 64 字节（info 为空）。
+func (s *symmetricState) mixKey(ikm []byte) error {
+	out, err := hkdf.Derive(ikm, s.ck, "", 2*hashLen)
+	if err != nil {
+		return err
+	}
+	s.ck = out[:hashLen]
+	s.cs = newCipherState(out[hashLen:])
+	return nil
+}
+
+func (s *symmetricState) encryptAndHash(dst, plaintext []byte) ([]byte, error) {
+	if s.cs == nil {
+		s.mixHash(plaintext)
+		return append(dst, plaintext...), nil
+	}
+	out, err := s.cs.encrypt(dst, s.h, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	s.mixHash(out[len(dst):])
+	return out, nil
+}
+
+func (s *symmetricState) decryptAndHash(ciphertext []byte) ([]byte, error) {
+	if s.cs == nil {
+		s.mixHash(ciphertext)
+		return append([]byte(nil), ciphertext...), nil
+	}
+	out, err := s.cs.decrypt(nil, s.h, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	s.mixHash(ciphertext)
+	return out, nil
+}
+
+func (s *symmetricState) split() (*CipherState, *CipherState, error) {
+	out, err := hkdf.Derive(nil, s.ck, "", 2*hashLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	return newCipherState(out[:hashLen]), newCipherState(out[hashLen:]), nil
+}
+
+// HandshakeState 是一次 Noise 握手的状态机，双方按模式交替调用 WriteMessage/ReadMessage，
+// 完成后用 CipherStates 取得传输密钥。任一步骤出错后状态即作废。
+type HandshakeState struct {
+	ss        symmetricState
+	pattern   patternDef
+	initiator bool
+
+	s          *ecdh.PrivateKey
+	e          *ecdh.PrivateKey
+	rs         *ecdh.PublicKey
+	re         *ecdh.PublicKey
+	expectedRS *ecdh.PublicKey
+	rand       io.Reader
+
+	index  int
+	failed bool
+	send   *CipherState
+	recv   *CipherState
+}
+
+// NewHandshake 创建握手状态；initiator 为 true 表示本方为发起方（先发送第一条消息）。
+func NewHandshake(pattern Pattern, initiator bool, opts ...Option) (*HandshakeState, error) {
+	def, ok := patterns[pattern]
+	if !ok {
+		return nil, ErrInvalidPattern
+	}
+	o := &options{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	if o.staticKey != nil && o.staticKey.Curve() != ecdh.X25519() {
+		return nil, ErrInvalidKey
+	}
+	if o.peerStatic != nil && o.peerStatic.Curve() != ecdh.X25519() {
+		return nil, ErrInvalidKey
+	}
+	if o.staticKey == nil && def.needsLocalStatic(initiator) {
+		return nil, ErrMissingStaticKey
+	}
+
+	hs := &HandshakeState{
+		ss:        newSymmetricState(pattern.ProtocolName()),
+		pattern:   def,
+		initiator: initiator,
+		s:         o.staticKey,
+		rand:      o.rand,
+	}
+	if initiator && def.responderPre {
+		if o.peerStatic == nil {
+			return nil, ErrMissingPeerStatic
+		}
+		hs.rs = o.peerStatic
+	} else {
+		hs.expectedRS = o.peerStatic
+	}
+
+	hs.ss.mixHash(o.prologue)
+	if def.responderPre {
+		if initiator {
+			hs.ss.mixHash(hs.rs.Bytes())
+		} else {
+			hs.ss.mixHash(hs.s.PublicKey().Bytes())
+		}
+	}
+	return hs, nil
+}
+
+// needsLocalStatic 报告本方是否需要静态私钥：作为预消息公开或在握手中发送。
+func (def patternDef) needsLocalStatic(initiator bool) bool {
+	if !initiator && def.responderPre {
+		return true
+	}
+	for i, msg := range def.messages {
+		if (i%2 == 0) != initiator {
+			continue
+		}
+		for _, t := range msg {
+			if t == tokenS {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteMessage 生成本方的下一条握手消息，payload 在可加密时被加密（NK/IK/XX 首条消息中
+// 负载的保密性与前向安全性较弱，详见 Noise 规范第 7.7 节），返回待发送的消息。
+func (hs *HandshakeState) WriteMessage(payload []byte) ([]byte, error) {
+	if err := hs.checkTurn(true); err != nil {
+		return nil, err
+	}
+	if len(payload) > MaxMessageSize {
+		return nil, ErrMessageTooLarge
+	}
+	msg, err := hs.writeMessage(payload)
+	if err != nil {
+		hs.failed = true
+		return nil, err
+	}
+	return msg, hs.advance()
+}
+
+// ReadMessage 处理对端的下一条握手消息，返回其中的负载。
+func (hs *HandshakeState) ReadMessage(message []byte) ([]byte, error) {
+	if err := hs.checkTurn(false); err != nil {
+		return nil, err
+	}
+	if len(message) > MaxMessageSize {
+		hs.failed = true
+		return nil, ErrMessageTooLarge
+	}
+	payload, err := hs.readMessage(message)
+	if err != nil {
+		hs.failed = true
+		return nil, err
+	}
+	return payload, hs.advance()
+}
+
+// Complete 报告握手是否已完成。
+func (hs *HandshakeState) Complete() bool {
+	return hs.send != nil
+}
+
+// WriteTurn 报告下一步是否轮到本方发送（握手完成后恒为 false）。
+func (hs *HandshakeState) WriteTurn() bool {
+	return !hs.Complete() && (hs.index%2 == 0) == hs.initiator
+}
+
+// CipherStates 返回握手完成后的 (发送, 接收) CipherState。
+func (hs *HandshakeState) CipherStates() (send, recv *CipherState, err error) {
+	if !hs.Complete() {
+		return nil, nil, ErrHandshakeIncomplete
+	}
+	return hs.send, hs.recv, nil
+}
+
+// HandshakeHash 返回握手哈希 h，完成后可作为通道绑定值（如再对其签名）。
+func (hs *HandshakeState) HandshakeHash() []byte {
+	return append([]byte(nil), hs.ss.h...)
+}
+
+// PeerStatic 返回对端静态公钥；尚未收到或对端在该模式中没有静态密钥（NK 的响应方）时为 nil。
+func (hs *HandshakeState) PeerStatic() *ecdh.PublicKey {
+	return hs.rs
+}
+
+func (hs *HandshakeState) checkTurn(write bool) error {
+	if hs.failed {
+		return ErrHandshakeFailed
+	}
+	if hs.Complete() || hs.WriteTurn() != write {
+		return ErrUnexpectedMessage
+	}
+	return nil
+}
+
+func (hs *HandshakeState) writeMessage(payload []byte) ([]byte, error) {
+	msg := make([]byte, 0, 2*dhLen+2*TagSize+len(payload))
+	var err error
+	for _, t := range hs.pattern.messages[hs.index] {
+		switch t {
+		case tokenE:
+			if hs.e, err = hs.generateEphemeral(); err != nil {
+				return nil, err
+			}
+			pub := hs.e.PublicKey().Bytes()
+			msg = append(msg, pub...)
+			hs.ss.mixHash(pub)
+		case tokenS:
+			if msg, err = hs.ss.encryptAndHash(msg, hs.s.PublicKey().Bytes()); err != nil {
+				return nil, err
+			}
+		default:
+			if err = hs.mixDH(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if msg, err = hs.ss.encryptAndHash(msg, payload); err != nil {
+		return nil, err
+	}
+	if len(msg) > MaxMessageSize {
+		return nil, ErrMessageTooLarge
+	}
+	return msg, nil
+}
+
+func (hs *HandshakeState) readMessage(message []byte) ([]byte, error) {
+	for _, t := range hs.pattern.messages[hs.index] {
+		switch t {
+		case tokenE:
+			if len(message) < dhLen {
+				return nil, ErrInvalidMessage
+			}
+			re, err := encryecdh.ParsePublicKey(ecdh.X25519(), message[:dhLen])
+			if err != nil {
+				return nil, errors.Join(ErrInvalidMessage, err)
+			}
+			hs.re = re
+			hs.ss.mixHash(message[:dhLen])
+			message = message[dhLen:]
+		case tokenS:
+			n := dhLen
+			if hs.ss.cs != nil {
+				n += TagSize
+			}
+			if len(message) < n {
+				return nil, ErrInvalidMessage
+			}
+			raw, err := hs.ss.decryptAndHash(message[:n])
+			if err != nil {
+				return nil, err
+			}
+			rs, err := encryecdh.ParsePublicKey(ecdh.X25519(), raw)
+			if err != nil {
+				return nil, errors.Join(ErrInvalidMessage, err)
+			}
+			if hs.expectedRS != nil && !hs.expectedRS.Equal(rs) {
+				return nil, ErrPeerStaticMismatch
+			}
+			hs.rs = rs
+			message = message[n:]
+		default:
+			if err := hs.mixDH(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if hs.ss.cs != nil && len(message) < TagSize {
+		return nil, ErrInvalidMessage
+	}
+	return hs.ss.decryptAndHash(message)
+}
+
+func (hs *HandshakeState) mixDH(t token) error {
+	var priv *ecdh.PrivateKey
+	var pub *ecdh.PublicKey
+	switch t {
+	case tokenEE:
+		priv, pub = hs.e, hs.re
+	case tokenES:
+		if hs.initiator {
+			priv, pub = hs.e, hs.rs
+		} else {
+			priv, pub = hs.s, hs.re
+		}
+	case tokenSE:
+		if hs.initiator {
+			priv, pub = hs.s, hs.re
+		} else {
+			priv, pub = hs.e, hs.rs
+		}
+	case tokenSS:
+		priv, pub = hs.s, hs.rs
+	}
+	shared, err := encryecdh.SharedSecret(priv, pub)
+	if err != nil {
+		// 对端公钥为小阶点时 X25519 输出全零，crypto/ecdh 返回错误。
+		return errors.Join(ErrInvalidMessage, err)
+	}
+	defer clear(shared)
+	return hs.ss.mixKey(shared)
+}
+
+func (hs *HandshakeState) generateEphemeral() (*ecdh.PrivateKey, error) {
+	if hs.rand == nil {
+		return encryecdh.GenerateX25519()
+	}
+	seed := make([]byte, encryecdh.X25519SeedSize)
+	defer clear(seed)
+	if _, err := io.ReadFull(hs.rand, seed); err != nil {
+		return nil, err
+	}
+	return encryecdh.X25519FromSeed(seed)
+}
+
+func (hs *HandshakeState) advance() error {
+	hs.index++
+	if hs.index < len(hs.pattern.messages) {
+		return nil
+	}
+	c1, c2, err := hs.ss.split()
+	if err != nil {
+		hs.failed = true
+		return err
+	}
+	if hs.initiator {
+		hs.send, hs.recv = c1, c2
+	} else {
+		hs.send, hs.recv = c2, c1
+	}
+	// 握手完成后临时私钥不再需要。
+	hs.e = nil
+	return nil
+}
@@ -0,0 +1,379 @@
+// Package blind 提供 RFC 9474 RSA 盲签名（RSABSSA-SHA384-PSS 系列）。
+//
+// 盲签名让签名方在看不到消息内容的前提下完成签名，适合 Privacy Pass 风格的匿名令牌：
+//
+//  1. 客户端 Prepare 预处理消息，再用签名方公钥 Blind 得到 (blindedMsg, inv)；
+//  2. 把 blindedMsg 发给签名方，签名方用私钥 BlindSign 返回 blindSig；
+//  3. 客户端用 inv Finalize 去盲，得到对 prepared 消息的标准 RSA-PSS 签名；
+//  4. 任何持有公钥的一方都可以 Verify(prepared, sig)。
+//
+// 签名方无法把 blindedMsg/blindSig 与最终 (prepared, sig) 关联起来。inv 是一次性的
+// 去盲因子，须与 blindedMsg 一一对应且不得复用或泄露。
+//
+// 默认推荐 SHA384PSSRandomized：Prepare 会在消息前加 32 字节随机前缀，防止签名方
+// 通过选择消息把签名与具体消息关联。
+package blind
+
+import (
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	"crypto/sha512"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Variant 表示 RFC 9474 定义的 RSABSSA 变体。
+type Variant int
+
+const (
+	// SHA384PSSRandomized 为 RSABSSA-SHA384-PSS-Randomized（推荐默认）。
+	SHA384PSSRandomized Variant = iota
+	// SHA384PSSZeroRandomized 为 RSABSSA-SHA384-PSSZERO-Randomized。
+	SHA384PSSZeroRandomized
+	// SHA384PSSDeterministic 为 RSABSSA-SHA384-PSS-Deterministic。
+	SHA384PSSDeterministic
+	// SHA384PSSZeroDeterministic 为 RSABSSA-SHA384-PSSZERO-Deterministic。
+	SHA384PSSZeroDeterministic
+)
+
+// msgPrefixSize 是随机化变体在消息前附加的随机前缀长度。
+const msgPrefixSize = 32
+
+var (
+	// ErrInvalidVariant 表示未知的 RSABSSA 变体。
+	ErrInvalidVariant = errors.New("blind: invalid variant")
+	// ErrInvalidPrivateKey 表示私钥为 nil 或结构不完整。
+	ErrInvalidPrivateKey = errors.New("blind: invalid private key")
+	// ErrInvalidPublicKey 表示公钥为 nil 或参数非法。
+	ErrInvalidPublicKey = errors.New("blind: invalid public key")
+	// ErrMessageTooLong 表示盲化消息/签名长度与模数不符或数值不小于模数。
+	ErrMessageTooLong = errors.New("blind: message representative out of range")
+	// ErrEncoding 表示 PSS 编码失败（模数过短或消息与模数不互素）。
+	ErrEncoding = errors.New("blind: encoding error")
+	// ErrInvalidInverse 表示去盲因子 inv 非法（长度与模数不符、为 0 或不小于模数）。
+	ErrInvalidInverse = errors.New("blind: invalid blinding inverse")
+	// ErrSigningFailure 表示签名结果自检失败（可能为故障注入或密钥损坏）。
+	ErrSigningFailure = errors.New("blind: signing failure")
+	// ErrVerification 表示 Finalize 得到的签名未通过校验。
+	ErrVerification = errors.New("blind: verification failed")
+)
+
+// String 返回 RFC 9474 中的变体名称。
+func (v Variant) String() string {
+	switch v {
+	case SHA384PSSRandomized:
+		return "RSABSSA-SHA384-PSS-Randomized"
+	case SHA384PSSZeroRandomized:
+		return "RSABSSA-SHA384-PSSZERO-Randomized"
+	case SHA384PSSDeterministic:
+		return "RSABSSA-SHA384-PSS-Deterministic"
+	case SHA384PSSZeroDeterministic:
+		return "RSABSSA-SHA384-PSSZERO-Deterministic"
+	default:
+		return fmt.Sprintf("Variant(%d)", int(v))
+	}
+}
+
+func (v Variant) valid() bool {
+	return v >= SHA384PSSRandomized && v <= SHA384PSSZeroDeterministic
+}
+
+func (v Variant) saltLen() int {
+	if v == SHA384PSSRandomized || v == SHA384PSSDeterministic {
+		return sha512.Size384
+	}
+	return 0
+}
+
+func (v Variant) randomized() bool {
+	return v == SHA384PSSRandomized || v == SHA384PSSZeroRandomized
+}
+
+// Prepare 按变体预处理消息：随机化变体返回 32 字节随机前缀 || msg，确定性变体原样返回副本。
+// 后续 Blind/Finalize/Verify 都必须使用 Prepare 的返回值而非原始 msg。
+func Prepare(v Variant, msg []byte) ([]byte, error) {
+	if !v.valid() {
+		return nil, ErrInvalidVariant
+	}
+	if !v.randomized() {
+		return append([]byte(nil), msg...), nil
+	}
+	prepared := make([]byte, msgPrefixSize+len(msg))
+	if _, err := io.ReadFull(rand.Reader, prepared[:msgPrefixSize]); err != nil {
+		return nil, err
+	}
+	copy(prepared[msgPrefixSize:], msg)
+	return prepared, nil
+}
+
+// Blind 用签名方公钥盲化 prepared 消息，返回待发送给签名方的 blindedMsg
+// 与客户端私有保存的去盲因子 inv（两者长度均为模数字节长度）。
+func Blind(v Variant, pub *stdrsa.PublicKey, prepared []byte) (blindedMsg, inv []byte, err error) {
+	if !v.valid() {
+		return nil, nil, ErrInvalidVariant
+	}
+	if err := checkPublicKey(pub); err != nil {
+		return nil, nil, err
+	}
+
+	salt := make([]byte, v.saltLen())
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, nil, err
+	}
+	r, err := randomUnit(pub.N)
+	if err != nil {
+		return nil, nil, err
+	}
+	return blind(pub, prepared, salt, r)
+}
+
+// BlindSign 用私钥对 blindedMsg 签名，返回 blindSig。签名方看不到原始消息。
+func BlindSign(priv *stdrsa.PrivateKey, blindedMsg []byte) ([]byte, error) {
+	if priv == nil || priv.D == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	if err := checkPublicKey(&priv.PublicKey); err != nil {
+		return nil, err
+	}
+	k := modulusLen(&priv.PublicKey)
+	if len(blindedMsg) != k {
+		return nil, ErrMessageTooLong
+	}
+	m := new(big.Int).SetBytes(blindedMsg)
+	if m.Cmp(priv.N) >= 0 {
+		return nil, ErrMessageTooLong
+	}
+
+	s, err := signRaw(priv, m)
+	if err != nil {
+		return nil, err
+	}
+	// RFC 9474 §4.3：用公钥回验，防止故障攻击泄露私钥。
+	if encrypt(&priv.PublicKey, s).Cmp(m) != 0 {
+		return nil, ErrSigningFailure
+	}
+	return s.FillBytes(make([]byte, k)), nil
+}
+
+// Finalize 用 inv 对 blindSig 去盲，并校验得到的签名；返回对 prepared 的 RSA-PSS 签名。
+func Finalize(v Variant, pub *stdrsa.PublicKey, prepared, blindSig, inv []byte) ([]byte, error) {
+	if !v.valid() {
+		return nil, ErrInvalidVariant
+	}
+	if err := checkPublicKey(pub); err != nil {
+		return nil, err
+	}
+	k := modulusLen(pub)
+	if len(blindSig) != k {
+		return nil, ErrMessageTooLong
+	}
+	z := new(big.Int).SetBytes(blindSig)
+	if z.Cmp(pub.N) >= 0 {
+		return nil, ErrMessageTooLong
+	}
+	if len(inv) != k {
+		return nil, ErrInvalidInverse
+	}
+	rInv := new(big.Int).SetBytes(inv)
+	if rInv.Sign() == 0 || rInv.Cmp(pub.N) >= 0 {
+		return nil, ErrInvalidInverse
+	}
+
+	s := new(big.Int).Mul(z, rInv)
+	s.Mod(s, pub.N)
+	sig := s.FillBytes(make([]byte, k))
+
+	ok, err := Verify(v, pub, prepared, sig)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrVerification
+	}
+	return sig, nil
+}
+
+// Verify 校验 sig 是否为 prepared 的有效签名，返回 (是否有效, 操作性错误)。
+// 按变体严格要求盐长（PSS 为 48 字节，PSSZERO 为 0）。
+func Verify(v Variant, pub *stdrsa.PublicKey, prepared, sig []byte) (bool, error) {
+	if !v.valid() {
+		return false, ErrInvalidVariant
+	}
+	if err := checkPublicKey(pub); err != nil {
+		return false, err
+	}
+	k := modulusLen(pub)
+	if len(sig) != k {
+		return false, nil
+	}
+	s := new(big.Int).SetBytes(sig)
+	if s.Cmp(pub.N) >= 0 {
+		return false, nil
+	}
+
+	emBits := pub.N.BitLen() - 1
+	emLen := (emBits + 7) / 8
+	m := encrypt(pub, s)
+	if m.BitLen() > emBits {
+		return false, nil
+	}
+	em := m.FillBytes(make([]byte, emLen))
+
+	salt, ok := recoverSalt(em, emBits, v.saltLen())
+	if !ok {
+		return false, nil
+	}
+	want, err := emsaPSSEncode(prepared, emBits, salt)
+	if err != nil {
+		return false, nil //nolint:nilerr // 编码失败即签名无效，不属于操作性错误。
+	}
+	return subtle.ConstantTimeCompare(em, want) == 1, nil
+}
+
+// blind 是 Blind 的确定性内核：salt 与随机因子 r 由调用方提供（测试向量复现用）。
+func blind(pub *stdrsa.PublicKey, prepared, salt []byte, r *big.Int) (blindedMsg, inv []byte, err error) {
+	emBits := pub.N.BitLen() - 1
+	em, err := emsaPSSEncode(prepared, emBits, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	m := new(big.Int).SetBytes(em)
+	if new(big.Int).GCD(nil, nil, m, pub.N).Cmp(big.NewInt(1)) != 0 {
+		return nil, nil, ErrEncoding
+	}
+
+	rInv := new(big.Int).ModInverse(r, pub.N)
+	if rInv == nil {
+		return nil, nil, ErrInvalidInverse
+	}
+	z := encrypt(pub, r)
+	z.Mul(z, m)
+	z.Mod(z, pub.N)
+
+	k := modulusLen(pub)
+	return z.FillBytes(make([]byte, k)), rInv.FillBytes(make([]byte, k)), nil
+}
+
+// emsaPSSEncode 实现 RFC 8017 §9.1.1 EMSA-PSS-ENCODE（SHA-384 + MGF1-SHA384）。
+func emsaPSSEncode(msg []byte, emBits int, salt []byte) ([]byte, error) {
+	const hLen = sha512.Size384
+	emLen := (emBits + 7) / 8
+	if emLen < hLen+len(salt)+2 {
+		return nil, ErrEncoding
+	}
+
+	mHash := sha512.Sum384(msg)
+	h := sha512.New384()
+	h.Write(make([]byte, 8))
+	h.Write(mHash[:])
+	h.Write(salt)
+	hSum := h.Sum(nil)
+
+	em := make([]byte, emLen)
+	db := em[:emLen-hLen-1]
+	db[len(db)-len(salt)-1] = 0x01
+	copy(db[len(db)-len(salt):], salt)
+	mgf1XOR(db, hSum)
+	db[0] &= 0xff >> (8*emLen - emBits)
+
+	copy(em[emLen-hLen-1:], hSum)
+	em[emLen-1] = 0xbc
+	return em, nil
+}
+
+// recoverSalt 从编码消息 em 中解出长度为 saltLen 的盐；格式不符时返回 false。
+func recoverSalt(em []byte, emBits, saltLen int) ([]byte, bool) {
+	const hLen = sha512.Size384
+	emLen := len(em)
+	if emLen < hLen+saltLen+2 || em[emLen-1] != 0xbc {
+		return nil, false
+	}
+	db := append([]byte(nil), em[:emLen-hLen-1]...)
+	mgf1XOR(db, em[emLen-hLen-1:emLen-1])
+	db[0] &= 0xff >> (8*emLen - emBits)
+
+	psLen := len(db) - saltLen - 1
+	for _, b := range db[:psLen] {
+		if b != 0 {
+			return nil, false
+		}
+	}
+	if db[psLen] != 0x01 {
+		return nil, false
+	}
+	return db[psLen+1:], true
+}
+
+// mgf1XOR 用 MGF1-SHA384(seed) 生成的掩码异或 out。
+func mgf1XOR(out, seed []byte) {
+	var counter [4]byte
+	h := sha512.New384()
+	for done := 0; done < len(out); {
+		h.Reset()
+		h.Write(seed)
+		h.Write(counter[:])
+		digest := h.Sum(nil)
+		for i := 0; i < len(digest) && done < len(out); i++ {
+			out[done] ^= digest[i]
+			done++
+		}
+		for i := 3; i >= 0; i-- {
+			counter[i]++
+			if counter[i] != 0 {
+				break
+			}
+		}
+	}
+}
+
+// signRaw 计算 m^d mod n。math/big 不是常数时间实现，这里额外做一次随机盲化
+// （(m·r^e)^d·r^-1），避免签名方私钥运算的耗时与攻击者可控输入相关。
+func signRaw(priv *stdrsa.PrivateKey, m *big.Int) (*big.Int, error) {
+	r, err := randomUnit(priv.N)
+	if err != nil {
+		return nil, err
+	}
+	rInv := new(big.Int).ModInverse(r, priv.N)
+	if rInv == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+
+	c := encrypt(&priv.PublicKey, r)
+	c.Mul(c, m)
+	c.Mod(c, priv.N)
+	s := new(big.Int).Exp(c, priv.D, priv.N)
+	s.Mul(s, rInv)
+	return s.Mod(s, priv.N), nil
+}
+
+// randomUnit 返回 [1, n) 中与 n 互素的随机数。
+func randomUnit(n *big.Int) (*big.Int, error) {
+	one := big.NewInt(1)
+	for {
+		r, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			return nil, err
+		}
+		if r.Sign() > 0 && new(big.Int).GCD(nil, nil, r, n).Cmp(one) == 0 {
+			return r, nil
+		}
+	}
+}
+
+func encrypt(pub *stdrsa.PublicKey, m *big.Int) *big.Int {
+	return new(big.Int).Exp(m, big.NewInt(int64(pub.E)), pub.N)
+}
+
+func modulusLen(pub *stdrsa.PublicKey) int {
+	return (pub.N.BitLen() + 7) / 8
+}
+
+func checkPublicKey(pub *stdrsa.PublicKey) error {
+	if pub == nil || pub.N == nil || pub.N.Sign() <= 0 || pub.E < 3 || pub.E%2 == 0 {
+		return ErrInvalidPublicKey
+	}
+	return nil
+}
@@ -0,0 +1,239 @@
+package blind_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/gtkit/encry/rsa/blind"
+	"github.com/stretchr/testify/require"
+)
+
+// hexBytes 把测试向量中的十六进制字符串解码为字节。
+type hexBytes []byte
+
+func (h *hexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	raw, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*h = raw
+	return nil
+}
+
+// rfcVector 对应 RFC 9474 附录 A 的一组测试向量。
+type rfcVector struct {
+	Name       string   `json:"name"`
+	P          hexBytes `json:"p"`
+	Q          hexBytes `json:"q"`
+	N          hexBytes `json:"n"`
+	E          hexBytes `json:"e"`
+	D          hexBytes `json:"d"`
+	Msg        hexBytes `json:"msg"`
+	MsgPrefix  hexBytes `json:"msg_prefix"`
+	InputMsg   hexBytes `json:"input_msg"`
+	Salt       hexBytes `json:"salt"`
+	Inv        hexBytes `json:"inv"`
+	BlindedMsg hexBytes `json:"blinded_msg"`
+	BlindSig   hexBytes `json:"blind_sig"`
+	Sig        hexBytes `json:"sig"`
+}
+
+func (v rfcVector) privateKey() *stdrsa.PrivateKey {
+	priv := &stdrsa.PrivateKey{
+		PublicKey: stdrsa.PublicKey{
+			N: new(big.Int).SetBytes(v.N),
+			E: int(new(big.Int).SetBytes(v.E).Int64()),
+		},
+		D:      new(big.Int).SetBytes(v.D),
+		Primes: []*big.Int{new(big.Int).SetBytes(v.P), new(big.Int).SetBytes(v.Q)},
+	}
+	return priv
+}
+
+var variantsByName = map[string]blind.Variant{
+	"RSABSSA-SHA384-PSS-Randomized":        blind.SHA384PSSRandomized,
+	"RSABSSA-SHA384-PSSZERO-Randomized":    blind.SHA384PSSZeroRandomized,
+	"RSABSSA-SHA384-PSS-Deterministic":     blind.SHA384PSSDeterministic,
+	"RSABSSA-SHA384-PSSZERO-Deterministic": blind.SHA384PSSZeroDeterministic,
+}
+
+func TestRFC9474Vectors(t *testing.T) {
+	raw, err := os.ReadFile("testdata/rfc9474.json")
+	require.NoError(t, err)
+	var vectors []rfcVector
+	require.NoError(t, json.Unmarshal(raw, &vectors))
+	require.Len(t, vectors, 4)
+
+	for _, vec := range vectors {
+		t.Run(vec.Name, func(t *testing.T) {
+			v, ok := variantsByName[vec.Name]
+			require.True(t, ok)
+			require.Equal(t, vec.Name, v.String())
+			require.Equal(t, append(append([]byte(nil), vec.MsgPrefix...), vec.Msg...), []byte(vec.InputMsg))
+
+			priv := vec.privateKey()
+			pub := &priv.PublicKey
+			r := new(big.Int).ModInverse(new(big.Int).SetBytes(vec.Inv), pub.N)
+			require.NotNil(t, r)
+
+			blindedMsg, inv, err := blind.BlindWith(pub, vec.InputMsg, vec.Salt, r)
+			require.NoError(t, err)
+			require.Equal(t, []byte(vec.BlindedMsg), blindedMsg)
+
+			blindSig, err := blind.BlindSign(priv, blindedMsg)
+			require.NoError(t, err)
+			require.Equal(t, []byte(vec.BlindSig), blindSig)
+
+			sig, err := blind.Finalize(v, pub, vec.InputMsg, blindSig, inv)
+			require.NoError(t, err)
+			require.Equal(t, []byte(vec.Sig), sig)
+
+			ok, err = blind.Verify(v, pub, vec.InputMsg, vec.Sig)
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+	}
+}
+
+func TestBlindSignRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	priv, err := stdrsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pub := &priv.PublicKey
+
+	tests := []struct {
+		name    string
+		variant blind.Variant
+	}{
+		{"pss randomized", blind.SHA384PSSRandomized},
+		{"psszero randomized", blind.SHA384PSSZeroRandomized},
+		{"pss deterministic", blind.SHA384PSSDeterministic},
+		{"psszero deterministic", blind.SHA384PSSZeroDeterministic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			prepared, err := blind.Prepare(tt.variant, []byte("rate-limit-token"))
+			require.NoError(t, err)
+
+			blindedMsg, inv, err := blind.Blind(tt.variant, pub, prepared)
+			require.NoError(t, err)
+			blindSig, err := blind.BlindSign(priv, blindedMsg)
+			require.NoError(t, err)
+			sig, err := blind.Finalize(tt.variant, pub, prepared, blindSig, inv)
+			require.NoError(t, err)
+
+			ok, err := blind.Verify(tt.variant, pub, prepared, sig)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			// 签名方看到的 blindedMsg/blindSig 与最终签名不同。
+			require.False(t, bytes.Equal(blindSig, sig))
+
+			ok, err = blind.Verify(tt.variant, pub, []byte("other"), sig)
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	t.Parallel()
+
+	msg := []byte("msg")
+	a, err := blind.Prepare(blind.SHA384PSSRandomized, msg)
+	require.NoError(t, err)
+	b, err := blind.Prepare(blind.SHA384PSSRandomized, msg)
+	require.NoError(t, err)
+	require.Len(t, a, 32+len(msg))
+	require.Equal(t, msg, a[32:])
+	require.NotEqual(t, a, b)
+
+	d, err := blind.Prepare(blind.SHA384PSSDeterministic, msg)
+	require.NoError(t, err)
+	require.Equal(t, msg, d)
+
+	_, err = blind.Prepare(blind.Variant(99), msg)
+	require.ErrorIs(t, err, blind.ErrInvalidVariant)
+}
+
+func TestVerifySaltLengthIsStrict(t *testing.T) {
+	t.Parallel()
+
+	priv, err := stdrsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pub := &priv.PublicKey
+
+	prepared := []byte("strict-salt")
+	blindedMsg, inv, err := blind.Blind(blind.SHA384PSSDeterministic, pub, prepared)
+	require.NoError(t, err)
+	blindSig, err := blind.BlindSign(priv, blindedMsg)
+	require.NoError(t, err)
+	sig, err := blind.Finalize(blind.SHA384PSSDeterministic, pub, prepared, blindSig, inv)
+	require.NoError(t, err)
+
+	// 48 字节盐的签名不能当作 PSSZERO 签名通过。
+	ok, err := blind.Verify(blind.SHA384PSSZeroDeterministic, pub, prepared, sig)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	priv, err := stdrsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pub := &priv.PublicKey
+	v := blind.SHA384PSSRandomized
+
+	_, _, err = blind.Blind(v, nil, []byte("m"))
+	require.ErrorIs(t, err, blind.ErrInvalidPublicKey)
+	_, _, err = blind.Blind(blind.Variant(-1), pub, []byte("m"))
+	require.ErrorIs(t, err, blind.ErrInvalidVariant)
+
+	_, err = blind.BlindSign(nil, []byte("m"))
+	require.ErrorIs(t, err, blind.ErrInvalidPrivateKey)
+	_, err = blind.BlindSign(priv, []byte("short"))
+	require.ErrorIs(t, err, blind.ErrMessageTooLong)
+	_, err = blind.BlindSign(priv, bytes.Repeat([]byte{0xff}, 256))
+	require.ErrorIs(t, err, blind.ErrMessageTooLong)
+
+	blindedMsg, inv, err := blind.Blind(v, pub, []byte("m"))
+	require.NoError(t, err)
+	blindSig, err := blind.BlindSign(priv, blindedMsg)
+	require.NoError(t, err)
+
+	_, err = blind.Finalize(v, pub, []byte("other"), blindSig, inv)
+	require.ErrorIs(t, err, blind.ErrVerification)
+	for name, bad := range map[string][]byte{
+		"空":     nil,
+		"截断":    inv[1:],
+		"过长":    append([]byte{0}, inv...),
+		"为零":    make([]byte, len(inv)),
+		"不小于模数": pub.N.Bytes(),
+	} {
+		_, err = blind.Finalize(v, pub, []byte("m"), blindSig, bad)
+		require.ErrorIs(t, err, blind.ErrInvalidInverse, name)
+	}
+	_, err = blind.Finalize(v, pub, []byte("m"), blindSig[1:], inv)
+	require.ErrorIs(t, err, blind.ErrMessageTooLong)
+
+	ok, err := blind.Verify(v, nil, []byte("m"), blindSig)
+	require.ErrorIs(t, err, blind.ErrInvalidPublicKey)
+	require.False(t, ok)
+	ok, err = blind.Verify(v, pub, []byte("m"), []byte("short"))
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,44 @@
+package blind_test
+
+import (
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	"fmt"
+
+	"github.com/gtkit/encry/rsa/blind"
+)
+
+func ExampleBlindSign() {
+	// 签名方：持有私钥，对外公布公钥。
+	priv, err := stdrsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	pub := &priv.PublicKey
+	v := blind.SHA384PSSRandomized
+
+	// 客户端：预处理并盲化令牌，只把 blindedMsg 发给签名方。
+	prepared, err := blind.Prepare(v, []byte("token"))
+	if err != nil {
+		panic(err)
+	}
+	blindedMsg, inv, err := blind.Blind(v, pub, prepared)
+	if err != nil {
+		panic(err)
+	}
+
+	// 签名方：对看不到内容的 blindedMsg 签名。
+	blindSig, err := blind.BlindSign(priv, blindedMsg)
+	if err != nil {
+		panic(err)
+	}
+
+	// 客户端：去盲得到标准签名，之后任何人都能用公钥验证。
+	sig, err := blind.Finalize(v, pub, prepared, blindSig, inv)
+	if err != nil {
+		panic(err)
+	}
+	ok, _ := blind.Verify(v, pub, prepared, sig)
+	fmt.Println(ok)
+	// Output: true
+}
@@ -0,0 +1,4 @@
+package blind
+
+// BlindWith 暴露确定性盲化内核，供测试向量固定 salt 与随机因子。
+var BlindWith = blind
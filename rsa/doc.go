@@ -1,3 +1,11 @@
 // Package rsa 提供 RSA 加密（OAEP）与签名（PSS），
 // 含密钥生成、PEM 读写与解析。新系统请使用 OAEP/PSS。
+//
+// 加载来自外部的密钥时，可用 KeyPolicy（如 DefaultKeyPolicy）检查模数位数、公钥指数、
+// ROCA 指纹与弱素数；其 Read*/Parse*/*WithPublicKey 方法以及按文件路径读取密钥的
+// EncryptOAEP*/DecryptOAEPWithOptions/SignPSSWithOptions/VerifyPSS* 方法在原函数基础上
+// 强制执行策略，不满足时返回 *PolicyError 列出违反的规则。
+//
+// VerifyPKCS1v15 / SignPKCS1v15（Deprecated）是与 OAEP/PSS 隔离的遗留签名 API，
+// 仅用于对接仍使用 SHA1withRSA / SHA256withRSA 的支付网关等外部系统。
 package rsa
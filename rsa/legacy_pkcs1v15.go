@@ -0,0 +1,67 @@
+package rsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	"crypto/sha1" // #nosec G505 -- SHA1withRSA 仅用于兼容遗留支付网关回调验签.
+	"encoding/base64"
+)
+
+// 本文件是与 OAEP/PSS 隔离的遗留 PKCS#1 v1.5 签名 API，仅为对接仍使用
+// SHA1withRSA / SHA256withRSA（v1.5）的支付宝、微信支付、银行回调等外部系统保留。
+// 新协议请使用 PSS；本仓库不提供 PKCS#1 v1.5 加密。
+
+// VerifyPKCS1v15 使用已解析公钥执行 RSASSA-PKCS1-v1_5 验签，返回 (是否有效, 操作性错误).
+// hash 支持 SHA-1、SHA-224、SHA-256、SHA-384、SHA-512.
+func VerifyPKCS1v15(publicKey *stdrsa.PublicKey, plainText, signature []byte, hash crypto.Hash) (bool, error) {
+	if publicKey == nil {
+		return false, ErrInvalidPublicKey
+	}
+	digest, err := legacyHashDigest(plainText, hash)
+	if err != nil {
+		return false, err
+	}
+	return mapVerify(stdrsa.VerifyPKCS1v15(publicKey, hash, digest, signature))
+}
+
+// VerifyPKCS1v15Base64 校验 Base64(Std) 编码的 PKCS#1 v1.5 签名，Base64 非法时返回错误.
+func VerifyPKCS1v15Base64(publicKey *stdrsa.PublicKey, plainText []byte, signature string, hash crypto.Hash) (bool, error) {
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, err
+	}
+	return VerifyPKCS1v15(publicKey, plainText, raw, hash)
+}
+
+// SignPKCS1v15 使用已解析私钥执行 RSASSA-PKCS1-v1_5 签名.
+//
+// Deprecated: 仅用于对接只接受 v1.5 签名的遗留系统（如向网关发起请求时的 SHA256withRSA）。
+// 新协议请使用 SignPSSWithPrivateKey。
+func SignPKCS1v15(privateKey *stdrsa.PrivateKey, plainText []byte, hash crypto.Hash) ([]byte, error) {
+	if privateKey == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	digest, err := legacyHashDigest(plainText, hash)
+	if err != nil {
+		return nil, err
+	}
+	return stdrsa.SignPKCS1v15(rand.Reader, privateKey, hash, digest)
+}
+
+// legacyHashDigest 在 hashDigest 的 SHA-2 系列之外额外支持 SHA-1.
+func legacyHashDigest(plainText []byte, hash crypto.Hash) ([]byte, error) {
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum(plainText) // #nosec G401 -- 遗留网关协议要求.
+		return sum[:], nil
+	}
+	return hashDigest(plainText, hash)
+}
+
+// VerifyPKCS1v15 先按策略检查公钥，再执行 VerifyPKCS1v15.
+func (p KeyPolicy) VerifyPKCS1v15(publicKey *stdrsa.PublicKey, plainText, signature []byte, hash crypto.Hash) (bool, error) {
+	if err := p.Check(publicKey); err != nil {
+		return false, err
+	}
+	return VerifyPKCS1v15(publicKey, plainText, signature, hash)
+}
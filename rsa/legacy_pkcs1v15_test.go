@@ -0,0 +1,124 @@
+package rsa_test
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/gtkit/encry/rsa"
+	"github.com/stretchr/testify/require"
+)
+
+// gatewayPublicKeyPEM 与下方签名由 openssl 生成（openssl dgst -<hash> -sign），
+// 模拟支付网关回调：网关用私钥对参数串做 v1.5 签名，商户侧只持有网关公钥。
+const gatewayPublicKeyPEM = `-----BEGIN PUBLIC KEY-----
+MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA3Y3xAJjvzw0/Ayzn5Avx
+VvhPJDqva8uF1eYxUUESKc+Q1Ow7Vsajq+FTQCr8aPqR4yUWINTrf2smpXyPvg81
+a3APq2+QO7DyNoe4UxJrpkE7+mZ/UzdVKKYy2eLwpLztqfKMf2AyErqAHVvfFE3k
+29v+1WQHtVVTGGb1BTGZMOB6WzaPQUYqiTESzYkfMCat6LugWrQfaOnwDUL2VpJC
+9t6qETaeaQZ3rgmP5Hh6cHGt6EY62CYVNGHvaynJV+qNuLsLeOPBuulRydKtGEVJ
+3Ijvncwy406GlYpBcIUcX5XMUgVXqujTWwflb59yOdRmTlLIZ8EySde2YZIbmtAy
+dwIDAQAB
+-----END PUBLIC KEY-----
+`
+
+const gatewayPayload = "app_id=2021000000000000&charset=utf-8&out_trade_no=20261019001&total_amount=88.00&trade_status=TRADE_SUCCESS"
+
+func TestVerifyPKCS1v15OpenSSLFixtures(t *testing.T) {
+	t.Parallel()
+
+	pub, err := rsa.ParsePublicKeyPEM([]byte(gatewayPublicKeyPEM))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		hash      crypto.Hash
+		signature string
+	}{
+		{
+			name:      "SHA1withRSA",
+			hash:      crypto.SHA1,
+			signature: "yLa8hgd/GaOX1ttW7yEBW0dSEOX2KsDa8J394aXsPHgtQ8tIrcGmHKrzvmm51w60KZxpLj0zrrM2DAJ8bpu9hKJlrnvrZ+/goPBV5eS4RaZ9Gy1CqOnvkJudRq4ndQpE/QCEwWBSHC/S614GtkIPO3gk7gMconxyQ2tjtJUMVMKK0ojN5CSmrF5MHIzgceR9XHJvFHNEqZpJC/QZM7VNpmgoD4H+b/bgknABQLdsjHb4N2e5FVBkjvLBlcUCZl6FmSrO46UCiavDqNZ5I5jy80hZthyTESIr0Scm7ha8f6JLWuc9erwtvkl6WvJTyKNeNkEdH+w6Gxl0aZMZWZmbyg==",
+		},
+		{
+			name:      "SHA256withRSA",
+			hash:      crypto.SHA256,
+			signature: "VTM2mj4VTQZ8GyM3hx3sxlNfFue88P0Ne/4oUoXOoH3b3F2BEduAWv85YOHD4D8cGrhGorlBAaXywkmbJ+gJOC+++JeQxnUIBQT8fuvbVZ1tiXIkrVxJm0YJoAR8MG7pj/HYVtHscVz+VmtaKprmOjTJ8NCKrkm8wxxCe/7tTkJU8TkWHqGm7Pi/iwMbBlBo5qHUBNsMGR7mY+c5aMjIGemvl2Zqv00SoyWJIvrxfHom1UzL5FnQ13FQEsF4lo5pminhM7jvJVNEGy2fuKe39UgCBSPptAMsdj2I0l9+rZeunNwkEq0bc5KuDo8p7xavpp/ILyyu8lXLgjJshTiW1Q==",
+		},
+		{
+			name:      "SHA512withRSA",
+			hash:      crypto.SHA512,
+			signature: "C3P71NzxW7E17qOE547ZD6Y2gYdUSuMuQUiaQRFtmrXPjpFNq+wdt2FBxazJ/BkpJbakR1AncKUSY3O7Lf1GFIcjGlVmcqxz+HVeq00tOKh7E2nwoTm2Yi57n+zdd3K10ErmxgyexiJ1GLrjO8CCyRpX8wVysHtC/NrN72o9dE2nhTgVUer84t3/8gIM0VvPuha23WuCgciB79KX4kNR5T5Dl1wGaWcowX/Few6CdKXZiSVyRpo5sav5YRzweSKSZsZfN4+Gu+/1TYy46M3AQu0SwKS6NGLOTnD3EGxn8Z1nlIcMBxV5idaoGkSlPTAUV/7otAUr8a7YjoyYzFW01g==",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ok, err := rsa.VerifyPKCS1v15Base64(pub, []byte(gatewayPayload), tt.signature, tt.hash)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			ok, err = rsa.VerifyPKCS1v15Base64(pub, []byte(gatewayPayload+"&tampered=1"), tt.signature, tt.hash)
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestSignPKCS1v15RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	_, _, priv, pub := keyFiles(t)
+
+	for _, hash := range []crypto.Hash{crypto.SHA1, crypto.SHA224, crypto.SHA256, crypto.SHA384, crypto.SHA512} {
+		t.Run(hash.String(), func(t *testing.T) {
+			t.Parallel()
+			//nolint:staticcheck // 覆盖 Deprecated 的遗留签名 API.
+			signature, err := rsa.SignPKCS1v15(priv, []byte("legacy"), hash)
+			require.NoError(t, err)
+
+			ok, err := rsa.VerifyPKCS1v15(pub, []byte("legacy"), signature, hash)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			// v1.5 签名不能被当作 PSS 签名接受。
+			ok, err = rsa.VerifyPSSWithPublicKey(pub, []byte("legacy"), signature, hash, nil)
+			if hash != crypto.SHA1 {
+				require.NoError(t, err)
+				require.False(t, ok)
+			}
+		})
+	}
+}
+
+func TestPKCS1v15Errors(t *testing.T) {
+	t.Parallel()
+
+	_, _, priv, pub := keyFiles(t)
+
+	ok, err := rsa.VerifyPKCS1v15(nil, []byte("m"), []byte("sig"), crypto.SHA256)
+	require.ErrorIs(t, err, rsa.ErrInvalidPublicKey)
+	require.False(t, ok)
+
+	ok, err = rsa.VerifyPKCS1v15(pub, []byte("m"), []byte("sig"), crypto.MD5)
+	require.ErrorIs(t, err, rsa.ErrUnsupportedHash)
+	require.False(t, ok)
+
+	ok, err = rsa.VerifyPKCS1v15(pub, []byte("m"), []byte("short-signature"), crypto.SHA256)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = rsa.KeyPolicy{MinBits: 3072}.VerifyPKCS1v15(pub, []byte("m"), []byte("sig"), crypto.SHA256)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+	require.False(t, ok)
+
+	_, err = rsa.VerifyPKCS1v15Base64(pub, []byte("m"), "!!!notb64!!!", crypto.SHA256)
+	require.Error(t, err)
+
+	//nolint:staticcheck // 覆盖 Deprecated 的遗留签名 API.
+	_, err = rsa.SignPKCS1v15(nil, []byte("m"), crypto.SHA256)
+	require.ErrorIs(t, err, rsa.ErrInvalidPrivateKey)
+	//nolint:staticcheck // 覆盖 Deprecated 的遗留签名 API.
+	_, err = rsa.SignPKCS1v15(priv, []byte("m"), crypto.MD5)
+	require.ErrorIs(t, err, rsa.ErrUnsupportedHash)
+}
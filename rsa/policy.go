@@ -0,0 +1,361 @@
+package rsa
+
+import (
+	"crypto"
+	stdrsa "crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+// ErrKeyPolicy 表示密钥不满足 KeyPolicy；具体违反的规则见 *PolicyError.
+var ErrKeyPolicy = errors.New("RSA key violates policy")
+
+// PolicyRule 标识 KeyPolicy 中的一条规则.
+type PolicyRule string
+
+const (
+	// RuleMinBits 模数位数低于 KeyPolicy.MinBits.
+	RuleMinBits PolicyRule = "min-bits"
+	// RuleExponent 公钥指数不在 KeyPolicy.AllowedExponents 内.
+	RuleExponent PolicyRule = "exponent"
+	// RuleROCA 模数命中 ROCA（CVE-2017-15361）指纹.
+	RuleROCA PolicyRule = "roca"
+	// RuleWeakPrime 模数可被快速分解（偶数、含小素因子或两素数过于接近）.
+	RuleWeakPrime PolicyRule = "weak-prime"
+	// RuleInvalidKey 私钥自身结构校验失败.
+	RuleInvalidKey PolicyRule = "invalid-key"
+)
+
+// PolicyViolation 描述一条被违反的规则.
+type PolicyViolation struct {
+	Rule   PolicyRule
+	Detail string
+}
+
+// PolicyError 汇总一次检查中违反的全部规则，可用 errors.Is(err, ErrKeyPolicy) 判断.
+type PolicyError struct {
+	Violations []PolicyViolation
+}
+
+// Error 实现 error 接口.
+func (e *PolicyError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, string(v.Rule)+": "+v.Detail)
+	}
+	return ErrKeyPolicy.Error() + ": " + strings.Join(parts, "; ")
+}
+
+// Unwrap 使 errors.Is(err, ErrKeyPolicy) 成立.
+func (e *PolicyError) Unwrap() error {
+	return ErrKeyPolicy
+}
+
+// Has 报告是否违反了指定规则.
+func (e *PolicyError) Has(rule PolicyRule) bool {
+	return slices.ContainsFunc(e.Violations, func(v PolicyViolation) bool { return v.Rule == rule })
+}
+
+// KeyPolicy 描述可接受的 RSA 密钥强度，零值不做任何限制.
+type KeyPolicy struct {
+	// MinBits 为最小模数位数，<=0 表示不检查.
+	MinBits int
+	// AllowedExponents 为允许的公钥指数，为空表示不限制.
+	AllowedExponents []int
+	// CheckROCA 为 true 时拒绝命中 ROCA 指纹的模数.
+	CheckROCA bool
+	// CheckWeakPrimes 为 true 时拒绝偶数模数、含小素因子或可被 Fermat 分解的模数.
+	CheckWeakPrimes bool
+}
+
+// DefaultKeyPolicy 返回推荐策略：至少 2048 位、指数仅 65537、开启 ROCA 与弱素数检查.
+func DefaultKeyPolicy() KeyPolicy {
+	return KeyPolicy{
+		MinBits:          2048,
+		AllowedExponents: []int{65537},
+		CheckROCA:        true,
+		CheckWeakPrimes:  true,
+	}
+}
+
+// Check 按策略检查公钥，不满足时返回 *PolicyError.
+func (p KeyPolicy) Check(publicKey *stdrsa.PublicKey) error {
+	if publicKey == nil || publicKey.N == nil {
+		return ErrInvalidPublicKey
+	}
+
+	var violations []PolicyViolation
+	if bits := publicKey.N.BitLen(); p.MinBits > 0 && bits < p.MinBits {
+		violations = append(violations, PolicyViolation{
+			Rule:   RuleMinBits,
+			Detail: fmt.Sprintf("modulus is %d bits, want at least %d", bits, p.MinBits),
+		})
+	}
+	if len(p.AllowedExponents) > 0 && !slices.Contains(p.AllowedExponents, publicKey.E) {
+		violations = append(violations, PolicyViolation{
+			Rule:   RuleExponent,
+			Detail: fmt.Sprintf("public exponent %d is not allowed", publicKey.E),
+		})
+	}
+	if p.CheckROCA && hasROCAFingerprint(publicKey.N) {
+		violations = append(violations, PolicyViolation{
+			Rule:   RuleROCA,
+			Detail: "modulus matches the ROCA (CVE-2017-15361) fingerprint",
+		})
+	}
+	if p.CheckWeakPrimes {
+		if detail := weakModulus(publicKey.N); detail != "" {
+			violations = append(violations, PolicyViolation{Rule: RuleWeakPrime, Detail: detail})
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// CheckPrivateKey 在 Check 的基础上额外校验私钥结构（crypto/rsa.PrivateKey.Validate）.
+func (p KeyPolicy) CheckPrivateKey(privateKey *stdrsa.PrivateKey) error {
+	if privateKey == nil {
+		return ErrInvalidPrivateKey
+	}
+	err := p.Check(&privateKey.PublicKey)
+	var policyErr *PolicyError
+	if err != nil && !errors.As(err, &policyErr) {
+		return err
+	}
+	if validateErr := privateKey.Validate(); validateErr != nil {
+		if policyErr == nil {
+			policyErr = &PolicyError{}
+		}
+		policyErr.Violations = append(policyErr.Violations, PolicyViolation{
+			Rule:   RuleInvalidKey,
+			Detail: validateErr.Error(),
+		})
+	}
+	if policyErr != nil {
+		return policyErr
+	}
+	return nil
+}
+
+// ReadPublicKey 读取公钥文件并按策略检查.
+func (p KeyPolicy) ReadPublicKey(filePath string) (*stdrsa.PublicKey, error) {
+	publicKey, err := ReadPublicKey(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Check(publicKey); err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	return publicKey, nil
+}
+
+// ReadPrivateKey 读取私钥文件并按策略检查.
+func (p KeyPolicy) ReadPrivateKey(filePath string) (*stdrsa.PrivateKey, error) {
+	privateKey, err := ReadPrivateKey(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivateKey(privateKey); err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	return privateKey, nil
+}
+
+// ParsePublicKeyPEM 解析 PEM 公钥并按策略检查.
+func (p KeyPolicy) ParsePublicKeyPEM(data []byte) (*stdrsa.PublicKey, error) {
+	publicKey, err := ParsePublicKeyPEM(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Check(publicKey); err != nil {
+		return nil, err
+	}
+	return publicKey, nil
+}
+
+// ParsePrivateKeyPEM 解析 PEM 私钥并按策略检查.
+func (p KeyPolicy) ParsePrivateKeyPEM(data []byte) (*stdrsa.PrivateKey, error) {
+	privateKey, err := ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.CheckPrivateKey(privateKey); err != nil {
+		return nil, err
+	}
+	return privateKey, nil
+}
+
+// EncryptOAEPWithPublicKey 先按策略检查公钥，再执行 EncryptOAEPWithPublicKey.
+func (p KeyPolicy) EncryptOAEPWithPublicKey(publicKey *stdrsa.PublicKey, plainText []byte, hash crypto.Hash, label []byte) ([]byte, error) {
+	if err := p.Check(publicKey); err != nil {
+		return nil, err
+	}
+	return EncryptOAEPWithPublicKey(publicKey, plainText, hash, label)
+}
+
+// EncryptOAEPChunkedWithPublicKey 先按策略检查公钥，再执行 EncryptOAEPChunkedWithPublicKey.
+func (p KeyPolicy) EncryptOAEPChunkedWithPublicKey(publicKey *stdrsa.PublicKey, plainText []byte, hash crypto.Hash, label []byte) ([]byte, error) {
+	if err := p.Check(publicKey); err != nil {
+		return nil, err
+	}
+	return EncryptOAEPChunkedWithPublicKey(publicKey, plainText, hash, label)
+}
+
+// VerifyPSSWithPublicKey 先按策略检查公钥，再执行 VerifyPSSWithPublicKey.
+// 公钥不满足策略属于操作性错误，返回 (false, *PolicyError).
+func (p KeyPolicy) VerifyPSSWithPublicKey(publicKey *stdrsa.PublicKey, plainText, signature []byte, hash crypto.Hash, opts *stdrsa.PSSOptions) (bool, error) {
+	if err := p.Check(publicKey); err != nil {
+		return false, err
+	}
+	return VerifyPSSWithPublicKey(publicKey, plainText, signature, hash, opts)
+}
+
+// EncryptOAEP 从文件读取公钥并按策略检查后，使用 RSA-OAEP + SHA256 单块加密.
+func (p KeyPolicy) EncryptOAEP(plainText []byte, pubFilePath string) ([]byte, error) {
+	return p.EncryptOAEPWithOptions(plainText, pubFilePath, crypto.SHA256, nil)
+}
+
+// EncryptOAEPWithOptions 从文件读取公钥并按策略检查后，执行 EncryptOAEPWithOptions.
+func (p KeyPolicy) EncryptOAEPWithOptions(plainText []byte, pubFilePath string, hash crypto.Hash, label []byte) ([]byte, error) {
+	publicKey, err := p.ReadPublicKey(pubFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptOAEPWithPublicKey(publicKey, plainText, hash, label)
+}
+
+// EncryptOAEPChunkedWithOptions 从文件读取公钥并按策略检查后，执行 EncryptOAEPChunkedWithOptions.
+func (p KeyPolicy) EncryptOAEPChunkedWithOptions(plainText []byte, pubFilePath string, hash crypto.Hash, label []byte) ([]byte, error) {
+	publicKey, err := p.ReadPublicKey(pubFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return EncryptOAEPChunkedWithPublicKey(publicKey, plainText, hash, label)
+}
+
+// DecryptOAEPWithOptions 从文件读取私钥并按策略检查后，执行 DecryptOAEPWithOptions.
+func (p KeyPolicy) DecryptOAEPWithOptions(cipherText []byte, priFilePath string, hash crypto.Hash, label []byte) ([]byte, error) {
+	privateKey, err := p.ReadPrivateKey(priFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptOAEPWithPrivateKey(privateKey, cipherText, hash, label)
+}
+
+// SignPSSWithOptions 从文件读取私钥并按策略检查后，执行 SignPSSWithOptions.
+func (p KeyPolicy) SignPSSWithOptions(plainText []byte, priFilePath string, hash crypto.Hash, opts *stdrsa.PSSOptions) ([]byte, error) {
+	privateKey, err := p.ReadPrivateKey(priFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return SignPSSWithPrivateKey(privateKey, plainText, hash, opts)
+}
+
+// VerifyPSS 从文件读取公钥并按策略检查后，使用 RSA-PSS + SHA256 验签.
+func (p KeyPolicy) VerifyPSS(plainText []byte, pubFilePath string, signature []byte) (bool, error) {
+	return p.VerifyPSSWithOptions(plainText, pubFilePath, signature, crypto.SHA256, nil)
+}
+
+// VerifyPSSBase64 从文件读取公钥并按策略检查后，使用 RSA-PSS + SHA256 Base64 验签.
+func (p KeyPolicy) VerifyPSSBase64(plainText []byte, pubFilePath, signature string) (bool, error) {
+	return p.VerifyPSSBase64WithOptions(plainText, pubFilePath, signature, crypto.SHA256, nil)
+}
+
+// VerifyPSSWithOptions 从文件读取公钥并按策略检查后，执行 VerifyPSSWithOptions.
+// 公钥不满足策略属于操作性错误，返回 (false, *PolicyError).
+func (p KeyPolicy) VerifyPSSWithOptions(plainText []byte, pubFilePath string, signature []byte, hash crypto.Hash, opts *stdrsa.PSSOptions) (bool, error) {
+	publicKey, err := p.ReadPublicKey(pubFilePath)
+	if err != nil {
+		return false, err
+	}
+	return VerifyPSSWithPublicKey(publicKey, plainText, signature, hash, opts)
+}
+
+// VerifyPSSBase64WithOptions 从文件读取公钥并按策略检查后，执行 VerifyPSSBase64WithOptions.
+func (p KeyPolicy) VerifyPSSBase64WithOptions(plainText []byte, pubFilePath, signature string, hash crypto.Hash, opts *stdrsa.PSSOptions) (bool, error) {
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, err
+	}
+	return p.VerifyPSSWithOptions(plainText, pubFilePath, raw, hash, opts)
+}
+
+// rocaPrimes 是 ROCA 检测所用的小素数表（与 CRoCS 官方检测工具一致）.
+var rocaPrimes = []int64{
+	3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73,
+	79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131, 137, 139, 149, 151, 157, 163, 167,
+}
+
+// rocaSubgroups[i] 标记 65537 在 Z/rocaPrimes[i] 中生成的子群元素.
+var rocaSubgroups = func() [][]bool {
+	groups := make([][]bool, len(rocaPrimes))
+	for i, prime := range rocaPrimes {
+		member := make([]bool, prime)
+		g := 65537 % prime
+		for x := int64(1); !member[x]; x = x * g % prime {
+			member[x] = true
+		}
+		groups[i] = member
+	}
+	return groups
+}()
+
+// hasROCAFingerprint 判断模数是否落在 ROCA 弱密钥的结构中：
+// 受影响库生成的 N 对上述每个小素数 p 取模都属于 <65537> mod p.
+func hasROCAFingerprint(n *big.Int) bool {
+	var r big.Int
+	for i, prime := range rocaPrimes {
+		r.Mod(n, big.NewInt(prime))
+		if !rocaSubgroups[i][r.Int64()] {
+			return false
+		}
+	}
+	return true
+}
+
+// smallPrimesProduct 是 1000 以内全部素数之积，用一次 GCD 完成小因子试除.
+var smallPrimesProduct = func() *big.Int {
+	product := big.NewInt(1)
+	for i := int64(3); i < 1000; i += 2 {
+		if big.NewInt(i).ProbablyPrime(0) {
+			product.Mul(product, big.NewInt(i))
+		}
+	}
+	return product
+}()
+
+// fermatRounds 是 Fermat 分解的尝试轮数，可覆盖 |p-q| 很小的"相邻素数"缺陷.
+const fermatRounds = 100
+
+// weakModulus 做基础的弱模数检查，发现问题时返回描述，否则返回空串.
+func weakModulus(n *big.Int) string {
+	if n.Bit(0) == 0 {
+		return "modulus is even"
+	}
+	if g := new(big.Int).GCD(nil, nil, n, smallPrimesProduct); g.Cmp(big.NewInt(1)) != 0 {
+		return fmt.Sprintf("modulus has small factor %s", g)
+	}
+
+	a := new(big.Int).Sqrt(n)
+	if new(big.Int).Mul(a, a).Cmp(n) < 0 {
+		a.Add(a, big.NewInt(1))
+	}
+	b2, b := new(big.Int), new(big.Int)
+	for range fermatRounds {
+		b2.Mul(a, a)
+		b2.Sub(b2, n)
+		b.Sqrt(b2)
+		if new(big.Int).Mul(b, b).Cmp(b2) == 0 {
+			return "modulus factors by Fermat's method (primes too close)"
+		}
+		a.Add(a, big.NewInt(1))
+	}
+	return ""
+}
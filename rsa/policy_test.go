@@ -0,0 +1,251 @@
+package rsa_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gtkit/encry/rsa"
+	"github.com/stretchr/testify/require"
+)
+
+// rocaModulus 构造一个命中 ROCA 指纹的约 2048 位奇数模数：N ≡ 65537^c (mod M)，
+// M 为 ROCA 检测素数之积。仅用于公钥检查，并非可用的 RSA 密钥。
+func rocaModulus(t *testing.T) *big.Int {
+	t.Helper()
+
+	primes := []int64{
+		3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71, 73,
+		79, 83, 89, 97, 101, 103, 107, 109, 113, 127, 131, 137, 139, 149, 151, 157, 163, 167,
+	}
+	m := big.NewInt(2) // 乘 2 保证结果为奇数
+	for _, p := range primes {
+		m.Mul(m, big.NewInt(p))
+	}
+	residue := new(big.Int).Exp(big.NewInt(65537), big.NewInt(1234), m)
+	if residue.Bit(0) == 0 {
+		residue.Add(residue, new(big.Int).Div(m, big.NewInt(2)))
+		residue.Mod(residue, m)
+	}
+
+	k, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 2048-uint(m.BitLen())))
+	require.NoError(t, err)
+	n := new(big.Int).Mul(k, m)
+	return n.Add(n, residue)
+}
+
+// closePrimesModulus 构造两素数极其接近、可被 Fermat 方法分解的模数。
+func closePrimesModulus(t *testing.T) *big.Int {
+	t.Helper()
+
+	p, err := rand.Prime(rand.Reader, 1024)
+	require.NoError(t, err)
+	q := new(big.Int).Add(p, big.NewInt(2))
+	for !q.ProbablyPrime(20) {
+		q.Add(q, big.NewInt(2))
+	}
+	return new(big.Int).Mul(p, q)
+}
+
+func TestKeyPolicyCheck(t *testing.T) {
+	t.Parallel()
+
+	_, _, _, good := keyFiles(t)
+	small, err := stdrsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		policy    rsa.KeyPolicy
+		key       *stdrsa.PublicKey
+		wantRules []rsa.PolicyRule
+	}{
+		{name: "default accepts 2048", policy: rsa.DefaultKeyPolicy(), key: good},
+		{name: "zero policy accepts 1024", policy: rsa.KeyPolicy{}, key: &small.PublicKey},
+		{name: "too small", policy: rsa.DefaultKeyPolicy(), key: &small.PublicKey, wantRules: []rsa.PolicyRule{rsa.RuleMinBits}},
+		{
+			name:      "disallowed exponent",
+			policy:    rsa.DefaultKeyPolicy(),
+			key:       &stdrsa.PublicKey{N: good.N, E: 3},
+			wantRules: []rsa.PolicyRule{rsa.RuleExponent},
+		},
+		{
+			name:      "roca fingerprint",
+			policy:    rsa.KeyPolicy{CheckROCA: true},
+			key:       &stdrsa.PublicKey{N: rocaModulus(t), E: 65537},
+			wantRules: []rsa.PolicyRule{rsa.RuleROCA},
+		},
+		{
+			name:      "close primes",
+			policy:    rsa.KeyPolicy{CheckWeakPrimes: true},
+			key:       &stdrsa.PublicKey{N: closePrimesModulus(t), E: 65537},
+			wantRules: []rsa.PolicyRule{rsa.RuleWeakPrime},
+		},
+		{
+			name:      "small factor",
+			policy:    rsa.KeyPolicy{CheckWeakPrimes: true},
+			key:       &stdrsa.PublicKey{N: new(big.Int).Mul(good.N, big.NewInt(997)), E: 65537},
+			wantRules: []rsa.PolicyRule{rsa.RuleWeakPrime},
+		},
+		{
+			name:      "multiple violations",
+			policy:    rsa.DefaultKeyPolicy(),
+			key:       &stdrsa.PublicKey{N: small.N, E: 17},
+			wantRules: []rsa.PolicyRule{rsa.RuleMinBits, rsa.RuleExponent},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.policy.Check(tt.key)
+			if len(tt.wantRules) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+			var policyErr *rsa.PolicyError
+			require.ErrorAs(t, err, &policyErr)
+			require.Len(t, policyErr.Violations, len(tt.wantRules))
+			for _, rule := range tt.wantRules {
+				require.True(t, policyErr.Has(rule), "missing rule %s in %v", rule, err)
+			}
+		})
+	}
+}
+
+func TestKeyPolicyCheckNilKeys(t *testing.T) {
+	t.Parallel()
+
+	policy := rsa.DefaultKeyPolicy()
+	require.ErrorIs(t, policy.Check(nil), rsa.ErrInvalidPublicKey)
+	require.ErrorIs(t, policy.CheckPrivateKey(nil), rsa.ErrInvalidPrivateKey)
+}
+
+func TestKeyPolicyCheckPrivateKey(t *testing.T) {
+	t.Parallel()
+
+	_, _, priv, _ := keyFiles(t)
+	require.NoError(t, rsa.DefaultKeyPolicy().CheckPrivateKey(priv))
+
+	broken := *priv
+	broken.D = new(big.Int).Add(priv.D, big.NewInt(2))
+	err := rsa.KeyPolicy{}.CheckPrivateKey(&broken)
+	var policyErr *rsa.PolicyError
+	require.ErrorAs(t, err, &policyErr)
+	require.True(t, policyErr.Has(rsa.RuleInvalidKey))
+}
+
+func TestKeyPolicyLoaders(t *testing.T) {
+	t.Parallel()
+
+	priPath, pubPath, _, _ := keyFiles(t)
+	strict := rsa.KeyPolicy{MinBits: 3072}
+	lenient := rsa.DefaultKeyPolicy()
+
+	_, err := lenient.ReadPublicKey(pubPath)
+	require.NoError(t, err)
+	_, err = lenient.ReadPrivateKey(priPath)
+	require.NoError(t, err)
+
+	_, err = strict.ReadPublicKey(pubPath)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+	_, err = strict.ReadPrivateKey(priPath)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+
+	pubPEM, err := os.ReadFile(pubPath)
+	require.NoError(t, err)
+	priPEM, err := os.ReadFile(priPath)
+	require.NoError(t, err)
+
+	_, err = lenient.ParsePublicKeyPEM(pubPEM)
+	require.NoError(t, err)
+	_, err = lenient.ParsePrivateKeyPEM(priPEM)
+	require.NoError(t, err)
+	_, err = strict.ParsePublicKeyPEM(pubPEM)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+	_, err = strict.ParsePrivateKeyPEM(priPEM)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+
+	// 底层解析错误原样透传。
+	_, err = lenient.ParsePublicKeyPEM([]byte("not pem"))
+	require.ErrorIs(t, err, rsa.ErrInvalidPEMBlock)
+	_, err = lenient.ReadPublicKey(filepath.Join(t.TempDir(), "missing.pem"))
+	require.Error(t, err)
+}
+
+func TestKeyPolicyHelpers(t *testing.T) {
+	t.Parallel()
+
+	_, _, priv, pub := keyFiles(t)
+	strict := rsa.KeyPolicy{MinBits: 3072}
+	lenient := rsa.DefaultKeyPolicy()
+
+	_, err := lenient.EncryptOAEPWithPublicKey(pub, []byte("hi"), crypto.SHA256, nil)
+	require.NoError(t, err)
+	_, err = strict.EncryptOAEPWithPublicKey(pub, []byte("hi"), crypto.SHA256, nil)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+
+	_, err = lenient.EncryptOAEPChunkedWithPublicKey(pub, []byte("hi"), crypto.SHA256, nil)
+	require.NoError(t, err)
+	_, err = strict.EncryptOAEPChunkedWithPublicKey(pub, []byte("hi"), crypto.SHA256, nil)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+
+	signature, err := rsa.SignPSSWithPrivateKey(priv, []byte("msg"), crypto.SHA256, nil)
+	require.NoError(t, err)
+	ok, err := lenient.VerifyPSSWithPublicKey(pub, []byte("msg"), signature, crypto.SHA256, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = strict.VerifyPSSWithPublicKey(pub, []byte("msg"), signature, crypto.SHA256, nil)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+	require.False(t, ok)
+}
+
+func TestKeyPolicyFileHelpers(t *testing.T) {
+	t.Parallel()
+
+	priPath, pubPath, _, _ := keyFiles(t)
+	strict := rsa.KeyPolicy{MinBits: 3072}
+	lenient := rsa.DefaultKeyPolicy()
+
+	cipherText, err := lenient.EncryptOAEP([]byte("hi"), pubPath)
+	require.NoError(t, err)
+	plainText, err := lenient.DecryptOAEPWithOptions(cipherText, priPath, crypto.SHA256, nil)
+	require.NoError(t, err)
+	require.Equal(t, "hi", string(plainText))
+	_, err = lenient.EncryptOAEPChunkedWithOptions([]byte("hi"), pubPath, crypto.SHA256, nil)
+	require.NoError(t, err)
+
+	signature, err := lenient.SignPSSWithOptions([]byte("msg"), priPath, crypto.SHA256, nil)
+	require.NoError(t, err)
+	ok, err := lenient.VerifyPSS([]byte("msg"), pubPath, signature)
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = lenient.VerifyPSSBase64([]byte("msg"), pubPath, base64.StdEncoding.EncodeToString(signature))
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, err = lenient.VerifyPSSBase64([]byte("msg"), pubPath, "!")
+	require.Error(t, err)
+
+	var policyErr *rsa.PolicyError
+	_, err = strict.EncryptOAEP([]byte("hi"), pubPath)
+	require.ErrorAs(t, err, &policyErr)
+	require.True(t, policyErr.Has(rsa.RuleMinBits))
+	_, err = strict.EncryptOAEPChunkedWithOptions([]byte("hi"), pubPath, crypto.SHA256, nil)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+	_, err = strict.DecryptOAEPWithOptions(cipherText, priPath, crypto.SHA256, nil)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+	_, err = strict.SignPSSWithOptions([]byte("msg"), priPath, crypto.SHA256, nil)
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+	ok, err = strict.VerifyPSS([]byte("msg"), pubPath, signature)
+	require.ErrorAs(t, err, &policyErr)
+	require.False(t, ok)
+	ok, err = strict.VerifyPSSBase64([]byte("msg"), pubPath, base64.StdEncoding.EncodeToString(signature))
+	require.ErrorIs(t, err, rsa.ErrKeyPolicy)
+	require.False(t, ok)
+}
@@ -0,0 +1,37 @@
+package seed_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gtkit/encry/seed"
+)
+
+func ExampleDeriveEd25519() {
+	master, err := seed.New()
+	if err != nil {
+		panic(err)
+	}
+
+	// 备份：把主种子抄写为 24 个助记词。
+	mnemonic, err := seed.EncodeMnemonic(master)
+	if err != nil {
+		panic(err)
+	}
+
+	// 恢复：由助记词还原主种子，再按同一路径重新派生出同一把密钥。
+	restored, err := seed.DecodeMnemonic(mnemonic)
+	if err != nil {
+		panic(err)
+	}
+	pub1, _, err := seed.DeriveEd25519(master, "m/release/0")
+	if err != nil {
+		panic(err)
+	}
+	pub2, _, err := seed.DeriveEd25519(restored, "m/release/0")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(bytes.Equal(pub1, pub2))
+	// Output: true
+}
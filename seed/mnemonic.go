@@ -0,0 +1,117 @@
+package seed
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// bip39English 为 BIP-39 官方英文词表（2048 词，CRC32 c1dbd296）。
+//
+//go:embed bip39_english.txt
+var bip39English string
+
+var (
+	// ErrInvalidEntropy 表示待编码的种子长度不是 16/20/24/28/32 字节。
+	ErrInvalidEntropy = errors.New("seed: mnemonic entropy must be 16, 20, 24, 28 or 32 bytes")
+	// ErrInvalidMnemonic 表示助记词个数非法或包含词表外的单词。
+	ErrInvalidMnemonic = errors.New("seed: invalid mnemonic")
+	// ErrMnemonicChecksum 表示助记词校验和不匹配（通常是抄错或顺序错误）。
+	ErrMnemonicChecksum = errors.New("seed: mnemonic checksum mismatch")
+)
+
+type wordlist struct {
+	words []string
+	index map[string]int
+}
+
+var english = sync.OnceValue(func() *wordlist {
+	words := strings.Fields(bip39English)
+	index := make(map[string]int, len(words))
+	for i, w := range words {
+		index[w] = i
+	}
+	return &wordlist{words: words, index: index}
+})
+
+// EncodeMnemonic 将种子按 BIP-39 编码为英文助记词：ENT 位熵后接 ENT/32 位 SHA-256 校验和，
+// 每 11 位对应一个单词。32 字节种子得到 24 个单词。
+//
+// 只做 BIP-39 的“熵 ↔ 助记词”编码，不做 PBKDF2 口令扩展：DecodeMnemonic 恢复出的就是
+// 原始种子，可直接交给 Derive。因此与钱包软件共用词表和校验规则，但派生结果并不通用。
+func EncodeMnemonic(entropy []byte) (string, error) {
+	if !validEntropyLen(len(entropy)) {
+		return "", ErrInvalidEntropy
+	}
+	bits := appendChecksum(entropy)
+	wl := english()
+
+	n := len(entropy) * 8 * 33 / 32 / 11
+	words := make([]string, n)
+	for i := range words {
+		words[i] = wl.words[readBits(bits, i*11, 11)]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// DecodeMnemonic 校验并解码 EncodeMnemonic 生成的助记词，返回原始种子。
+// 单词间任意空白均可，大小写不敏感。
+func DecodeMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	n := len(words)
+	if n%3 != 0 || n < 12 || n > 24 {
+		return nil, ErrInvalidMnemonic
+	}
+
+	wl := english()
+	totalBits := n * 11
+	buf := make([]byte, (totalBits+7)/8)
+	for i, w := range words {
+		idx, ok := wl.index[w]
+		if !ok {
+			return nil, ErrInvalidMnemonic
+		}
+		writeBits(buf, i*11, 11, idx)
+	}
+
+	entLen := totalBits * 32 / 33 / 8
+	entropy := buf[:entLen]
+	want := appendChecksum(entropy)
+	csBits := entLen * 8 / 32
+	if readBits(buf, entLen*8, csBits) != readBits(want, entLen*8, csBits) {
+		return nil, ErrMnemonicChecksum
+	}
+	return append([]byte(nil), entropy...), nil
+}
+
+func validEntropyLen(n int) bool {
+	return n >= 16 && n <= 32 && n%4 == 0
+}
+
+// appendChecksum 返回 entropy || SHA-256(entropy)[0]；校验和最多 8 位，一个字节足够。
+func appendChecksum(entropy []byte) []byte {
+	sum := sha256.Sum256(entropy)
+	return append(append([]byte(nil), entropy...), sum[0])
+}
+
+// readBits 从 buf 的第 off 位（大端）起读取 n 位。
+func readBits(buf []byte, off, n int) int {
+	v := 0
+	for i := range n {
+		bit := off + i
+		v = v<<1 | int(buf[bit/8]>>(7-bit%8)&1)
+	}
+	return v
+}
+
+// writeBits 将 v 的低 n 位写入 buf 的第 off 位（大端）起。
+func writeBits(buf []byte, off, n, v int) {
+	for i := range n {
+		bit := off + i
+		if v>>(n-1-i)&1 == 1 {
+			buf[bit/8] |= 1 << (7 - bit%8)
+		}
+	}
+}
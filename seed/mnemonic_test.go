@@ -0,0 +1,86 @@
+package seed_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/seed"
+	"github.com/stretchr/testify/require"
+)
+
+// testdata/bip39_vectors.json 为 BIP-39 参考实现（Trezor）的熵/助记词向量。
+func TestMnemonicVectors(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "bip39_vectors.json"))
+	require.NoError(t, err)
+	var vectors []struct {
+		Entropy  string `json:"entropy"`
+		Mnemonic string `json:"mnemonic"`
+	}
+	require.NoError(t, json.Unmarshal(data, &vectors))
+	require.NotEmpty(t, vectors)
+
+	for _, v := range vectors {
+		entropy, err := hex.DecodeString(v.Entropy)
+		require.NoError(t, err)
+
+		mnemonic, err := seed.EncodeMnemonic(entropy)
+		require.NoError(t, err)
+		require.Equal(t, v.Mnemonic, mnemonic)
+
+		decoded, err := seed.DecodeMnemonic(v.Mnemonic)
+		require.NoError(t, err)
+		require.Equal(t, entropy, decoded)
+	}
+}
+
+func TestMnemonicRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{16, 20, 24, 28, 32} {
+		master := make([]byte, size)
+		for i := range master {
+			master[i] = byte(i*37 + size)
+		}
+		mnemonic, err := seed.EncodeMnemonic(master)
+		require.NoError(t, err)
+		require.Len(t, strings.Fields(mnemonic), size*3/4)
+
+		// 大小写与多余空白不影响解码。
+		decoded, err := seed.DecodeMnemonic("  " + strings.ToUpper(strings.ReplaceAll(mnemonic, " ", "\n\t")) + " ")
+		require.NoError(t, err)
+		require.Equal(t, master, decoded)
+	}
+}
+
+func TestMnemonicErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{0, 15, 17, 33, 64} {
+		_, err := seed.EncodeMnemonic(make([]byte, size))
+		require.ErrorIs(t, err, seed.ErrInvalidEntropy, "size %d", size)
+	}
+
+	valid := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	tests := []struct {
+		name     string
+		mnemonic string
+		want     error
+	}{
+		{"empty", "", seed.ErrInvalidMnemonic},
+		{"too few words", "abandon abandon abandon", seed.ErrInvalidMnemonic},
+		{"not a multiple of three", valid + " abandon", seed.ErrInvalidMnemonic},
+		{"unknown word", strings.Replace(valid, "about", "aboutt", 1), seed.ErrInvalidMnemonic},
+		{"bad checksum", strings.Replace(valid, "about", "abandon", 1), seed.ErrMnemonicChecksum},
+		{"swapped words", "legal winner thank year wave sausage worth useful legal winner yellow thank", seed.ErrMnemonicChecksum},
+	}
+	for _, tt := range tests {
+		_, err := seed.DecodeMnemonic(tt.mnemonic)
+		require.ErrorIs(t, err, tt.want, tt.name)
+	}
+}
@@ -0,0 +1,119 @@
+// Package seed 提供由主种子确定性派生 Ed25519/X25519 密钥的能力（主种子 → 路径 → 密钥），
+// 用于备份恢复与可复现的测试夹具。
+//
+// 派生基于 hkdf 包（HKDF-SHA256）逐级进行：路径 "m/app/signing/0" 的每一段都以上一级
+// 的 32 字节种子为输入派生下一级，因此
+//
+//	Derive(Derive(master, "app"), "signing/0") == Derive(master, "app/signing/0")
+//
+// 可以把某个子树的种子交给下游服务而不暴露主种子。最终的密钥种子再按密钥类型做一次
+// 域分离，同一路径下的 Ed25519 与 X25519 密钥互不相关。
+//
+// 主种子可用 EncodeMnemonic 编码为 BIP-39 英文助记词（带校验和）便于抄写备份。
+// 主种子、任一级子种子都等同于私钥，必须按私钥保管。
+package seed
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"strings"
+
+	encryecdh "github.com/gtkit/encry/ecdh"
+	"github.com/gtkit/encry/ed"
+	"github.com/gtkit/encry/hkdf"
+)
+
+const (
+	// MasterSize 为 New 生成的主种子长度（256 位，对应 24 个助记词）。
+	MasterSize = 32
+	// MinMasterSize 为可接受的最短主种子长度（128 位，对应 12 个助记词）。
+	MinMasterSize = 16
+	// ChildSize 为 Derive 返回的子种子长度。
+	ChildSize = 32
+
+	maxSegmentLen = 255
+
+	// derivationSalt 固定为协议版本标识；修改会改变所有派生结果。
+	derivationSalt = "encry/seed/v1"
+	childInfo      = "encry/seed/v1/child:"
+	keyInfo        = "encry/seed/v1/key:"
+)
+
+var (
+	// ErrInvalidMaster 表示主种子短于 MinMasterSize。
+	ErrInvalidMaster = errors.New("seed: master seed must be at least 16 bytes")
+	// ErrInvalidPath 表示派生路径为空或含空段、超长段。
+	ErrInvalidPath = errors.New("seed: invalid derivation path")
+)
+
+// New 使用 crypto/rand 生成 MasterSize 字节的主种子。
+func New() ([]byte, error) {
+	master := make([]byte, MasterSize)
+	if _, err := rand.Read(master); err != nil {
+		return nil, err
+	}
+	return master, nil
+}
+
+// Derive 按路径从 master 派生 ChildSize 字节的子种子。
+//
+// 路径以 "/" 分段，可带前缀 "m/"（如 "m/app/signing/0" 与 "app/signing/0" 等价），
+// 每段为 1~255 字节的任意非 "/" 字符串。
+func Derive(master []byte, path string) ([]byte, error) {
+	if len(master) < MinMasterSize {
+		return nil, ErrInvalidMaster
+	}
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	node := master
+	for _, segment := range segments {
+		if node, err = hkdf.Derive(node, []byte(derivationSalt), childInfo+segment, ChildSize); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// DeriveEd25519 按路径从 master 派生 Ed25519 密钥对。
+func DeriveEd25519(master []byte, path string) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	keySeed, err := deriveKeySeed(master, path, "ed25519")
+	if err != nil {
+		return nil, nil, err
+	}
+	return ed.NewKeyFromSeed(keySeed)
+}
+
+// DeriveX25519 按路径从 master 派生 X25519 私钥。
+func DeriveX25519(master []byte, path string) (*ecdh.PrivateKey, error) {
+	keySeed, err := deriveKeySeed(master, path, "x25519")
+	if err != nil {
+		return nil, err
+	}
+	return encryecdh.X25519FromSeed(keySeed)
+}
+
+func deriveKeySeed(master []byte, path, keyType string) ([]byte, error) {
+	node, err := Derive(master, path)
+	if err != nil {
+		return nil, err
+	}
+	return hkdf.Derive(node, []byte(derivationSalt), keyInfo+keyType, ChildSize)
+}
+
+func parsePath(path string) ([]string, error) {
+	path = strings.TrimPrefix(path, "m/")
+	if path == "" {
+		return nil, ErrInvalidPath
+	}
+	segments := strings.Split(path, "/")
+	for _, segment := range segments {
+		if segment == "" || len(segment) > maxSegmentLen {
+			return nil, ErrInvalidPath
+		}
+	}
+	return segments, nil
+}
@@ -0,0 +1,114 @@
+package seed_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/seed"
+	"github.com/stretchr/testify/require"
+)
+
+func testMaster(t *testing.T) []byte {
+	t.Helper()
+	master, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	require.NoError(t, err)
+	return master
+}
+
+func TestDeriveDeterministic(t *testing.T) {
+	t.Parallel()
+
+	master := testMaster(t)
+	a, err := seed.Derive(master, "m/app/signing/0")
+	require.NoError(t, err)
+	b, err := seed.Derive(master, "app/signing/0")
+	require.NoError(t, err)
+	require.Len(t, a, seed.ChildSize)
+	require.Equal(t, a, b)
+
+	// 固定向量：派生算法变化会导致已备份的密钥无法恢复，必须显式更新此值。
+	require.Equal(t, "c54948d3866832d5db7ef0bef28708cfe88d491046e2b1cbecff1b18dca78d61", hex.EncodeToString(a))
+
+	other, err := seed.Derive(master, "app/signing/1")
+	require.NoError(t, err)
+	require.NotEqual(t, a, other)
+}
+
+func TestDeriveHierarchical(t *testing.T) {
+	t.Parallel()
+
+	master := testMaster(t)
+	full, err := seed.Derive(master, "m/tenant-a/payments/0")
+	require.NoError(t, err)
+
+	sub, err := seed.Derive(master, "m/tenant-a")
+	require.NoError(t, err)
+	fromSub, err := seed.Derive(sub, "payments/0")
+	require.NoError(t, err)
+	require.Equal(t, full, fromSub)
+
+	// 段边界参与派生："ab/c" 与 "a/bc" 不同。
+	x, err := seed.Derive(master, "ab/c")
+	require.NoError(t, err)
+	y, err := seed.Derive(master, "a/bc")
+	require.NoError(t, err)
+	require.NotEqual(t, x, y)
+}
+
+func TestDeriveKeys(t *testing.T) {
+	t.Parallel()
+
+	master := testMaster(t)
+	pub, priv, err := seed.DeriveEd25519(master, "m/release")
+	require.NoError(t, err)
+	pub2, _, err := seed.DeriveEd25519(master, "m/release")
+	require.NoError(t, err)
+	require.Equal(t, pub, pub2)
+
+	msg := []byte("reproducible")
+	require.True(t, ed25519.Verify(pub, msg, ed25519.Sign(priv, msg)))
+
+	xk, err := seed.DeriveX25519(master, "m/release")
+	require.NoError(t, err)
+	xk2, err := seed.DeriveX25519(master, "m/release")
+	require.NoError(t, err)
+	require.True(t, xk.Equal(xk2))
+
+	// 同一路径下不同密钥类型互相独立，也不等于路径子种子本身。
+	node, err := seed.Derive(master, "m/release")
+	require.NoError(t, err)
+	require.NotEqual(t, priv.Seed(), xk.Bytes())
+	require.NotEqual(t, node, priv.Seed())
+	require.NotEqual(t, node, xk.Bytes())
+}
+
+func TestDeriveErrors(t *testing.T) {
+	t.Parallel()
+
+	master := testMaster(t)
+	_, err := seed.Derive(master[:15], "a")
+	require.ErrorIs(t, err, seed.ErrInvalidMaster)
+	_, _, err = seed.DeriveEd25519(nil, "a")
+	require.ErrorIs(t, err, seed.ErrInvalidMaster)
+	_, err = seed.DeriveX25519(nil, "a")
+	require.ErrorIs(t, err, seed.ErrInvalidMaster)
+
+	for _, path := range []string{"", "m/", "a//b", "/a", "a/", strings.Repeat("x", 256)} {
+		_, err = seed.Derive(master, path)
+		require.ErrorIs(t, err, seed.ErrInvalidPath, "path %q", path)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	a, err := seed.New()
+	require.NoError(t, err)
+	b, err := seed.New()
+	require.NoError(t, err)
+	require.Len(t, a, seed.MasterSize)
+	require.False(t, bytes.Equal(a, b))
+}
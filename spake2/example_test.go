@@ -0,0 +1,31 @@
+package spake2_test
+
+import (
+	"fmt"
+
+	"github.com/gtkit/encry/spake2"
+)
+
+func Example() {
+	// 电视屏幕显示配对码，用户在手机上输入。
+	ids := spake2.WithIdentities([]byte("phone-01"), []byte("tv-living-room"))
+	phone, _ := spake2.New(spake2.RoleA, []byte("482913"), ids)
+	tv, _ := spake2.New(spake2.RoleB, []byte("482913"), ids)
+
+	// phone → tv: pA；tv → phone: pB, cB；phone → tv: cA。
+	cB, _ := tv.Finish(phone.Message())
+	cA, _ := phone.Finish(tv.Message())
+	if err := phone.Verify(cB); err != nil {
+		panic(err)
+	}
+	if err := tv.Verify(cA); err != nil {
+		panic(err)
+	}
+
+	sealer, _ := phone.ChaCha()
+	opener, _ := tv.ChaCha()
+	cipherText, _ := sealer.Encrypt([]byte("wifi-credentials"))
+	plainText, _ := opener.Decrypt(cipherText)
+	fmt.Println(string(plainText))
+	// Output: wifi-credentials
+}
@@ -0,0 +1,297 @@
+// Package spake2 实现 RFC 9382 SPAKE2 口令认证密钥交换（PAKE），套件为
+// SPAKE2-edwards25519-SHA256-HKDF-HMAC（与 ed 相同的曲线），用于设备配对等
+// “双方只共享一个短口令（如 6 位数字码）”的场景：
+//
+//   - 口令不在线路上传输，被动窃听者无法离线猜测口令；
+//   - 主动中间人每次会话只能猜一次口令，猜错即被密钥确认发现（ErrConfirmationFailed），
+//     调用方应限制失败次数并在失败后更换配对码。
+//
+// 流程（A 为发起方，B 为响应方）：
+//
+//	A → B: A.Message()
+//	B → A: B.Message(), cB = B.Finish(pA)
+//	A → B: cA = A.Finish(pB)，A.Verify(cB)
+//	B:     B.Verify(cA)
+//
+// 双方 Verify 成功后用 ChaCha 得到可直接使用的 *chacha.ChaCha，或用 Key 取得 RFC 9382 的 Ke。
+//
+// 口令标量 w 由 HKDF-SHA512(口令, 标识) 宽约减得到；RFC 9382 建议用内存困难函数处理口令，
+// 持久保存口令派生值的场景应先用 hash 包的 Argon2 处理口令再传入。
+package spake2
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"slices"
+
+	"filippo.io/edwards25519"
+	"github.com/gtkit/encry/chacha"
+	"github.com/gtkit/encry/hkdf"
+)
+
+// MessageSize 为 Message 的长度（edwards25519 压缩点）。
+const MessageSize = 32
+
+// ConfirmationSize 为 Finish 返回的密钥确认值长度（HMAC-SHA256）。
+const ConfirmationSize = sha256.Size
+
+// RFC 9382 §4 为 edwards25519 给出的 M、N（由 "edwards25519 point generation seed (M/N)"
+// 哈希生成的素数阶点，无人知道其离散对数）。
+var (
+	pointM = mustPoint("d048032c6ea0b6d697ddc2e86bda85a33adac920f1bf18e1b0c6d166a5cecdaf")
+	pointN = mustPoint("d3bfb518f44f3430f29d0c92af503865a1ed3281dc69b35dd868ba85f886c4ab")
+)
+
+const (
+	passwordInfo = "encry spake2 w"
+	confirmInfo  = "ConfirmationKeys"
+	chachaInfo   = "encry spake2 chacha20poly1305"
+)
+
+var (
+	// ErrInvalidRole 表示角色不是 RoleA 或 RoleB。
+	ErrInvalidRole = errors.New("spake2: invalid role")
+	// ErrEmptyPassword 表示口令为空。
+	ErrEmptyPassword = errors.New("spake2: empty password")
+	// ErrInvalidMessage 表示对端消息不是合法的曲线点，或共享点为单位元（小阶点攻击）。
+	ErrInvalidMessage = errors.New("spake2: invalid message")
+	// ErrConfirmationFailed 表示密钥确认失败：口令不一致、标识不一致或存在中间人。
+	ErrConfirmationFailed = errors.New("spake2: key confirmation failed")
+	// ErrUnexpectedCall 表示调用顺序错误（如未 Finish 就 Verify、重复 Finish、确认前取密钥）。
+	ErrUnexpectedCall = errors.New("spake2: unexpected call")
+)
+
+// Role 是交换中的角色，双方必须一个为 RoleA、一个为 RoleB。
+type Role uint8
+
+const (
+	// RoleA 为发起方（RFC 9382 中的 A，使用 M 掩盖口令）。
+	RoleA Role = iota + 1
+	// RoleB 为响应方（RFC 9382 中的 B，使用 N 掩盖口令）。
+	RoleB
+)
+
+// Option 用于定制交换参数（Functional Options）。
+type Option func(*options)
+
+type options struct {
+	idA, idB []byte
+	aad      []byte
+	rand     io.Reader
+}
+
+// WithIdentities 指定双方标识（如设备 ID），写入口令派生与记录 TT，双方必须一致；默认为空。
+func WithIdentities(idA, idB []byte) Option {
+	return func(o *options) {
+		o.idA = bytes.Clone(idA)
+		o.idB = bytes.Clone(idB)
+	}
+}
+
+// WithAAD 指定附加认证数据（RFC 9382 的 AAD，如协议版本），参与确认密钥派生，双方必须一致。
+func WithAAD(aad []byte) Option {
+	return func(o *options) { o.aad = bytes.Clone(aad) }
+}
+
+// WithRand 指定生成临时标量的随机源（读取 64 字节），默认 crypto/rand。仅用于测试。
+func WithRand(r io.Reader) Option {
+	return func(o *options) { o.rand = r }
+}
+
+// Exchange 是一方的 SPAKE2 状态，只能使用一次，不是并发安全的。
+type Exchange struct {
+	role    Role
+	opts    *options
+	w       *edwards25519.Scalar
+	x       *edwards25519.Scalar
+	message []byte
+
+	ke, kcA, kcB, tt []byte
+	finished         bool
+	verified         bool
+	confirmed        bool
+}
+
+// New 以口令创建一方的交换状态。
+func New(role Role, password []byte, opts ...Option) (*Exchange, error) {
+	if role != RoleA && role != RoleB {
+		return nil, ErrInvalidRole
+	}
+	if len(password) == 0 {
+		return nil, ErrEmptyPassword
+	}
+	o := &options{rand: rand.Reader}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+
+	w, err := passwordScalar(password, o.idA, o.idB)
+	if err != nil {
+		return nil, err
+	}
+	seed := make([]byte, 64)
+	defer clear(seed)
+	if _, err := io.ReadFull(o.rand, seed); err != nil {
+		return nil, err
+	}
+	x, err := edwards25519.NewScalar().SetUniformBytes(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	// pA = x·P + w·M，pB = y·P + w·N。
+	mask := pointM
+	if role == RoleB {
+		mask = pointN
+	}
+	share := new(edwards25519.Point).ScalarBaseMult(x)
+	share.Add(share, new(edwards25519.Point).ScalarMult(w, mask))
+	return &Exchange{role: role, opts: o, w: w, x: x, message: share.Bytes()}, nil
+}
+
+// Message 返回发给对端的消息（本方的 pA 或 pB）。
+func (e *Exchange) Message() []byte {
+	return bytes.Clone(e.message)
+}
+
+// Finish 处理对端消息，计算共享密钥并返回发给对端的密钥确认值（cA 或 cB）。只能调用一次。
+func (e *Exchange) Finish(peerMessage []byte) ([]byte, error) {
+	if e.finished {
+		return nil, ErrUnexpectedCall
+	}
+	peer, err := new(edwards25519.Point).SetBytes(peerMessage)
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+
+	// K = h·x·(pB − w·N)（A 侧）或 h·y·(pA − w·M)（B 侧），h = 8 为余因子。
+	peerMask := pointN
+	if e.role == RoleB {
+		peerMask = pointM
+	}
+	k := new(edwards25519.Point).ScalarMult(e.w, peerMask)
+	k.Subtract(peer, k)
+	k.ScalarMult(e.x, k)
+	k.MultByCofactor(k)
+	if k.Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, ErrInvalidMessage
+	}
+
+	pA, pB := e.message, peerMessage
+	if e.role == RoleB {
+		pA, pB = peerMessage, e.message
+	}
+	e.tt = transcript(e.opts.idA, e.opts.idB, pA, pB, k.Bytes(), scalarBytes(e.w))
+
+	// Ke || Ka = Hash(TT)；KcA || KcB = KDF(nil, Ka, "ConfirmationKeys" || AAD)。
+	digest := sha256.Sum256(e.tt)
+	ka := digest[sha256.Size/2:]
+	e.ke = bytes.Clone(digest[:sha256.Size/2])
+	kc, err := hkdf.Derive(ka, nil, confirmInfo+string(e.opts.aad), sha256.Size)
+	clear(digest[:])
+	if err != nil {
+		return nil, err
+	}
+	e.kcA, e.kcB = kc[:sha256.Size/2], kc[sha256.Size/2:]
+	e.finished = true
+
+	own := e.kcA
+	if e.role == RoleB {
+		own = e.kcB
+	}
+	return confirmation(own, e.tt), nil
+}
+
+// Verify 校验对端的密钥确认值，成功后 Key/ChaCha 才可用。只能调用一次，失败后须重新开始交换。
+func (e *Exchange) Verify(peerConfirmation []byte) error {
+	if !e.finished || e.verified {
+		return ErrUnexpectedCall
+	}
+	e.verified = true
+	peerKey := e.kcB
+	if e.role == RoleB {
+		peerKey = e.kcA
+	}
+	if !hmac.Equal(peerConfirmation, confirmation(peerKey, e.tt)) {
+		return ErrConfirmationFailed
+	}
+	e.confirmed = true
+	return nil
+}
+
+// Key 返回 RFC 9382 的共享密钥 Ke（16 字节）；需先 Verify 成功。
+func (e *Exchange) Key() ([]byte, error) {
+	if !e.confirmed {
+		return nil, ErrUnexpectedCall
+	}
+	return bytes.Clone(e.ke), nil
+}
+
+// ChaCha 由 Ke 经 HKDF-SHA256 派生 32 字节密钥并返回 XChaCha20-Poly1305 实例；需先 Verify 成功。
+func (e *Exchange) ChaCha() (*chacha.ChaCha, error) {
+	if !e.confirmed {
+		return nil, ErrUnexpectedCall
+	}
+	key, err := hkdf.Derive(e.ke, nil, chachaInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(key)
+	return chacha.NewChaCha(key)
+}
+
+// passwordScalar 由口令与双方标识派生口令标量 w（64 字节输出宽约减，近似均匀分布）。
+func passwordScalar(password, idA, idB []byte) (*edwards25519.Scalar, error) {
+	ikm := transcript(password, idA, idB)
+	defer clear(ikm)
+	wide, err := hkdf.Derive(ikm, nil, passwordInfo, 64, hkdf.WithHash(crypto.SHA512))
+	if err != nil {
+		return nil, err
+	}
+	defer clear(wide)
+	return edwards25519.NewScalar().SetUniformBytes(wide)
+}
+
+// transcript 按 RFC 9382 §3.3 编码：每个字段前缀 8 字节小端长度。
+func transcript(fields ...[]byte) []byte {
+	var out []byte
+	for _, f := range fields {
+		out = binary.LittleEndian.AppendUint64(out, uint64(len(f)))
+		out = append(out, f...)
+	}
+	return out
+}
+
+// scalarBytes 把 w 编码为 32 字节大端整数写入 TT（edwards25519 标量本身为小端）。
+func scalarBytes(s *edwards25519.Scalar) []byte {
+	b := s.Bytes()
+	slices.Reverse(b)
+	return b
+}
+
+func confirmation(key, tt []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(tt)
+	return mac.Sum(nil)
+}
+
+func mustPoint(s string) *edwards25519.Point {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	p, err := new(edwards25519.Point).SetBytes(b)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
@@ -0,0 +1,27 @@
+package sshsig_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gtkit/encry/ed"
+	"github.com/gtkit/encry/sshsig"
+)
+
+func ExampleSignReader() {
+	publicKey, privateKey, err := ed.GenerateKeyPair()
+	if err != nil {
+		panic(err)
+	}
+	artifact := []byte("release tarball bytes")
+
+	// 输出可直接用 `ssh-keygen -Y verify -n file` 校验。
+	signature, err := sshsig.SignReader(privateKey, bytes.NewReader(artifact), "file")
+	if err != nil {
+		panic(err)
+	}
+
+	ok, err := sshsig.VerifyReader(publicKey, bytes.NewReader(artifact), signature, "file")
+	fmt.Println(ok, err)
+	// Output: true <nil>
+}
@@ -0,0 +1,321 @@
+// Package sshsig 实现 OpenSSH 的 SSHSIG 签名格式（PROTOCOL.sshsig），与
+// `ssh-keygen -Y sign` / `ssh-keygen -Y verify` 互通。
+//
+// 开发者可直接用已有的 Ed25519/ECDSA/RSA SSH 密钥为发布产物签名：
+//
+//	key, _ := ed.ParseOpenSSHPrivateKey(idEd25519)
+//	sig, _ := sshsig.SignReader(key, artifact, "file")
+//	ok, _ := sshsig.VerifyReader(key.Public(), artifact, sig, "file")
+//
+// namespace 用于域分离（ssh-keygen 约定 "file" 用于文件、"git" 用于 git 提交），
+// 不同 namespace 的签名互不通用。消息先做 SHA-512（默认）或 SHA-256，可流式处理大文件。
+package sshsig
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"hash"
+	"io"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	magicPreamble = "SSHSIG"
+	sigVersion    = 1
+	pemType       = "SSH SIGNATURE"
+	// armorWidth 与 ssh-keygen 输出的 Base64 行宽一致。
+	armorWidth = 70
+
+	hashSHA256 = "sha256"
+	hashSHA512 = "sha512"
+)
+
+var (
+	// ErrInvalidSignature 表示签名不是合法的 SSHSIG 编码。
+	ErrInvalidSignature = errors.New("sshsig: malformed signature")
+	// ErrInvalidNamespace 表示 namespace 为空。
+	ErrInvalidNamespace = errors.New("sshsig: namespace must not be empty")
+	// ErrUnsupportedHash 表示摘要算法不是 SHA-256 或 SHA-512。
+	ErrUnsupportedHash = errors.New("sshsig: unsupported hash algorithm")
+	// ErrInvalidKey 表示密钥为 nil 或类型不受 SSH 支持。
+	ErrInvalidKey = errors.New("sshsig: invalid key")
+	// ErrNilReader 表示 SignReader/VerifyReader 收到 nil io.Reader。
+	ErrNilReader = errors.New("sshsig: nil reader")
+)
+
+// Signature 是解析后的 SSHSIG 签名。
+type Signature struct {
+	// PublicKey 为签名中携带的签名者公钥；验签时必须与期望公钥比对，不能直接信任。
+	PublicKey ssh.PublicKey
+	// Namespace 为签名时使用的命名空间。
+	Namespace string
+	// HashAlgorithm 为消息摘要算法，"sha256" 或 "sha512"。
+	HashAlgorithm string
+	// Signature 为对签名数据的 SSH 签名。
+	Signature *ssh.Signature
+}
+
+// Option 用于定制签名参数（Functional Options）。
+type Option func(*options)
+
+type options struct {
+	hash crypto.Hash
+}
+
+// WithHash 指定消息摘要算法，仅支持 crypto.SHA256 与 crypto.SHA512（默认）。
+func WithHash(h crypto.Hash) Option {
+	return func(o *options) { o.hash = h }
+}
+
+// Sign 用 signer 对 message 生成 ASCII 铠装的 SSHSIG 签名（"-----BEGIN SSH SIGNATURE-----"）。
+//
+// signer 可以是 ed25519.PrivateKey、*ecdsa.PrivateKey 或 *rsa.PrivateKey；
+// RSA 固定使用 rsa-sha2-512。
+func Sign(signer crypto.Signer, message []byte, namespace string, opts ...Option) ([]byte, error) {
+	return SignReader(signer, bytes.NewReader(message), namespace, opts...)
+}
+
+// SignReader 流式读取 r 并生成 SSHSIG 签名，内存占用与数据大小无关。
+func SignReader(signer crypto.Signer, r io.Reader, namespace string, opts ...Option) ([]byte, error) {
+	if signer == nil {
+		return nil, ErrInvalidKey
+	}
+	if namespace == "" {
+		return nil, ErrInvalidNamespace
+	}
+	o := &options{hash: crypto.SHA512}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	hashName, h, err := newHash(o.hash)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := digestReader(h, r)
+	if err != nil {
+		return nil, err
+	}
+
+	sshSigner, err := ssh.NewSignerFromSigner(signer)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidKey, err)
+	}
+	data := signedData(namespace, hashName, digest)
+
+	var sig *ssh.Signature
+	if _, isRSA := signer.Public().(*rsa.PublicKey); isRSA {
+		algSigner, ok := sshSigner.(ssh.AlgorithmSigner)
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		sig, err = algSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = sshSigner.Sign(rand.Reader, data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return (&Signature{
+		PublicKey:     sshSigner.PublicKey(),
+		Namespace:     namespace,
+		HashAlgorithm: hashName,
+		Signature:     sig,
+	}).Marshal(), nil
+}
+
+// Verify 校验 SSHSIG 签名，返回 (是否有效, 操作性错误)。
+//
+// publicKey 为期望的签名者公钥（ed25519.PublicKey、*ecdsa.PublicKey、*rsa.PublicKey 或 ssh.PublicKey）；
+// 签名中的公钥与之不符、namespace 不符或签名不匹配时返回 (false, nil)；
+// 签名编码非法时返回 ErrInvalidSignature。
+func Verify(publicKey crypto.PublicKey, message, signature []byte, namespace string) (bool, error) {
+	return VerifyReader(publicKey, bytes.NewReader(message), signature, namespace)
+}
+
+// VerifyReader 流式读取 r 并校验 SSHSIG 签名，返回 (是否有效, 操作性错误)。
+func VerifyReader(publicKey crypto.PublicKey, r io.Reader, signature []byte, namespace string) (bool, error) {
+	expected, err := toSSHPublicKey(publicKey)
+	if err != nil {
+		return false, err
+	}
+	if namespace == "" {
+		return false, ErrInvalidNamespace
+	}
+	sig, err := Parse(signature)
+	if err != nil {
+		return false, err
+	}
+	_, h, err := newHashByName(sig.HashAlgorithm)
+	if err != nil {
+		return false, err
+	}
+	digest, err := digestReader(h, r)
+	if err != nil {
+		return false, err
+	}
+
+	if sig.Namespace != namespace {
+		return false, nil
+	}
+	if !bytes.Equal(sig.PublicKey.Marshal(), expected.Marshal()) {
+		return false, nil
+	}
+	// 与 ssh-keygen 一致：拒绝基于 SHA-1 的 ssh-rsa 签名。
+	if sig.Signature.Format == ssh.KeyAlgoRSA {
+		return false, nil
+	}
+	return expected.Verify(signedData(sig.Namespace, sig.HashAlgorithm, digest), sig.Signature) == nil, nil
+}
+
+// Parse 解析 ASCII 铠装的 SSHSIG 签名，不做任何校验。
+func Parse(armored []byte) (*Signature, error) {
+	block, _ := pem.Decode(armored)
+	if block == nil || block.Type != pemType {
+		return nil, ErrInvalidSignature
+	}
+	return parseBlob(block.Bytes)
+}
+
+// Marshal 将签名编码为 ssh-keygen 相同格式的 ASCII 铠装（Base64 每行 70 字符）。
+func (s *Signature) Marshal() []byte {
+	var blob []byte
+	blob = append(blob, magicPreamble...)
+	blob = binary.BigEndian.AppendUint32(blob, sigVersion)
+	blob = appendString(blob, s.PublicKey.Marshal())
+	blob = appendString(blob, []byte(s.Namespace))
+	blob = appendString(blob, nil) // reserved
+	blob = appendString(blob, []byte(s.HashAlgorithm))
+	blob = appendString(blob, ssh.Marshal(s.Signature))
+
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var buf bytes.Buffer
+	buf.WriteString("-----BEGIN " + pemType + "-----\n")
+	for len(encoded) > armorWidth {
+		buf.WriteString(encoded[:armorWidth])
+		buf.WriteByte('\n')
+		encoded = encoded[armorWidth:]
+	}
+	buf.WriteString(encoded)
+	buf.WriteString("\n-----END " + pemType + "-----\n")
+	return buf.Bytes()
+}
+
+func parseBlob(blob []byte) (*Signature, error) {
+	rest, ok := bytes.CutPrefix(blob, []byte(magicPreamble))
+	if !ok || len(rest) < 4 {
+		return nil, ErrInvalidSignature
+	}
+	if binary.BigEndian.Uint32(rest) != sigVersion {
+		return nil, ErrInvalidSignature
+	}
+	rest = rest[4:]
+
+	fields := make([][]byte, 5)
+	for i := range fields {
+		if fields[i], rest, ok = readString(rest); !ok {
+			return nil, ErrInvalidSignature
+		}
+	}
+	if len(rest) != 0 {
+		return nil, ErrInvalidSignature
+	}
+
+	pub, err := ssh.ParsePublicKey(fields[0])
+	if err != nil {
+		return nil, errors.Join(ErrInvalidSignature, err)
+	}
+	sig := new(ssh.Signature)
+	if err := ssh.Unmarshal(fields[4], sig); err != nil {
+		return nil, errors.Join(ErrInvalidSignature, err)
+	}
+	return &Signature{
+		PublicKey:     pub,
+		Namespace:     string(fields[1]),
+		HashAlgorithm: string(fields[3]),
+		Signature:     sig,
+	}, nil
+}
+
+// signedData 构造实际被签名的数据：MAGIC || string(namespace) || string(reserved) || string(hash_alg) || string(H(msg))。
+func signedData(namespace, hashName string, digest []byte) []byte {
+	data := []byte(magicPreamble)
+	data = appendString(data, []byte(namespace))
+	data = appendString(data, nil)
+	data = appendString(data, []byte(hashName))
+	return appendString(data, digest)
+}
+
+func toSSHPublicKey(publicKey crypto.PublicKey) (ssh.PublicKey, error) {
+	if publicKey == nil {
+		return nil, ErrInvalidKey
+	}
+	if pub, ok := publicKey.(ssh.PublicKey); ok {
+		return pub, nil
+	}
+	pub, err := ssh.NewPublicKey(publicKey)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidKey, err)
+	}
+	return pub, nil
+}
+
+func newHash(h crypto.Hash) (string, hash.Hash, error) {
+	switch h {
+	case crypto.SHA256:
+		return hashSHA256, sha256.New(), nil
+	case crypto.SHA512:
+		return hashSHA512, sha512.New(), nil
+	default:
+		return "", nil, ErrUnsupportedHash
+	}
+}
+
+func newHashByName(name string) (string, hash.Hash, error) {
+	switch name {
+	case hashSHA256:
+		return newHash(crypto.SHA256)
+	case hashSHA512:
+		return newHash(crypto.SHA512)
+	default:
+		return "", nil, ErrUnsupportedHash
+	}
+}
+
+func digestReader(h hash.Hash, r io.Reader) ([]byte, error) {
+	if r == nil {
+		return nil, ErrNilReader
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func appendString(b, s []byte) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s))) // #nosec G115 -- SSH 字段长度远小于 4GiB.
+	return append(b, s...)
+}
+
+func readString(b []byte) ([]byte, []byte, bool) {
+	if len(b) < 4 {
+		return nil, nil, false
+	}
+	n := binary.BigEndian.Uint32(b)
+	b = b[4:]
+	if uint64(n) > uint64(len(b)) {
+		return nil, nil, false
+	}
+	return b[:n], b[n:], true
+}
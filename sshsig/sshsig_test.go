@@ -0,0 +1,215 @@
+package sshsig_test
+
+import (
+	"bytes"
+	"crypto"
+	stdecdsa "crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/ecdsa"
+	"github.com/gtkit/encry/ed"
+	"github.com/gtkit/encry/sshsig"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	return data
+}
+
+func authorizedKey(t *testing.T, name string) ssh.PublicKey {
+	t.Helper()
+	pub, _, _, _, err := ssh.ParseAuthorizedKey(readFixture(t, name))
+	require.NoError(t, err)
+	return pub
+}
+
+// testdata 中的签名由 OpenSSH 9.2 的 `ssh-keygen -Y sign` 生成。
+func TestVerifySSHKeygenFixtures(t *testing.T) {
+	t.Parallel()
+
+	artifact := readFixture(t, "artifact.txt")
+	tests := []struct {
+		name      string
+		pub       string
+		sig       string
+		namespace string
+		hashAlg   string
+	}{
+		{name: "ed25519", pub: "id_ed25519.pub", sig: "id_ed25519.file.sig", namespace: "file"},
+		{name: "ecdsa p256", pub: "id_ecdsa.pub", sig: "id_ecdsa.file.sig", namespace: "file"},
+		{name: "ecdsa p384 encrypted key", pub: "id_ecdsa384_enc.pub", sig: "id_ecdsa384_enc.git.sig", namespace: "git"},
+		{name: "rsa", pub: "id_rsa.pub", sig: "id_rsa.file.sig", namespace: "file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pub := authorizedKey(t, tt.pub)
+			sig := readFixture(t, tt.sig)
+
+			parsed, err := sshsig.Parse(sig)
+			require.NoError(t, err)
+			require.Equal(t, tt.namespace, parsed.Namespace)
+			require.Equal(t, "sha512", parsed.HashAlgorithm)
+			require.Equal(t, pub.Marshal(), parsed.PublicKey.Marshal())
+
+			ok, err := sshsig.Verify(pub, artifact, sig, tt.namespace)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			ok, err = sshsig.VerifyReader(pub.(ssh.CryptoPublicKey).CryptoPublicKey(), bytes.NewReader(artifact), sig, tt.namespace)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			ok, err = sshsig.Verify(pub, artifact, sig, "other")
+			require.NoError(t, err)
+			require.False(t, ok)
+
+			ok, err = sshsig.Verify(pub, append([]byte("x"), artifact...), sig, tt.namespace)
+			require.NoError(t, err)
+			require.False(t, ok)
+		})
+	}
+}
+
+func TestSignMatchesSSHKeygen(t *testing.T) {
+	t.Parallel()
+
+	// Ed25519 签名是确定性的，输出应与 ssh-keygen 逐字节一致。
+	key, err := ed.ParseOpenSSHPrivateKey(readFixture(t, "id_ed25519"))
+	require.NoError(t, err)
+	sig, err := sshsig.Sign(key, readFixture(t, "artifact.txt"), "file")
+	require.NoError(t, err)
+	require.Equal(t, string(readFixture(t, "id_ed25519.file.sig")), string(sig))
+}
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	_, edKey, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKeyWithCurve(elliptic.P521())
+	require.NoError(t, err)
+	rsaKey, err := stdrsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		signer crypto.Signer
+		hash   crypto.Hash
+		format string
+	}{
+		{name: "ed25519", signer: edKey, hash: crypto.SHA512, format: ssh.KeyAlgoED25519},
+		{name: "ecdsa p521 sha256", signer: ecKey, hash: crypto.SHA256, format: ssh.KeyAlgoECDSA521},
+		{name: "rsa", signer: rsaKey, hash: crypto.SHA512, format: ssh.KeyAlgoRSASHA512},
+	}
+
+	msg := []byte("release v2.0.0")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sig, err := sshsig.Sign(tt.signer, msg, "file", sshsig.WithHash(tt.hash))
+			require.NoError(t, err)
+
+			parsed, err := sshsig.Parse(sig)
+			require.NoError(t, err)
+			require.Equal(t, tt.format, parsed.Signature.Format)
+			require.Equal(t, sig, parsed.Marshal())
+
+			ok, err := sshsig.Verify(tt.signer.Public(), msg, sig, "file")
+			require.NoError(t, err)
+			require.True(t, ok)
+		})
+	}
+}
+
+func TestVerifyRejectsOtherKey(t *testing.T) {
+	t.Parallel()
+
+	sig := readFixture(t, "id_ed25519.file.sig")
+	other, _, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+
+	// 签名自带公钥，但验签只信任调用方给出的期望公钥。
+	ok, err := sshsig.Verify(other, readFixture(t, "artifact.txt"), sig, "file")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+	sig, err := sshsig.Sign(priv, []byte("m"), "file")
+	require.NoError(t, err)
+
+	_, err = sshsig.Sign(nil, []byte("m"), "file")
+	require.ErrorIs(t, err, sshsig.ErrInvalidKey)
+	_, err = sshsig.Sign(priv, []byte("m"), "")
+	require.ErrorIs(t, err, sshsig.ErrInvalidNamespace)
+	_, err = sshsig.Sign(priv, []byte("m"), "file", sshsig.WithHash(crypto.SHA1))
+	require.ErrorIs(t, err, sshsig.ErrUnsupportedHash)
+	_, err = sshsig.SignReader(priv, nil, "file")
+	require.ErrorIs(t, err, sshsig.ErrNilReader)
+
+	_, err = sshsig.Verify(nil, []byte("m"), sig, "file")
+	require.ErrorIs(t, err, sshsig.ErrInvalidKey)
+	_, err = sshsig.Verify(ed25519.PublicKey{1, 2}, []byte("m"), sig, "file")
+	require.ErrorIs(t, err, sshsig.ErrInvalidKey)
+	_, err = sshsig.Verify(pub, []byte("m"), sig, "")
+	require.ErrorIs(t, err, sshsig.ErrInvalidNamespace)
+
+	readErr := errors.New("read failure")
+	_, err = sshsig.VerifyReader(pub, failingReader{readErr}, sig, "file")
+	require.ErrorIs(t, err, readErr)
+
+	malformed := [][]byte{
+		[]byte("not armored"),
+		[]byte("-----BEGIN SSH SIGNATURE-----\nAAAA\n-----END SSH SIGNATURE-----\n"),
+		bytes.Replace(sig, []byte("BEGIN SSH SIGNATURE"), []byte("BEGIN PGP SIGNATURE"), 1),
+		[]byte("-----BEGIN SSH SIGNATURE-----\n" + "U1NIU0lHAAAAAgAAAAA=" + "\n-----END SSH SIGNATURE-----\n"),
+	}
+	for i, m := range malformed {
+		_, err = sshsig.Verify(pub, []byte("m"), m, "file")
+		require.ErrorIs(t, err, sshsig.ErrInvalidSignature, "case %d", i)
+	}
+}
+
+func TestWrongSignatureAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	// 把 ECDSA 公钥嵌入 Ed25519 签名：公钥匹配但签名格式不符，应判无效而非报错。
+	ecKey, err := stdecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+
+	sig, err := sshsig.Sign(edKey, []byte("m"), "file")
+	require.NoError(t, err)
+	parsed, err := sshsig.Parse(sig)
+	require.NoError(t, err)
+	parsed.PublicKey, err = ssh.NewPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+
+	ok, err := sshsig.Verify(&ecKey.PublicKey, []byte("m"), parsed.Marshal(), "file")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.True(t, strings.HasPrefix(string(parsed.Marshal()), "-----BEGIN SSH SIGNATURE-----\n"))
+}
+
+type failingReader struct{ err error }
+
+func (r failingReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,54 @@
+package x509ca
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+)
+
+const (
+	pemTypeCSR         = "CERTIFICATE REQUEST"
+	pemTypeCertificate = "CERTIFICATE"
+	pemTypePrivateKey  = "PRIVATE KEY"
+)
+
+// CreateCSR 用 key 生成 PEM 编码的 PKCS#10 证书签名请求。
+//
+// key 可以是 rsa/ecdsa/ed 包生成的任意私钥（*rsa.PrivateKey、*ecdsa.PrivateKey、
+// ed25519.PrivateKey）；SAN 通过 WithDNSNames/WithIPAddresses 等选项设置，
+// 有效期与 EKU 类选项在 CSR 中被忽略，由签发方决定。
+func CreateCSR(key crypto.Signer, subject pkix.Name, opts ...Option) ([]byte, error) {
+	if key == nil {
+		return nil, ErrInvalidKey
+	}
+	o := newOptions(defaultLeafValidity, opts)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:        subject,
+		DNSNames:       o.dnsNames,
+		IPAddresses:    o.ipAddresses,
+		EmailAddresses: o.emails,
+		URIs:           o.uris,
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeCSR, Bytes: der}), nil
+}
+
+// ParseCSRPEM 解析 PEM 编码的 CSR 并校验其自签名。
+func ParseCSRPEM(data []byte) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemTypeCSR {
+		return nil, ErrInvalidCSR
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, err
+	}
+	return csr, nil
+}
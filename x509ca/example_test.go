@@ -0,0 +1,43 @@
+package x509ca_test
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+
+	"github.com/gtkit/encry/ecdsa"
+	"github.com/gtkit/encry/x509ca"
+)
+
+func ExampleCA_SignCSR() {
+	caKey, err := ecdsa.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+	ca, err := x509ca.NewCA(caKey, pkix.Name{CommonName: "internal root"})
+	if err != nil {
+		panic(err)
+	}
+
+	// 服务方生成密钥与 CSR，CA 只看到 CSR。
+	svcKey, err := ecdsa.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+	csrPEM, err := x509ca.CreateCSR(svcKey, pkix.Name{CommonName: "api"}, x509ca.WithDNSNames("api.internal"))
+	if err != nil {
+		panic(err)
+	}
+	certPEM, err := ca.SignCSR(csrPEM)
+	if err != nil {
+		panic(err)
+	}
+
+	cert, err := x509ca.ParseCertificatePEM(certPEM)
+	if err != nil {
+		panic(err)
+	}
+	_, err = cert.Verify(x509.VerifyOptions{Roots: ca.CertPool(), DNSName: "api.internal"})
+	fmt.Println(cert.Subject.CommonName, err == nil)
+	// Output: api true
+}
@@ -0,0 +1,99 @@
+package x509ca
+
+import (
+	"crypto/x509"
+	"net"
+	"net/url"
+	"time"
+)
+
+// 默认有效期：CA 10 年，叶子证书 90 天。
+const (
+	defaultCAValidity   = 10 * 365 * 24 * time.Hour
+	defaultLeafValidity = 90 * 24 * time.Hour
+	// clockSkew 让 NotBefore 略早于当前时间，容忍机器间时钟偏差。
+	clockSkew = 5 * time.Minute
+)
+
+// Option 用于定制 CSR / 证书模板（Functional Options）。
+type Option func(*options)
+
+type options struct {
+	dnsNames    []string
+	ipAddresses []net.IP
+	emails      []string
+	uris        []*url.URL
+	extKeyUsage []x509.ExtKeyUsage
+	validity    time.Duration
+	notBefore   time.Time
+	maxPathLen  int
+	sansSet     bool
+}
+
+// WithDNSNames 设置 DNS 类型的 SAN。
+func WithDNSNames(names ...string) Option {
+	return func(o *options) {
+		o.dnsNames = append(o.dnsNames, names...)
+		o.sansSet = true
+	}
+}
+
+// WithIPAddresses 设置 IP 类型的 SAN。
+func WithIPAddresses(ips ...net.IP) Option {
+	return func(o *options) {
+		o.ipAddresses = append(o.ipAddresses, ips...)
+		o.sansSet = true
+	}
+}
+
+// WithEmailAddresses 设置 Email 类型的 SAN。
+func WithEmailAddresses(emails ...string) Option {
+	return func(o *options) {
+		o.emails = append(o.emails, emails...)
+		o.sansSet = true
+	}
+}
+
+// WithURIs 设置 URI 类型的 SAN（如 SPIFFE ID）。
+func WithURIs(uris ...*url.URL) Option {
+	return func(o *options) {
+		o.uris = append(o.uris, uris...)
+		o.sansSet = true
+	}
+}
+
+// WithExtKeyUsage 设置扩展密钥用途；叶子证书默认同时包含 ServerAuth 与 ClientAuth。
+func WithExtKeyUsage(usages ...x509.ExtKeyUsage) Option {
+	return func(o *options) { o.extKeyUsage = append(o.extKeyUsage, usages...) }
+}
+
+// WithValidity 设置有效期长度（自 NotBefore 起算）；由 CA 签发时 NotAfter 最晚为签发 CA 的 NotAfter。
+func WithValidity(d time.Duration) Option {
+	return func(o *options) { o.validity = d }
+}
+
+// WithNotBefore 设置生效时间，默认当前时间减 5 分钟。
+func WithNotBefore(t time.Time) Option {
+	return func(o *options) { o.notBefore = t }
+}
+
+// WithMaxPathLen 限制 CA 证书之下允许的中间 CA 层数，仅对 CA 证书生效；默认不限制。
+func WithMaxPathLen(n int) Option {
+	return func(o *options) { o.maxPathLen = n }
+}
+
+func newOptions(defaultValidity time.Duration, opts []Option) *options {
+	o := &options{maxPathLen: -1}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	if o.validity <= 0 {
+		o.validity = defaultValidity
+	}
+	if o.notBefore.IsZero() {
+		o.notBefore = time.Now().Add(-clockSkew)
+	}
+	return o
+}
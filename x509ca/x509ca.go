@@ -0,0 +1,362 @@
+// Package x509ca 提供 X.509 CSR 生成与一个最小化的进程内证书颁发机构（CA）。
+//
+// 用于内部服务证书与集成测试中的 mTLS，无需再调用 openssl：
+//
+//	caKey, _ := ecdsa.GenerateKey()
+//	ca, _ := x509ca.NewCA(caKey, pkix.Name{CommonName: "test root"})
+//	leafKey, _ := ecdsa.GenerateKey()
+//	certPEM, _ := ca.Issue(leafKey.Public(), pkix.Name{CommonName: "svc"}, x509ca.WithDNSNames("svc.local"))
+//	tlsCert, _ := ca.TLSCertificate(certPEM, leafKey)
+//
+// 密钥可以来自 rsa/ecdsa/ed 任一包（任何 crypto.Signer）。叶子证书默认有效期 90 天、
+// EKU 同时包含 ServerAuth 与 ClientAuth；CA 证书默认有效期 10 年。签发的证书（含中间 CA）
+// 的 NotAfter 不会晚于签发 CA 的 NotAfter。
+package x509ca
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+)
+
+var (
+	// ErrInvalidKey 表示密钥为 nil 或类型不受支持。
+	ErrInvalidKey = errors.New("x509ca: invalid key")
+	// ErrInvalidCSR 表示 PEM 不是有效的证书签名请求。
+	ErrInvalidCSR = errors.New("x509ca: invalid certificate request")
+	// ErrInvalidCertificate 表示 PEM 不是有效的证书，或证书不是 CA 证书。
+	ErrInvalidCertificate = errors.New("x509ca: invalid certificate")
+	// ErrKeyMismatch 表示 CA 证书与私钥不匹配。
+	ErrKeyMismatch = errors.New("x509ca: certificate does not match private key")
+	// ErrIssuerExpired 表示新证书的生效时间不早于签发 CA 的过期时间。
+	ErrIssuerExpired = errors.New("x509ca: validity starts after issuer expires")
+)
+
+// CA 是一个进程内证书颁发机构，创建后只读，可被多个 goroutine 并发使用。
+type CA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+	// chain 为本 CA 证书及其上级中间 CA 证书（DER，不含自签根证书），用于拼接 TLS 证书链。
+	chain [][]byte
+}
+
+// NewCA 用 key 创建一个自签名根 CA。
+func NewCA(key crypto.Signer, subject pkix.Name, opts ...Option) (*CA, error) {
+	if key == nil {
+		return nil, ErrInvalidKey
+	}
+	o := newOptions(defaultCAValidity, opts)
+	tmpl, err := caTemplate(subject, o)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{cert: cert, key: key}, nil
+}
+
+// LoadCA 从 PEM 编码的 CA 证书与私钥（PKCS#8、PKCS#1 或 SEC1）恢复 CA。
+// certPEM 可以包含多张证书：第一张为本 CA，其后为上级中间 CA。
+func LoadCA(certPEM, keyPEM []byte) (*CA, error) {
+	certs, err := parseCertificatesPEM(certPEM)
+	if err != nil {
+		return nil, err
+	}
+	if !certs[0].IsCA {
+		return nil, ErrInvalidCertificate
+	}
+	key, err := ParsePrivateKeyPEM(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	if !publicKeyEqual(certs[0].PublicKey, key.Public()) {
+		return nil, ErrKeyMismatch
+	}
+
+	ca := &CA{cert: certs[0], key: key}
+	for _, c := range certs {
+		if !isSelfSigned(c) {
+			ca.chain = append(ca.chain, c.Raw)
+		}
+	}
+	return ca, nil
+}
+
+// Certificate 返回 CA 证书。
+func (ca *CA) Certificate() *x509.Certificate {
+	return ca.cert
+}
+
+// CertificatePEM 返回 PEM 编码的 CA 证书。
+func (ca *CA) CertificatePEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: ca.cert.Raw})
+}
+
+// CertPool 返回只包含本 CA 证书的 CertPool，可直接用作 tls.Config 的 RootCAs/ClientCAs。
+func (ca *CA) CertPool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+// NewIntermediate 用本 CA 签发一个中间 CA。
+func (ca *CA) NewIntermediate(key crypto.Signer, subject pkix.Name, opts ...Option) (*CA, error) {
+	if key == nil {
+		return nil, ErrInvalidKey
+	}
+	o := newOptions(defaultCAValidity, opts)
+	tmpl, err := caTemplate(subject, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := ca.clampValidity(tmpl); err != nil {
+		return nil, err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	chain := append([][]byte{der}, ca.chain...)
+	return &CA{cert: cert, key: key, chain: chain}, nil
+}
+
+// Issue 为公钥 pub 签发叶子证书，返回 PEM。
+func (ca *CA) Issue(pub crypto.PublicKey, subject pkix.Name, opts ...Option) ([]byte, error) {
+	if pub == nil {
+		return nil, ErrInvalidKey
+	}
+	o := newOptions(defaultLeafValidity, opts)
+	tmpl, err := leafTemplate(pub, subject, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := ca.clampValidity(tmpl); err != nil {
+		return nil, err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, pub, ca.key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der}), nil
+}
+
+// SignCSR 校验 CSR 自签名后签发叶子证书，返回 PEM。
+//
+// 证书的 Subject 取自 CSR；若 opts 中设置了任一 SAN 选项，则以 opts 的 SAN 为准
+// （忽略 CSR 中申请的 SAN），否则沿用 CSR 中的 SAN。
+func (ca *CA) SignCSR(csrPEM []byte, opts ...Option) ([]byte, error) {
+	csr, err := ParseCSRPEM(csrPEM)
+	if err != nil {
+		return nil, err
+	}
+	o := newOptions(defaultLeafValidity, opts)
+	if !o.sansSet {
+		o.dnsNames = csr.DNSNames
+		o.ipAddresses = csr.IPAddresses
+		o.emails = csr.EmailAddresses
+		o.uris = csr.URIs
+	}
+	tmpl, err := leafTemplate(csr.PublicKey, csr.Subject, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := ca.clampValidity(tmpl); err != nil {
+		return nil, err
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeCertificate, Bytes: der}), nil
+}
+
+// ChainPEM 把叶子证书与本 CA 的证书链（不含自签根证书）拼接为 PEM，
+// 可直接作为 tls.X509KeyPair 的 certPEMBlock。
+func (ca *CA) ChainPEM(leafPEM []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSpace(leafPEM))
+	buf.WriteByte('\n')
+	for _, der := range ca.chain {
+		_ = pem.Encode(&buf, &pem.Block{Type: pemTypeCertificate, Bytes: der})
+	}
+	return buf.Bytes()
+}
+
+// TLSCertificate 用叶子证书 PEM、本 CA 的证书链与叶子私钥组装 tls.Certificate。
+func (ca *CA) TLSCertificate(leafPEM []byte, key crypto.Signer) (tls.Certificate, error) {
+	keyPEM, err := MarshalPrivateKeyPEM(key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(ca.ChainPEM(leafPEM), keyPEM)
+}
+
+// MarshalPrivateKeyPEM 将 crypto.Signer 私钥编码为 PKCS#8 PEM。
+func MarshalPrivateKeyPEM(key crypto.Signer) ([]byte, error) {
+	if key == nil {
+		return nil, ErrInvalidKey
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: der}), nil
+}
+
+// ParsePrivateKeyPEM 解析 PKCS#8、PKCS#1（RSA）或 SEC1（EC）PEM 私钥。
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidKey
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, ErrInvalidKey
+}
+
+// ParseCertificatePEM 解析 PEM 中的第一张证书。
+func ParseCertificatePEM(data []byte) (*x509.Certificate, error) {
+	certs, err := parseCertificatesPEM(data)
+	if err != nil {
+		return nil, err
+	}
+	return certs[0], nil
+}
+
+func parseCertificatesPEM(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != pemTypeCertificate {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, ErrInvalidCertificate
+	}
+	return certs, nil
+}
+
+func caTemplate(subject pkix.Name, o *options) (*x509.Certificate, error) {
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               subject,
+		NotBefore:             o.notBefore,
+		NotAfter:              o.notBefore.Add(o.validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		ExtKeyUsage:           o.extKeyUsage,
+	}
+	if o.maxPathLen >= 0 {
+		tmpl.MaxPathLen = o.maxPathLen
+		tmpl.MaxPathLenZero = o.maxPathLen == 0
+	}
+	return tmpl, nil
+}
+
+func leafTemplate(pub crypto.PublicKey, subject pkix.Name, o *options) (*x509.Certificate, error) {
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+	keyUsage := x509.KeyUsageDigitalSignature
+	switch pub.(type) {
+	case *rsa.PublicKey:
+		// RSA 密钥在 TLS 1.2 的 RSA 密钥交换中还需要 KeyEncipherment。
+		keyUsage |= x509.KeyUsageKeyEncipherment
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+	default:
+		return nil, ErrInvalidKey
+	}
+	extKeyUsage := o.extKeyUsage
+	if len(extKeyUsage) == 0 {
+		extKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	}
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               subject,
+		NotBefore:             o.notBefore,
+		NotAfter:              o.notBefore.Add(o.validity),
+		KeyUsage:              keyUsage,
+		ExtKeyUsage:           extKeyUsage,
+		BasicConstraintsValid: true,
+		DNSNames:              o.dnsNames,
+		IPAddresses:           o.ipAddresses,
+		EmailAddresses:        o.emails,
+		URIs:                  o.uris,
+	}, nil
+}
+
+// clampValidity 把 tmpl 的 NotAfter 截断到本 CA 的 NotAfter：比签发者更长寿的证书会在
+// CA 过期后被验证方拒绝，截断让证书的实际可用期一目了然。
+func (ca *CA) clampValidity(tmpl *x509.Certificate) error {
+	if !tmpl.NotBefore.Before(ca.cert.NotAfter) {
+		return ErrIssuerExpired
+	}
+	if tmpl.NotAfter.After(ca.cert.NotAfter) {
+		tmpl.NotAfter = ca.cert.NotAfter
+	}
+	return nil
+}
+
+// randomSerial 返回 128 位随机正整数序列号（RFC 5280 要求不超过 20 字节）。
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	return serial.Add(serial, big.NewInt(1)), nil
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawIssuer, cert.RawSubject) && cert.CheckSignatureFrom(cert) == nil
+}
+
+func publicKeyEqual(a, b crypto.PublicKey) bool {
+	k, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && k.Equal(b)
+}
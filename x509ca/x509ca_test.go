@@ -0,0 +1,294 @@
+package x509ca_test
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gtkit/encry/ecdsa"
+	"github.com/gtkit/encry/ed"
+	"github.com/gtkit/encry/rsa"
+	"github.com/gtkit/encry/x509ca"
+	"github.com/stretchr/testify/require"
+)
+
+func newECDSAKey(t *testing.T) crypto.Signer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey()
+	require.NoError(t, err)
+	return key
+}
+
+func newCA(t *testing.T) *x509ca.CA {
+	t.Helper()
+	ca, err := x509ca.NewCA(newECDSAKey(t), pkix.Name{CommonName: "encry test root"})
+	require.NoError(t, err)
+	return ca
+}
+
+func TestSignCSRWithPackageKeys(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, _, err := rsa.GenerateKeyPair(2048)
+	require.NoError(t, err)
+	_, edKey, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+
+	ca := newCA(t)
+	tests := []struct {
+		name string
+		key  crypto.Signer
+		want x509.KeyUsage
+	}{
+		{"rsa", rsaKey, x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment},
+		{"ecdsa", newECDSAKey(t), x509.KeyUsageDigitalSignature},
+		{"ed25519", edKey, x509.KeyUsageDigitalSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			csrPEM, err := x509ca.CreateCSR(tt.key, pkix.Name{CommonName: tt.name + ".svc"},
+				x509ca.WithDNSNames(tt.name+".svc.local"),
+				x509ca.WithIPAddresses(net.ParseIP("127.0.0.1")),
+			)
+			require.NoError(t, err)
+
+			csr, err := x509ca.ParseCSRPEM(csrPEM)
+			require.NoError(t, err)
+			require.Equal(t, []string{tt.name + ".svc.local"}, csr.DNSNames)
+
+			certPEM, err := ca.SignCSR(csrPEM)
+			require.NoError(t, err)
+			cert, err := x509ca.ParseCertificatePEM(certPEM)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.name+".svc", cert.Subject.CommonName)
+			require.Equal(t, []string{tt.name + ".svc.local"}, cert.DNSNames)
+			require.Equal(t, tt.want, cert.KeyUsage)
+			require.True(t, cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }).Equal(tt.key.Public()))
+
+			_, err = cert.Verify(x509.VerifyOptions{Roots: ca.CertPool(), DNSName: tt.name + ".svc.local"})
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestSignCSROptionsOverride(t *testing.T) {
+	t.Parallel()
+
+	ca := newCA(t)
+	key := newECDSAKey(t)
+	csrPEM, err := x509ca.CreateCSR(key, pkix.Name{CommonName: "client"}, x509ca.WithDNSNames("requested.example"))
+	require.NoError(t, err)
+
+	spiffe, err := url.Parse("spiffe://example.org/ns/default/sa/client")
+	require.NoError(t, err)
+	notBefore := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	certPEM, err := ca.SignCSR(csrPEM,
+		x509ca.WithURIs(spiffe),
+		x509ca.WithExtKeyUsage(x509.ExtKeyUsageClientAuth),
+		x509ca.WithNotBefore(notBefore),
+		x509ca.WithValidity(2*time.Hour),
+	)
+	require.NoError(t, err)
+	cert, err := x509ca.ParseCertificatePEM(certPEM)
+	require.NoError(t, err)
+
+	// 设置了 SAN 选项时以签发方为准，CSR 申请的 SAN 被忽略。
+	require.Empty(t, cert.DNSNames)
+	require.Len(t, cert.URIs, 1)
+	require.Equal(t, spiffe.String(), cert.URIs[0].String())
+	require.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, cert.ExtKeyUsage)
+	require.True(t, cert.NotBefore.Equal(notBefore))
+	require.True(t, cert.NotAfter.Equal(notBefore.Add(2*time.Hour)))
+}
+
+func TestMutualTLSWithIntermediate(t *testing.T) {
+	t.Parallel()
+
+	root := newCA(t)
+	intermediate, err := root.NewIntermediate(newECDSAKey(t), pkix.Name{CommonName: "encry test intermediate"}, x509ca.WithMaxPathLen(0))
+	require.NoError(t, err)
+	require.True(t, intermediate.Certificate().MaxPathLenZero)
+
+	serverKey := newECDSAKey(t)
+	serverPEM, err := intermediate.Issue(serverKey.Public(), pkix.Name{CommonName: "server"}, x509ca.WithDNSNames("server.test"))
+	require.NoError(t, err)
+	serverCert, err := intermediate.TLSCertificate(serverPEM, serverKey)
+	require.NoError(t, err)
+	require.Len(t, serverCert.Certificate, 2) // leaf + intermediate，不含根
+
+	_, clientKey, err := ed.GenerateKeyPair()
+	require.NoError(t, err)
+	clientPEM, err := root.Issue(clientKey.Public(), pkix.Name{CommonName: "client"})
+	require.NoError(t, err)
+	clientCert, err := root.TLSCertificate(clientPEM, clientKey)
+	require.NoError(t, err)
+
+	serverConf := &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    root.CertPool(),
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS13,
+	}
+	clientConf := &tls.Config{
+		Certificates: []tls.Certificate{clientCert},
+		RootCAs:      root.CertPool(),
+		ServerName:   "server.test",
+		MinVersion:   tls.VersionTLS13,
+	}
+
+	c1, c2 := net.Pipe()
+	server := tls.Server(c1, serverConf)
+	client := tls.Client(c2, clientConf)
+	defer server.Close()
+	defer client.Close()
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.Handshake() }()
+	require.NoError(t, client.Handshake())
+	require.NoError(t, <-errc)
+
+	require.Equal(t, "client", server.ConnectionState().PeerCertificates[0].Subject.CommonName)
+	require.Equal(t, "server", client.ConnectionState().PeerCertificates[0].Subject.CommonName)
+}
+
+func TestLoadCA(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, _, err := rsa.GenerateKeyPair(2048)
+	require.NoError(t, err)
+	root, err := x509ca.NewCA(rsaKey, pkix.Name{CommonName: "root"})
+	require.NoError(t, err)
+
+	t.Run("pkcs1 key", func(t *testing.T) {
+		t.Parallel()
+		loaded, err := x509ca.LoadCA(root.CertificatePEM(), rsa.MarshalPKCS1PrivateKeyPEM(rsaKey))
+		require.NoError(t, err)
+		require.True(t, loaded.Certificate().Equal(root.Certificate()))
+
+		leafPEM, err := loaded.Issue(newECDSAKey(t).Public(), pkix.Name{CommonName: "leaf"})
+		require.NoError(t, err)
+		leaf, err := x509ca.ParseCertificatePEM(leafPEM)
+		require.NoError(t, err)
+		_, err = leaf.Verify(x509.VerifyOptions{Roots: root.CertPool()})
+		require.NoError(t, err)
+	})
+
+	t.Run("intermediate chain", func(t *testing.T) {
+		t.Parallel()
+		key := newECDSAKey(t)
+		intermediate, err := root.NewIntermediate(key, pkix.Name{CommonName: "intermediate"})
+		require.NoError(t, err)
+		keyPEM, err := x509ca.MarshalPrivateKeyPEM(key)
+		require.NoError(t, err)
+
+		certPEM := append(intermediate.CertificatePEM(), root.CertificatePEM()...)
+		loaded, err := x509ca.LoadCA(certPEM, keyPEM)
+		require.NoError(t, err)
+
+		chain := loaded.ChainPEM([]byte("-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----\n"))
+		var blocks int
+		for rest := chain; ; blocks++ {
+			var block *pem.Block
+			if block, rest = pem.Decode(rest); block == nil {
+				break
+			}
+		}
+		require.Equal(t, 2, blocks) // leaf + intermediate
+	})
+
+	t.Run("key mismatch", func(t *testing.T) {
+		t.Parallel()
+		otherPEM, err := x509ca.MarshalPrivateKeyPEM(newECDSAKey(t))
+		require.NoError(t, err)
+		_, err = x509ca.LoadCA(root.CertificatePEM(), otherPEM)
+		require.ErrorIs(t, err, x509ca.ErrKeyMismatch)
+	})
+
+	t.Run("leaf is not a CA", func(t *testing.T) {
+		t.Parallel()
+		key := newECDSAKey(t)
+		leafPEM, err := root.Issue(key.Public(), pkix.Name{CommonName: "leaf"})
+		require.NoError(t, err)
+		keyPEM, err := x509ca.MarshalPrivateKeyPEM(key)
+		require.NoError(t, err)
+		_, err = x509ca.LoadCA(leafPEM, keyPEM)
+		require.ErrorIs(t, err, x509ca.ErrInvalidCertificate)
+	})
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	ca := newCA(t)
+
+	_, err := x509ca.CreateCSR(nil, pkix.Name{})
+	require.ErrorIs(t, err, x509ca.ErrInvalidKey)
+	_, err = x509ca.NewCA(nil, pkix.Name{})
+	require.ErrorIs(t, err, x509ca.ErrInvalidKey)
+	_, err = ca.Issue(nil, pkix.Name{})
+	require.ErrorIs(t, err, x509ca.ErrInvalidKey)
+	_, err = ca.Issue("not a key", pkix.Name{})
+	require.ErrorIs(t, err, x509ca.ErrInvalidKey)
+
+	_, err = ca.SignCSR([]byte("not pem"))
+	require.ErrorIs(t, err, x509ca.ErrInvalidCSR)
+	_, err = x509ca.ParseCertificatePEM([]byte("not pem"))
+	require.ErrorIs(t, err, x509ca.ErrInvalidCertificate)
+	_, err = x509ca.ParsePrivateKeyPEM([]byte("not pem"))
+	require.ErrorIs(t, err, x509ca.ErrInvalidKey)
+	_, err = x509ca.MarshalPrivateKeyPEM(nil)
+	require.ErrorIs(t, err, x509ca.ErrInvalidKey)
+}
+
+func TestValidityCappedAtIssuer(t *testing.T) {
+	t.Parallel()
+
+	root, err := x509ca.NewCA(newECDSAKey(t), pkix.Name{CommonName: "short root"}, x509ca.WithValidity(24*time.Hour))
+	require.NoError(t, err)
+	rootNotAfter := root.Certificate().NotAfter
+
+	intermediate, err := root.NewIntermediate(newECDSAKey(t), pkix.Name{CommonName: "intermediate"})
+	require.NoError(t, err)
+	require.Equal(t, rootNotAfter, intermediate.Certificate().NotAfter)
+
+	leafKey := newECDSAKey(t)
+	leafPEM, err := intermediate.Issue(leafKey.Public(), pkix.Name{CommonName: "svc"}, x509ca.WithValidity(365*24*time.Hour))
+	require.NoError(t, err)
+	leaf, err := x509ca.ParseCertificatePEM(leafPEM)
+	require.NoError(t, err)
+	require.Equal(t, rootNotAfter, leaf.NotAfter)
+
+	csrPEM, err := x509ca.CreateCSR(leafKey, pkix.Name{CommonName: "csr"})
+	require.NoError(t, err)
+	leafPEM, err = root.SignCSR(csrPEM)
+	require.NoError(t, err)
+	leaf, err = x509ca.ParseCertificatePEM(leafPEM)
+	require.NoError(t, err)
+	require.Equal(t, rootNotAfter, leaf.NotAfter)
+
+	// 有效期短于签发 CA 时保持不变。
+	leafPEM, err = root.Issue(leafKey.Public(), pkix.Name{CommonName: "short"}, x509ca.WithValidity(time.Hour))
+	require.NoError(t, err)
+	leaf, err = x509ca.ParseCertificatePEM(leafPEM)
+	require.NoError(t, err)
+	require.Equal(t, leaf.NotBefore.Add(time.Hour), leaf.NotAfter)
+
+	// 生效时间不早于签发 CA 的过期时间时无法签发。
+	late := x509ca.WithNotBefore(rootNotAfter)
+	_, err = root.Issue(leafKey.Public(), pkix.Name{CommonName: "late"}, late)
+	require.ErrorIs(t, err, x509ca.ErrIssuerExpired)
+	_, err = root.SignCSR(csrPEM, late)
+	require.ErrorIs(t, err, x509ca.ErrIssuerExpired)
+	_, err = root.NewIntermediate(newECDSAKey(t), pkix.Name{CommonName: "late"}, late)
+	require.ErrorIs(t, err, x509ca.ErrIssuerExpired)
+}
@@ -0,0 +1,19 @@
+package xwing
+
+import (
+	"crypto/ecdh"
+	"crypto/mlkem"
+	"crypto/mlkem/mlkemtest"
+)
+
+// EncapsulateDerand 用 64 字节 eseed（ML-KEM 随机数 || X25519 临时私钥）确定性封装，
+// 对应规范中的 EncapsulateDerand，仅供向量测试。
+func EncapsulateDerand(pub *PublicKey, eseed []byte) (sharedSecret, ciphertext []byte, err error) {
+	ekX, err := ecdh.X25519().NewPrivateKey(eseed[32:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return encapsulate(pub, ekX, func(ek *mlkem.EncapsulationKey768) ([]byte, []byte, error) {
+		return mlkemtest.Encapsulate768(ek, eseed[:32])
+	})
+}
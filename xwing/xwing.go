@@ -0,0 +1,196 @@
+// Package xwing 提供混合后量子 KEM X-Wing（X25519 + ML-KEM-768，draft-connolly-cfrg-xwing-kem）。
+//
+// X-Wing 同时执行 ML-KEM-768 封装与 X25519 交换，再用 SHA3-256 把两份共享密钥、
+// X25519 密文与公钥一起组合成最终共享密钥：只要 ML-KEM 与 X25519 有一方未被攻破，
+// 共享密钥就保持安全。适合需要长期保密的数据（"现在截获、将来解密"）。
+//
+// 私钥是 32 字节种子，公钥 1216 字节，密文 1120 字节，共享密钥 32 字节。
+// 同一 KEM 也以 KEM ID 0x647a（MLKEM768-X25519）接入 hpke，见 hpke.SealPQ。
+package xwing
+
+import (
+	"crypto/ecdh"
+	"crypto/mlkem"
+	"crypto/rand"
+	"crypto/sha3"
+	"crypto/subtle"
+	"errors"
+)
+
+// 各字段长度。
+const (
+	SeedSize       = 32
+	PublicKeySize  = mlkem.EncapsulationKeySize768 + 32
+	CiphertextSize = mlkem.CiphertextSize768 + 32
+	SharedKeySize  = 32
+)
+
+// label 为 X-Wing 组合器的域分隔标签（ASCII 画的 \./ 与 /^\）。
+const label = `\.//^\`
+
+var (
+	// ErrInvalidPrivateKey 表示私钥种子长度不是 SeedSize。
+	ErrInvalidPrivateKey = errors.New("xwing: invalid private key")
+	// ErrInvalidPublicKey 表示公钥长度或 ML-KEM/X25519 部分非法。
+	ErrInvalidPublicKey = errors.New("xwing: invalid public key")
+	// ErrInvalidCiphertext 表示密文长度非法或 X25519 部分为小阶点。
+	// 注意 ML-KEM 部分的篡改不会报错（隐式拒绝），只会得到不同的共享密钥。
+	ErrInvalidCiphertext = errors.New("xwing: invalid ciphertext")
+)
+
+// PrivateKey 是 X-Wing 私钥，由 32 字节种子展开得到 ML-KEM-768 与 X25519 私钥。
+type PrivateKey struct {
+	seed []byte
+	dk   *mlkem.DecapsulationKey768
+	x    *ecdh.PrivateKey
+	pub  *PublicKey
+}
+
+// PublicKey 是 X-Wing 公钥：ML-KEM-768 封装公钥 || X25519 公钥。
+type PublicKey struct {
+	ek *mlkem.EncapsulationKey768
+	x  *ecdh.PublicKey
+}
+
+// GenerateKey 随机生成一把 X-Wing 私钥。
+func GenerateKey() (*PrivateKey, error) {
+	seed := make([]byte, SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, err
+	}
+	return NewPrivateKey(seed)
+}
+
+// NewPrivateKey 由 32 字节种子确定性展开私钥：SHAKE256(seed) 的前 64 字节为
+// ML-KEM-768 种子 (d || z)，随后 32 字节为 X25519 私钥。
+func NewPrivateKey(seed []byte) (*PrivateKey, error) {
+	if len(seed) != SeedSize {
+		return nil, ErrInvalidPrivateKey
+	}
+	expanded := sha3.SumSHAKE256(seed, mlkem.SeedSize+32)
+	defer clear(expanded)
+	dk, err := mlkem.NewDecapsulationKey768(expanded[:mlkem.SeedSize])
+	if err != nil {
+		return nil, err
+	}
+	x, err := ecdh.X25519().NewPrivateKey(expanded[mlkem.SeedSize:])
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{
+		seed: append([]byte(nil), seed...),
+		dk:   dk,
+		x:    x,
+		pub:  &PublicKey{ek: dk.EncapsulationKey(), x: x.PublicKey()},
+	}, nil
+}
+
+// Bytes 返回 32 字节私钥种子，须保密。
+func (k *PrivateKey) Bytes() []byte {
+	return append([]byte(nil), k.seed...)
+}
+
+// PublicKey 返回对应的公钥。
+func (k *PrivateKey) PublicKey() *PublicKey {
+	return k.pub
+}
+
+// Equal 以常量时间比较两把私钥是否相同。
+func (k *PrivateKey) Equal(other *PrivateKey) bool {
+	return other != nil && subtle.ConstantTimeCompare(k.seed, other.seed) == 1
+}
+
+// NewPublicKey 解析 1216 字节公钥，并校验 ML-KEM 封装公钥的合法性。
+func NewPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	ek, err := mlkem.NewEncapsulationKey768(b[:mlkem.EncapsulationKeySize768])
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	x, err := ecdh.X25519().NewPublicKey(b[mlkem.EncapsulationKeySize768:])
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return &PublicKey{ek: ek, x: x}, nil
+}
+
+// Bytes 返回 1216 字节公钥编码。
+func (k *PublicKey) Bytes() []byte {
+	return append(k.ek.Bytes(), k.x.Bytes()...)
+}
+
+// Equal 比较两把公钥是否相同。
+func (k *PublicKey) Equal(other *PublicKey) bool {
+	return other != nil && subtle.ConstantTimeCompare(k.Bytes(), other.Bytes()) == 1
+}
+
+// Encapsulate 用接收方公钥产生 32 字节共享密钥及 1120 字节密文，把密文发给接收方。
+func Encapsulate(pub *PublicKey) (sharedSecret, ciphertext []byte, err error) {
+	if pub == nil {
+		return nil, nil, ErrInvalidPublicKey
+	}
+	ekX, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encapsulate(pub, ekX, func(ek *mlkem.EncapsulationKey768) ([]byte, []byte, error) {
+		ss, ct := ek.Encapsulate()
+		return ss, ct, nil
+	})
+}
+
+// encapsulate 为 Encapsulate 的内核；ML-KEM 封装与 X25519 临时私钥由调用方提供，
+// 测试借此复现规范向量。
+func encapsulate(pub *PublicKey, ekX *ecdh.PrivateKey, encapM func(*mlkem.EncapsulationKey768) ([]byte, []byte, error)) (sharedSecret, ciphertext []byte, err error) {
+	ssX, err := ekX.ECDH(pub.x)
+	if err != nil {
+		return nil, nil, ErrInvalidPublicKey
+	}
+	defer clear(ssX)
+	ssM, ctM, err := encapM(pub.ek)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer clear(ssM)
+	ctX := ekX.PublicKey().Bytes()
+	return combine(ssM, ssX, ctX, pub.x.Bytes()), append(ctM, ctX...), nil
+}
+
+// Decapsulate 用私钥从密文还原共享密钥。
+func Decapsulate(priv *PrivateKey, ciphertext []byte) ([]byte, error) {
+	if priv == nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	if len(ciphertext) != CiphertextSize {
+		return nil, ErrInvalidCiphertext
+	}
+	ctM, ctX := ciphertext[:mlkem.CiphertextSize768], ciphertext[mlkem.CiphertextSize768:]
+	ssM, err := priv.dk.Decapsulate(ctM)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	defer clear(ssM)
+	pkE, err := ecdh.X25519().NewPublicKey(ctX)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	ssX, err := priv.x.ECDH(pkE)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	defer clear(ssX)
+	return combine(ssM, ssX, ctX, priv.pub.x.Bytes()), nil
+}
+
+// combine 即 X-Wing 组合器 SHA3-256(ss_M || ss_X || ct_X || pk_X || label)。
+func combine(ssM, ssX, ctX, pkX []byte) []byte {
+	h := sha3.New256()
+	h.Write(ssM)
+	h.Write(ssX)
+	h.Write(ctX)
+	h.Write(pkX)
+	h.Write([]byte(label))
+	return h.Sum(nil)
+}
@@ -0,0 +1,160 @@
+package xwing_test
+
+import (
+	"bytes"
+	"crypto/hpke"
+	"crypto/sha3"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gtkit/encry/xwing"
+	"github.com/stretchr/testify/require"
+)
+
+// writeHex 按 X-Wing 规范 test-vectors.txt 的排版输出一个字段。
+func writeHex(w *strings.Builder, name string, val []byte) {
+	const indent, width = "  ", 74
+	h := fmt.Sprintf("%x", val)
+	if len(name)+len(h)+5 < width {
+		fmt.Fprintf(w, "%s     %s\n", name, h)
+		return
+	}
+	fmt.Fprintf(w, "%s\n", name)
+	for len(h) > 0 {
+		n := min(len(h), width-len(indent))
+		fmt.Fprintf(w, "%s%s\n", indent, h[:n])
+		h = h[n:]
+	}
+}
+
+// TestSpecVectors 复现规范仓库 spec/test-vectors.txt：种子与 eseed 取自 SHAKE128 空输入流，
+// 生成的文本整体再做 SHAKE128 与规范文件的摘要比对（与 CIRCL 的校验方式相同）。
+func TestSpecVectors(t *testing.T) {
+	t.Parallel()
+	stream := sha3.NewSHAKE128()
+	var w strings.Builder
+	for range 3 {
+		seed := make([]byte, xwing.SeedSize)
+		_, _ = stream.Read(seed)
+		writeHex(&w, "seed", seed)
+
+		priv, err := xwing.NewPrivateKey(seed)
+		require.NoError(t, err)
+		writeHex(&w, "sk", priv.Bytes())
+		writeHex(&w, "pk", priv.PublicKey().Bytes())
+
+		eseed := make([]byte, 64)
+		_, _ = stream.Read(eseed)
+		writeHex(&w, "eseed", eseed)
+
+		ss, ct, err := xwing.EncapsulateDerand(priv.PublicKey(), eseed)
+		require.NoError(t, err)
+		writeHex(&w, "ct", ct)
+		writeHex(&w, "ss", ss)
+
+		got, err := xwing.Decapsulate(priv, ct)
+		require.NoError(t, err)
+		require.Equal(t, ss, got)
+		w.WriteString("\n")
+	}
+	sum := sha3.SumSHAKE128([]byte(w.String()), 32)
+	require.Equal(t, "1bcd0057d861d6b866239936cadcaeee1ec0164dedc181c386e9e54fe46156fe", fmt.Sprintf("%x", sum))
+}
+
+func TestEncapsulateDecapsulateRoundTrip(t *testing.T) {
+	t.Parallel()
+	priv, err := xwing.GenerateKey()
+	require.NoError(t, err)
+	pub, err := xwing.NewPublicKey(priv.PublicKey().Bytes())
+	require.NoError(t, err)
+	require.True(t, pub.Equal(priv.PublicKey()))
+	require.Len(t, pub.Bytes(), xwing.PublicKeySize)
+
+	ss1, ct, err := xwing.Encapsulate(pub)
+	require.NoError(t, err)
+	require.Len(t, ss1, xwing.SharedKeySize)
+	require.Len(t, ct, xwing.CiphertextSize)
+	ss2, err := xwing.Decapsulate(priv, ct)
+	require.NoError(t, err)
+	require.Equal(t, ss1, ss2)
+
+	restored, err := xwing.NewPrivateKey(priv.Bytes())
+	require.NoError(t, err)
+	require.True(t, restored.Equal(priv))
+	ss3, err := xwing.Decapsulate(restored, ct)
+	require.NoError(t, err)
+	require.Equal(t, ss1, ss3)
+}
+
+// TestMatchesStdlibHPKEKey 与标准库 crypto/hpke 的 MLKEM768X25519 互相校验密钥展开与封装。
+func TestMatchesStdlibHPKEKey(t *testing.T) {
+	t.Parallel()
+	priv, err := xwing.GenerateKey()
+	require.NoError(t, err)
+	std, err := hpke.MLKEM768X25519().NewPrivateKey(priv.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, priv.PublicKey().Bytes(), std.PublicKey().Bytes())
+}
+
+func TestDecapsulateTampered(t *testing.T) {
+	t.Parallel()
+	priv, err := xwing.GenerateKey()
+	require.NoError(t, err)
+	ss, ct, err := xwing.Encapsulate(priv.PublicKey())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		pos  int
+	}{
+		{"mlkem part", 0},
+		{"x25519 part", xwing.CiphertextSize - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tampered := bytes.Clone(ct)
+			tampered[tt.pos] ^= 0x01
+			got, err := xwing.Decapsulate(priv, tampered)
+			require.NoError(t, err)
+			require.NotEqual(t, ss, got)
+		})
+	}
+}
+
+func TestInvalidInputs(t *testing.T) {
+	t.Parallel()
+	priv, err := xwing.GenerateKey()
+	require.NoError(t, err)
+
+	_, err = xwing.NewPrivateKey(make([]byte, 31))
+	require.ErrorIs(t, err, xwing.ErrInvalidPrivateKey)
+	_, err = xwing.NewPublicKey(priv.PublicKey().Bytes()[1:])
+	require.ErrorIs(t, err, xwing.ErrInvalidPublicKey)
+	// ML-KEM 公钥系数超出模数。
+	bad := priv.PublicKey().Bytes()
+	for i := range 384 {
+		bad[i] = 0xff
+	}
+	_, err = xwing.NewPublicKey(bad)
+	require.ErrorIs(t, err, xwing.ErrInvalidPublicKey)
+
+	_, err = xwing.Decapsulate(priv, make([]byte, xwing.CiphertextSize-1))
+	require.ErrorIs(t, err, xwing.ErrInvalidCiphertext)
+	// X25519 部分为零点（小阶）。
+	_, err = xwing.Decapsulate(priv, make([]byte, xwing.CiphertextSize))
+	require.ErrorIs(t, err, xwing.ErrInvalidCiphertext)
+	_, _, err = xwing.Encapsulate(nil)
+	require.ErrorIs(t, err, xwing.ErrInvalidPublicKey)
+}
+
+func ExampleEncapsulate() {
+	priv, _ := xwing.GenerateKey()
+
+	// 发送方只需公钥（1216 字节）。
+	ss1, ct, _ := xwing.Encapsulate(priv.PublicKey())
+	ss2, _ := xwing.Decapsulate(priv, ct)
+	fmt.Println(len(ct), bytes.Equal(ss1, ss2))
+	// Output: 1120 true
+}